@@ -40,3 +40,10 @@ func FormatNumber(n int) string {
 	return fmt.Sprintf("%.1fM", millions)
 }
 
+
+// FormatGameTime converts seconds of game time to minutes and seconds
+// Examples: 95 -> "1:35", 1420 -> "23:40"
+func FormatGameTime(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
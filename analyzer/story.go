@@ -0,0 +1,364 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Story arcs, the shape of the whole game
+const (
+	ArcComeback     = "comeback"       // We won from behind
+	ArcThrow        = "throw"          // We lost after being well ahead
+	ArcBackAndForth = "back-and-forth" // The lead changed hands more than once
+	ArcStomp        = "stomp"          // The winners were never behind and got far ahead
+	ArcClose        = "close"          // Neither team ever got far ahead
+	ArcSteady       = "steady"         // One team built a lead and kept it
+)
+
+// Story phases
+const (
+	PhaseEven         = "even"
+	PhaseEarlyLead    = "early lead"
+	PhaseEarlyDeficit = "early deficit"
+	PhaseTookLead     = "took the lead"
+	PhaseFellBehind   = "fell behind"
+	PhaseThrow        = "throw"
+	PhaseComeback     = "comeback"
+	PhaseBackAndForth = "back-and-forth"
+)
+
+const (
+	storyMinPhaseFrames = 2    // Shorter stretches are folded into the phase before
+	storyEarlyMinutes   = 15   // A lead taken before this is an early lead
+	stompGoldLead       = 8000 // The winners' lead at some point in a stomp
+	closeGoldLead       = 2500 // Neither team got further ahead than this in a close game
+	decisiveBefore      = 60   // Seconds before the winners took the lead for good that still count
+	decisiveAfter       = 180  // and seconds after
+)
+
+// GameStory is what happened in the game, from our side, for messages to reference truthfully
+type GameStory struct {
+	Arc      string       `json:"arc"`
+	Phases   []StoryPhase `json:"phases"`
+	Decisive *StoryMoment `json:"decisive,omitempty"` // The teamfight or objective that decided the game
+	Summary  string       `json:"summary"`            // A few sentences, e.g. "We fell behind early (down 2.5k gold), then came back at 21:00..."
+	Source   string       `json:"source"`             // "live" or "matchHistory"
+}
+
+// StoryPhase is a stretch of the game where the gold lead had one shape
+type StoryPhase struct {
+	Label      string `json:"label"`
+	From       string `json:"from"` // Game time, e.g. "12:00"
+	To         string `json:"to"`
+	GoldLead   int    `json:"goldLead"` // Our lead at the end of the phase (negative = behind)
+	PeakLead   int    `json:"peakLead"` // Our biggest lead, or deficit in phases behind
+	OurKills   int    `json:"ourKills"`
+	TheirKills int    `json:"theirKills"`
+}
+
+// StoryMoment is the teamfight or objective that decided the game
+type StoryMoment struct {
+	Time        string `json:"time"`   // Game time, e.g. "27:10"
+	Kind        string `json:"kind"`   // "teamfight" or "objective"
+	Winner      string `json:"winner"` // "us" or "them"
+	Description string `json:"description"`
+}
+
+// span is a run of frames with the same leader ("us", "them" or "" when even)
+type span struct {
+	leader      string
+	first, last int // Frame indexes
+}
+
+// tellStory labels the phases of the game, finds the moment that decided it, and sets the
+// story flags on the summary to match
+func tellStory(summary *GameSummary, timeline *gameTimeline) *GameStory {
+	story := &GameStory{Source: timeline.source}
+	story.Phases = timeline.phases()
+	story.Decisive = timeline.decisiveMoment(summary.MyTeamWon())
+	story.Arc = storyArc(summary)
+
+	summary.WasStomp = story.Arc == ArcStomp
+	summary.WasClose = story.Arc == ArcClose || story.Arc == ArcBackAndForth
+	if story.Arc == ArcThrow || story.Arc == ArcBackAndForth {
+		summary.IsIntenseMatch = true
+	}
+
+	story.Summary = story.tell(summary)
+	return story
+}
+
+// storyArc picks the arc of the game from the gold leads on the summary
+func storyArc(summary *GameSummary) string {
+	won := summary.MyTeamWon()
+	winnerLead, winnerDeficit := summary.LargestGoldLead, summary.LargestGoldDeficit
+	if !won {
+		winnerLead, winnerDeficit = winnerDeficit, winnerLead
+	}
+
+	switch {
+	case summary.IsComeback:
+		return ArcComeback
+	case !won && (summary.GoldDiffAt15 >= comebackGoldDeficit || summary.LargestGoldLead >= comebackMaxDeficit):
+		return ArcThrow
+	case summary.LeadSwings >= 2:
+		return ArcBackAndForth
+	case max(summary.LargestGoldLead, summary.LargestGoldDeficit) < closeGoldLead:
+		return ArcClose
+	case winnerDeficit < leadGold && winnerLead >= stompGoldLead:
+		return ArcStomp
+	}
+	return ArcSteady
+}
+
+// phases splits the game into stretches by who was ahead and labels them
+func (t *gameTimeline) phases() []StoryPhase {
+	var spans []span
+	for i, frame := range t.frames {
+		leader := leaderOf(frame.goldLead)
+		if len(spans) > 0 && spans[len(spans)-1].leader == leader {
+			spans[len(spans)-1].last = i
+			continue
+		}
+		spans = append(spans, span{leader: leader, first: i, last: i})
+	}
+
+	// Fold short stretches into the one before, so a lead held for a minute isn't a phase
+	var folded []span
+	for _, s := range spans {
+		switch {
+		case len(folded) == 0:
+			folded = append(folded, s)
+		case s.last-s.first+1 < storyMinPhaseFrames, folded[len(folded)-1].leader == s.leader:
+			folded[len(folded)-1].last = s.last
+		default:
+			folded = append(folded, s)
+		}
+	}
+
+	var phases []StoryPhase
+	previous := "" // The last team that was ahead
+	for i, s := range folded {
+		phase := t.phase(s, i == 0)
+		early := t.frames[s.first].time < storyEarlyMinutes*60
+		switch s.leader {
+		case "us":
+			switch {
+			case previous == "them":
+				phase.Label = PhaseComeback
+			case previous == "" && early:
+				phase.Label = PhaseEarlyLead
+			default:
+				phase.Label = PhaseTookLead
+			}
+		case "them":
+			switch {
+			case previous == "us":
+				phase.Label = PhaseThrow
+			case previous == "" && early:
+				phase.Label = PhaseEarlyDeficit
+			default:
+				phase.Label = PhaseFellBehind
+			}
+		default:
+			phase.Label = PhaseEven
+		}
+		if s.leader != "" {
+			previous = s.leader
+		}
+		phases = append(phases, phase)
+	}
+	for i := range phases[:max(len(phases)-1, 0)] {
+		phases[i].To = phases[i+1].From
+	}
+	return mergeBackAndForth(phases)
+}
+
+// phase fills in the times, gold and kills of a span
+func (t *gameTimeline) phase(s span, first bool) StoryPhase {
+	start, end := t.frames[s.first], t.frames[s.last]
+	phase := StoryPhase{
+		From:     FormatGameTime(start.time),
+		To:       FormatGameTime(end.time),
+		GoldLead: end.goldLead,
+	}
+	if first {
+		phase.From = FormatGameTime(0)
+	}
+
+	before := minuteFrame{}
+	if s.first > 0 {
+		before = t.frames[s.first-1]
+	}
+	phase.OurKills = end.ourKills - before.ourKills
+	phase.TheirKills = end.theirKills - before.theirKills
+
+	for _, frame := range t.frames[s.first : s.last+1] {
+		switch s.leader {
+		case "us":
+			phase.PeakLead = max(phase.PeakLead, frame.goldLead)
+		case "them":
+			phase.PeakLead = min(phase.PeakLead, frame.goldLead)
+		default:
+			if abs(frame.goldLead) > abs(phase.PeakLead) {
+				phase.PeakLead = frame.goldLead
+			}
+		}
+	}
+	return phase
+}
+
+// mergeBackAndForth turns two or more swings of the lead in a row (with even stretches between)
+// into one back-and-forth phase
+func mergeBackAndForth(phases []StoryPhase) []StoryPhase {
+	isSwing := func(p StoryPhase) bool { return p.Label == PhaseComeback || p.Label == PhaseThrow }
+
+	var merged []StoryPhase
+	for i := 0; i < len(phases); i++ {
+		if !isSwing(phases[i]) {
+			merged = append(merged, phases[i])
+			continue
+		}
+
+		// Find the last swing in this run
+		last, swings := i, 1
+		for j := i + 1; j < len(phases); j++ {
+			if isSwing(phases[j]) {
+				last, swings = j, swings+1
+			} else if phases[j].Label != PhaseEven {
+				break
+			}
+		}
+		if swings < 2 {
+			merged = append(merged, phases[i])
+			continue
+		}
+
+		phase := StoryPhase{Label: PhaseBackAndForth, From: phases[i].From, To: phases[last].To, GoldLead: phases[last].GoldLead}
+		for _, p := range phases[i : last+1] {
+			phase.OurKills += p.OurKills
+			phase.TheirKills += p.TheirKills
+			if abs(p.PeakLead) > abs(phase.PeakLead) {
+				phase.PeakLead = p.PeakLead
+			}
+		}
+		merged = append(merged, phase)
+		i = last
+	}
+	return merged
+}
+
+// decisiveMoment returns the biggest teamfight or objective the winners won around the time
+// they took the lead for good (nil if there is none)
+func (t *gameTimeline) decisiveMoment(won bool) *StoryMoment {
+	winner, subject := "them", "They"
+	if won {
+		winner, subject = "us", "We"
+	}
+
+	// When the winners went ahead for the last time
+	lockedIn := 0.0
+	for _, frame := range t.frames {
+		if leaderOf(frame.goldLead) != winner {
+			lockedIn = frame.time
+		}
+	}
+
+	var best *StoryMoment
+	bestScore := 0
+	consider := func(moment *StoryMoment, score int, inWindow bool) {
+		if !inWindow {
+			score -= 100 // Only used when nothing happened around the time the game was decided
+		}
+		if best == nil || score > bestScore {
+			best, bestScore = moment, score
+		}
+	}
+	inWindow := func(at float64) bool {
+		return at >= lockedIn-decisiveBefore && at <= lockedIn+decisiveAfter
+	}
+
+	for _, fight := range t.teamfights() {
+		won, lost := fight.ourKills, fight.theirKills
+		if winner == "them" {
+			won, lost = lost, won
+		}
+		if won <= lost || fight.end < lockedIn-decisiveBefore {
+			continue
+		}
+		objective, rank := t.objectiveAfter(fight, winner == "us")
+		moment := &StoryMoment{
+			Time:        FormatGameTime(fight.start),
+			Kind:        "teamfight",
+			Winner:      winner,
+			Description: fmt.Sprintf("%s won a %d-for-%d fight at %s", subject, won, lost, FormatGameTime(fight.start)),
+		}
+		if objective != "" {
+			moment.Description += " and took " + objective
+		}
+		consider(moment, rank*10+5+won-lost, inWindow(fight.start))
+	}
+
+	for _, event := range t.events {
+		if event.rank < objectiveRankDragon || event.ours != (winner == "us") || event.time < lockedIn-decisiveBefore {
+			continue
+		}
+		moment := &StoryMoment{
+			Time:        FormatGameTime(event.time),
+			Kind:        "objective",
+			Winner:      winner,
+			Description: fmt.Sprintf("%s took %s at %s", subject, event.name, FormatGameTime(event.time)),
+		}
+		consider(moment, event.rank*10, inWindow(event.time))
+	}
+	return best
+}
+
+// tell writes the story out in a few sentences
+func (s *GameStory) tell(summary *GameSummary) string {
+	var clauses []string
+	for i, phase := range s.Phases {
+		var clause string
+		switch phase.Label {
+		case PhaseEarlyLead:
+			clause = fmt.Sprintf("we got ahead early (up %s gold)", FormatNumber(phase.PeakLead))
+		case PhaseEarlyDeficit:
+			clause = fmt.Sprintf("we fell behind early (down %s gold)", FormatNumber(-phase.PeakLead))
+		case PhaseTookLead:
+			clause = fmt.Sprintf("we took the lead at %s (up %s gold)", phase.From, FormatNumber(phase.PeakLead))
+		case PhaseFellBehind:
+			clause = fmt.Sprintf("we fell behind at %s (down %s gold)", phase.From, FormatNumber(-phase.PeakLead))
+		case PhaseThrow:
+			clause = fmt.Sprintf("we lost our lead at %s (down %s gold)", phase.From, FormatNumber(-phase.PeakLead))
+		case PhaseComeback:
+			clause = fmt.Sprintf("we came back at %s (up %s gold)", phase.From, FormatNumber(phase.PeakLead))
+		case PhaseBackAndForth:
+			clause = fmt.Sprintf("the lead went back and forth from %s to %s", phase.From, phase.To)
+		default:
+			if i == 0 && len(s.Phases) > 1 && (s.Phases[1].Label == PhaseEarlyLead || s.Phases[1].Label == PhaseEarlyDeficit) {
+				continue // "We got ahead early" says it
+			}
+			if i == 0 {
+				clause = fmt.Sprintf("it was even until %s", phase.To)
+			} else {
+				clause = fmt.Sprintf("it evened out at %s", phase.From)
+			}
+		}
+		clauses = append(clauses, clause)
+	}
+
+	var b strings.Builder
+	if len(clauses) > 0 {
+		text := strings.Join(clauses, ", then ")
+		b.WriteString(strings.ToUpper(text[:1]) + text[1:] + ". ")
+	}
+	if s.Decisive != nil {
+		b.WriteString("The deciding moment: " + s.Decisive.Description + ". ")
+	}
+	result := "They"
+	if summary.MyTeamWon() {
+		result = "We"
+	}
+	fmt.Fprintf(&b, "%s won at %s.", result, FormatGameTime(summary.GameDurationMinutes*60))
+	return b.String()
+}
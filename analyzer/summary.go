@@ -132,6 +132,7 @@ type GameSummary struct {
 	// Game mode information
 	GameMode            string          `json:"gameMode,omitempty"` // e.g., "ARAM", "CLASSIC"
 	QueueType           string          `json:"queueType,omitempty"` // e.g., "ARAM", "RANKED_SOLO_5x5"
+	Profile             string          `json:"profile,omitempty"`   // Config profile used for this game
 	GameType            string          `json:"gameType,omitempty"` // e.g., "MATCHED_GAME"
 	
 	// Match intensity indicators
@@ -151,10 +152,34 @@ type GameSummary struct {
 	HadClutchMoments     bool            `json:"hadClutchMoments,omitempty"`      // Indicators of clutch plays
 	TeamworkHighlight    string          `json:"teamworkHighlight,omitempty"`     // Key teamwork moment
 	
+	// Timeline insights (only with a live recording or match-history timeline)
+	HasTimeline          bool            `json:"hasTimeline,omitempty"`
+	GoldDiffAt15         int             `json:"goldDiffAt15,omitempty"`         // Our team's item gold lead at 15 minutes (negative = behind)
+	LargestGoldLead      int             `json:"largestGoldLead,omitempty"`      // Most our team was ahead at any minute
+	LargestGoldDeficit   int             `json:"largestGoldDeficit,omitempty"`   // Most our team was behind at any minute
+	LeadSwings           int             `json:"leadSwings,omitempty"`           // Times the gold lead changed hands
+	TurningPoints        []TurningPoint  `json:"turningPoints,omitempty"`        // Teamfights that swung the game
+	Story                *GameStory      `json:"story,omitempty"`                // Phases of the game and the moment that decided it
+	
 	// Explicit achievements - LLM should use these directly, not calculate
 	Achievements        GameAchievements `json:"achievements,omitempty"`
 }
 
+// Me returns our own player summary, or nil if we weren't found in the game
+func (s *GameSummary) Me() *PlayerSummary {
+	for i := range s.Players {
+		if s.Players[i].SummonerName == s.MySummonerName {
+			return &s.Players[i]
+		}
+	}
+	return nil
+}
+
+// MyTeamWon reports whether our team won the game
+func (s *GameSummary) MyTeamWon() bool {
+	return s.MyTeam != "" && s.MyTeam == s.WinningTeam
+}
+
 func AnalyzeGame(stats *eog.EoGStatsBlock, cfg *config.Config) (*GameSummary, error) {
 	if len(stats.Participants) == 0 {
 		return nil, nil
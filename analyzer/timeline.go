@@ -0,0 +1,500 @@
+package analyzer
+
+import (
+	"fmt"
+	"lol-kind-bot/eog"
+	"lol-kind-bot/lcu"
+	"lol-kind-bot/monitor"
+	"sort"
+	"strconv"
+)
+
+const (
+	comebackGoldDeficit = 1500 // Behind by this much at 15 minutes and still won
+	comebackMaxDeficit  = 4000 // Or behind by this much at any point and still won
+	leadGold            = 1000 // Smaller gold leads count as even when counting lead swings
+	teamfightGapSeconds = 15   // Kills further apart than this are separate fights
+	teamfightMinKills   = 3
+	maxTurningPoints    = 3
+)
+
+// Timeline sources
+const (
+	TimelineSourceLive         = "live"
+	TimelineSourceMatchHistory = "matchHistory"
+)
+
+// TurningPoint is a teamfight that swung the game
+type TurningPoint struct {
+	Time        string `json:"time"`   // Game time the fight started, e.g. "23:40"
+	Winner      string `json:"winner"` // "us" or "them"
+	OurKills    int    `json:"ourKills"`
+	TheirKills  int    `json:"theirKills"`
+	Objective   string `json:"objective,omitempty"` // Taken by the winners right after, e.g. "Baron"
+	GoldSwing   int    `json:"goldSwing"`           // Change in our gold lead from before the fight to a minute after it
+	Description string `json:"description"`         // e.g. "We won a 4-for-1 fight at 23:40 and took Baron"
+}
+
+// gameTimeline is a game minute by minute from our side, whichever source it came from
+type gameTimeline struct {
+	source string
+	frames []minuteFrame
+	events []gameEvent // Kills and objectives, in game order
+}
+
+// minuteFrame is the score at one point of the game
+type minuteFrame struct {
+	time       float64 // Seconds of game time
+	goldLead   int     // Our team's gold minus theirs
+	ourKills   int
+	theirKills int
+}
+
+// gameEvent is a champion kill (rank 0) or an objective taken
+type gameEvent struct {
+	time float64
+	ours bool   // Taken by our team
+	name string // Objective, e.g. "Baron", "the Infernal Drake"
+	rank int    // Objective rank, 0 for kills
+}
+
+// teamfight is a run of champion kills close together
+type teamfight struct {
+	start, end float64
+	ourKills   int
+	theirKills int
+}
+
+// Objective ranks, so the biggest objective taken after a fight is the one mentioned
+const (
+	objectiveRankTower = iota + 1
+	objectiveRankHerald
+	objectiveRankDragon
+	objectiveRankInhibitor
+	objectiveRankBaron
+)
+
+// IntegrateTimeline replaces the comeback guess from final stats with what the live recording
+// shows, and adds the gold leads, turning points and the game's story
+func IntegrateTimeline(summary *GameSummary, timeline *monitor.Timeline) {
+	if summary == nil || timeline == nil || len(timeline.Frames) == 0 {
+		return
+	}
+
+	ours := timeline.MyTeam
+	if ours == "" {
+		ours = liveTeam(summary.MyTeam)
+	}
+	if ours == "" {
+		return
+	}
+	integrateTimeline(summary, liveTimeline(timeline, ours))
+}
+
+// IntegrateMatchTimeline is IntegrateTimeline for a match-history timeline. teams maps
+// participant IDs to team IDs; without it participants 1-5 are taken to be blue side.
+func IntegrateMatchTimeline(summary *GameSummary, timeline *lcu.GameTimeline, teams map[int]int) {
+	if summary == nil || timeline == nil || len(timeline.Frames) == 0 {
+		return
+	}
+
+	ours := eog.TeamIDBlue
+	switch summary.MyTeam {
+	case eog.TeamIDToSide(eog.TeamIDBlue):
+	case eog.TeamIDToSide(eog.TeamIDRed):
+		ours = eog.TeamIDRed
+	default:
+		return
+	}
+	integrateTimeline(summary, matchTimeline(timeline, ours, teams))
+}
+
+func integrateTimeline(summary *GameSummary, timeline *gameTimeline) {
+	if len(timeline.frames) == 0 {
+		return
+	}
+
+	summary.HasTimeline = true
+	summary.LargestGoldLead, summary.LargestGoldDeficit = 0, 0
+	leader := ""
+	summary.LeadSwings = 0
+	for _, frame := range timeline.frames {
+		summary.LargestGoldLead = max(summary.LargestGoldLead, frame.goldLead)
+		summary.LargestGoldDeficit = max(summary.LargestGoldDeficit, -frame.goldLead)
+
+		current := leaderOf(frame.goldLead)
+		if current == "" {
+			continue
+		}
+		if leader != "" && current != leader {
+			summary.LeadSwings++
+		}
+		leader = current
+	}
+
+	behindAt15 := false
+	summary.GoldDiffAt15 = 0
+	if frame := timeline.inMinute(15); frame != nil {
+		summary.GoldDiffAt15 = frame.goldLead
+		behindAt15 = summary.GoldDiffAt15 <= -comebackGoldDeficit
+	}
+
+	summary.IsComeback = summary.MyTeamWon() && (behindAt15 || summary.LargestGoldDeficit >= comebackMaxDeficit)
+	if summary.IsComeback {
+		summary.IsIntenseMatch = true
+		summary.HadClutchMoments = true
+	}
+	if summary.LeadSwings >= 2 {
+		summary.IsIntenseMatch = true
+	}
+
+	summary.TurningPoints = turningPoints(timeline)
+	summary.Story = tellStory(summary, timeline)
+}
+
+// liveTimeline converts a live recording. Gold is the value of each team's items.
+func liveTimeline(timeline *monitor.Timeline, ours string) *gameTimeline {
+	converted := &gameTimeline{source: TimelineSourceLive}
+	for i := range timeline.Frames {
+		frame := &timeline.Frames[i]
+		gold := frame.TeamGold()
+		mf := minuteFrame{time: frame.Time, goldLead: gold[ours] - gold[otherLiveTeam(ours)]}
+		for _, p := range frame.Players {
+			if p.Team == ours {
+				mf.ourKills += p.Kills
+			} else {
+				mf.theirKills += p.Kills
+			}
+		}
+		converted.frames = append(converted.frames, mf)
+	}
+
+	for _, event := range timeline.Events {
+		if event.Team == "" {
+			continue
+		}
+		ge := gameEvent{time: event.Time, ours: event.Team == ours}
+		switch event.Name {
+		case lcu.LiveEventChampionKill:
+		case lcu.LiveEventBaronKill:
+			ge.name, ge.rank = "Baron", objectiveRankBaron
+		case lcu.LiveEventDragonKill:
+			ge.name, ge.rank = dragonName(event.Detail), objectiveRankDragon
+			if event.Detail == "Elder" {
+				ge.rank = objectiveRankBaron
+			}
+		case lcu.LiveEventInhibKilled:
+			ge.name, ge.rank = "an inhibitor", objectiveRankInhibitor
+		case lcu.LiveEventHeraldKill:
+			ge.name, ge.rank = "Rift Herald", objectiveRankHerald
+		case lcu.LiveEventTurretKilled:
+			ge.name, ge.rank = "a tower", objectiveRankTower
+		default:
+			continue
+		}
+		converted.events = append(converted.events, ge)
+	}
+	return converted
+}
+
+// matchTimeline converts a match-history timeline. Gold is each team's total gold earned.
+func matchTimeline(timeline *lcu.GameTimeline, ours int, teams map[int]int) *gameTimeline {
+	teamOf := func(participantID int) int {
+		if team, ok := teams[participantID]; ok {
+			return team
+		}
+		if participantID >= 1 && participantID <= 5 {
+			return eog.TeamIDBlue
+		}
+		if participantID >= 6 && participantID <= 10 {
+			return eog.TeamIDRed
+		}
+		return 0
+	}
+
+	converted := &gameTimeline{source: TimelineSourceMatchHistory}
+	ourKills, theirKills := 0, 0
+	for _, frame := range timeline.Frames {
+		for _, event := range frame.Events {
+			ge := gameEvent{time: float64(event.Timestamp) / 1000}
+			team := teamOf(event.KillerID)
+			switch event.Type {
+			case lcu.TimelineChampionKill:
+				if team == 0 {
+					// Executed by a turret or minion: the kill goes to the victim's enemies
+					if victimTeam := teamOf(event.VictimID); victimTeam != 0 {
+						team = eog.TeamIDBlue + eog.TeamIDRed - victimTeam
+					}
+				}
+				if team == ours {
+					ourKills++
+				} else if team != 0 {
+					theirKills++
+				}
+			case lcu.TimelineEliteMonsterKill:
+				switch event.MonsterType {
+				case "BARON_NASHOR":
+					ge.name, ge.rank = "Baron", objectiveRankBaron
+				case "DRAGON":
+					dragonType := matchDragonTypes[event.MonsterSubType]
+					ge.name, ge.rank = dragonName(dragonType), objectiveRankDragon
+					if dragonType == "Elder" {
+						ge.rank = objectiveRankBaron
+					}
+				case "RIFTHERALD":
+					ge.name, ge.rank = "Rift Herald", objectiveRankHerald
+				default:
+					continue
+				}
+			case lcu.TimelineBuildingKill:
+				// The event names the team that lost the building
+				if event.TeamID == eog.TeamIDBlue || event.TeamID == eog.TeamIDRed {
+					team = eog.TeamIDBlue + eog.TeamIDRed - event.TeamID
+				}
+				switch event.BuildingType {
+				case "INHIBITOR_BUILDING":
+					ge.name, ge.rank = "an inhibitor", objectiveRankInhibitor
+				case "TOWER_BUILDING":
+					ge.name, ge.rank = "a tower", objectiveRankTower
+				default:
+					continue
+				}
+			default:
+				continue
+			}
+			if team == 0 {
+				continue
+			}
+			ge.ours = team == ours
+			converted.events = append(converted.events, ge)
+		}
+
+		gold := make(map[int]int, 2)
+		for key, pf := range frame.ParticipantFrames {
+			id := pf.ParticipantID
+			if id == 0 {
+				id, _ = strconv.Atoi(key)
+			}
+			gold[teamOf(id)] += pf.TotalGold
+		}
+		converted.frames = append(converted.frames, minuteFrame{
+			time:       float64(frame.Timestamp) / 1000,
+			goldLead:   gold[ours] - gold[eog.TeamIDBlue+eog.TeamIDRed-ours],
+			ourKills:   ourKills,
+			theirKills: theirKills,
+		})
+	}
+
+	sort.SliceStable(converted.events, func(i, j int) bool { return converted.events[i].time < converted.events[j].time })
+	return converted
+}
+
+// turningPoints returns the teamfights that moved the gold lead the most, or that won Baron,
+// Elder Dragon or an inhibitor, in game order
+func turningPoints(timeline *gameTimeline) []TurningPoint {
+	type candidate struct {
+		point  TurningPoint
+		start  float64
+		weight int // Gold swing towards the winners
+	}
+	var candidates []candidate
+
+	for _, fight := range timeline.teamfights() {
+		point := TurningPoint{
+			Time:       FormatGameTime(fight.start),
+			OurKills:   fight.ourKills,
+			TheirKills: fight.theirKills,
+		}
+		switch {
+		case point.OurKills > point.TheirKills:
+			point.Winner = "us"
+		case point.TheirKills > point.OurKills:
+			point.Winner = "them"
+		default:
+			continue // Traded evenly
+		}
+
+		before := timeline.at(fight.start)
+		after := timeline.after(fight.end + 60)
+		if before == nil || after == nil {
+			continue
+		}
+		point.GoldSwing = after.goldLead - before.goldLead
+
+		objective, rank := timeline.objectiveAfter(fight, point.Winner == "us")
+		point.Objective = objective
+
+		weight := point.GoldSwing
+		if point.Winner == "them" {
+			weight = -weight
+		}
+		flipped := (before.goldLead < 0) != (after.goldLead < 0) && abs(after.goldLead) >= leadGold
+		if weight < leadGold && !flipped && rank < objectiveRankInhibitor {
+			continue
+		}
+
+		subject, won, lost := "We", point.OurKills, point.TheirKills
+		if point.Winner == "them" {
+			subject, won, lost = "They", point.TheirKills, point.OurKills
+		}
+		point.Description = fmt.Sprintf("%s won a %d-for-%d fight at %s", subject, won, lost, point.Time)
+		if objective != "" {
+			point.Description += " and took " + objective
+		}
+		candidates = append(candidates, candidate{point: point, start: fight.start, weight: weight + rank*leadGold})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].weight > candidates[j].weight })
+	if len(candidates) > maxTurningPoints {
+		candidates = candidates[:maxTurningPoints]
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].start < candidates[j].start })
+
+	points := make([]TurningPoint, 0, len(candidates))
+	for _, c := range candidates {
+		points = append(points, c.point)
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	return points
+}
+
+// teamfights groups champion kills into fights of at least teamfightMinKills kills
+func (t *gameTimeline) teamfights() []teamfight {
+	var fights []teamfight
+	var current *teamfight
+	for _, event := range t.events {
+		if event.rank != 0 {
+			continue
+		}
+		if current == nil || event.time-current.end > teamfightGapSeconds {
+			fights = append(fights, teamfight{start: event.time})
+			current = &fights[len(fights)-1]
+		}
+		current.end = event.time
+		if event.ours {
+			current.ourKills++
+		} else {
+			current.theirKills++
+		}
+	}
+
+	large := fights[:0]
+	for _, fight := range fights {
+		if fight.ourKills+fight.theirKills >= teamfightMinKills {
+			large = append(large, fight)
+		}
+	}
+	return large
+}
+
+// objectiveAfter returns the biggest objective a team took during a fight or the 90 seconds after it
+func (t *gameTimeline) objectiveAfter(fight teamfight, ours bool) (string, int) {
+	best, bestRank := "", 0
+	for _, event := range t.events {
+		if event.time < fight.start || event.time > fight.end+90 || event.ours != ours {
+			continue
+		}
+		if event.rank > bestRank {
+			best, bestRank = event.name, event.rank
+		}
+	}
+	return best, bestRank
+}
+
+// at returns the last frame at or before a game time (nil if there is none)
+func (t *gameTimeline) at(seconds float64) *minuteFrame {
+	var found *minuteFrame
+	for i := range t.frames {
+		if t.frames[i].time > seconds {
+			break
+		}
+		found = &t.frames[i]
+	}
+	return found
+}
+
+// after returns the first frame at or after a game time, or the last frame
+func (t *gameTimeline) after(seconds float64) *minuteFrame {
+	for i := range t.frames {
+		if t.frames[i].time >= seconds {
+			return &t.frames[i]
+		}
+	}
+	return &t.frames[len(t.frames)-1]
+}
+
+// inMinute returns the frame recorded during the given minute (nil if none was)
+func (t *gameTimeline) inMinute(minute int) *minuteFrame {
+	for i := range t.frames {
+		if int(t.frames[i].time/60) == minute {
+			return &t.frames[i]
+		}
+	}
+	return nil
+}
+
+// leaderOf returns "us" or "them" for a lead of leadGold or more, or "" when the game is even
+func leaderOf(goldLead int) string {
+	switch {
+	case goldLead >= leadGold:
+		return "us"
+	case goldLead <= -leadGold:
+		return "them"
+	}
+	return ""
+}
+
+// dragonName returns the in-game name of a dragon type ("Fire" -> "the Infernal Drake")
+func dragonName(dragonType string) string {
+	names := map[string]string{
+		"Fire":     "the Infernal Drake",
+		"Earth":    "the Mountain Drake",
+		"Water":    "the Ocean Drake",
+		"Air":      "the Cloud Drake",
+		"Hextech":  "the Hextech Drake",
+		"Chemtech": "the Chemtech Drake",
+		"Elder":    "the Elder Dragon",
+	}
+	if name, ok := names[dragonType]; ok {
+		return name
+	}
+	return "a drake"
+}
+
+// matchDragonTypes maps match-history dragon subtypes to the live game's dragon types
+var matchDragonTypes = map[string]string{
+	"FIRE_DRAGON":     "Fire",
+	"EARTH_DRAGON":    "Earth",
+	"WATER_DRAGON":    "Water",
+	"AIR_DRAGON":      "Air",
+	"HEXTECH_DRAGON":  "Hextech",
+	"CHEMTECH_DRAGON": "Chemtech",
+	"ELDER_DRAGON":    "Elder",
+}
+
+// liveTeam converts an EoG side ("BLUE" or "RED") to the live game's team name
+func liveTeam(side string) string {
+	switch side {
+	case eog.TeamIDToSide(eog.TeamIDBlue):
+		return "ORDER"
+	case eog.TeamIDToSide(eog.TeamIDRed):
+		return "CHAOS"
+	}
+	return ""
+}
+
+func otherLiveTeam(team string) string {
+	if team == "ORDER" {
+		return "CHAOS"
+	}
+	return "ORDER"
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
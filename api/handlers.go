@@ -0,0 +1,236 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/config"
+	"lol-kind-bot/history"
+	"lol-kind-bot/llm"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// lastResponse is the body of GET /api/last
+type lastResponse struct {
+	GameID      string                `json:"gameId,omitempty"`
+	Summary     *analyzer.GameSummary `json:"summary"`
+	Messages    []string              `json:"messages"`
+	Suggestions []llm.Suggestion      `json:"suggestions"` // Messages with the reasoning behind them
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.bot.Status())
+}
+
+func (s *Server) handleLast(w http.ResponseWriter, r *http.Request) {
+	set := s.bot.LastMessageSet()
+	if set == nil {
+		writeError(w, http.StatusNotFound, "no game has been processed yet")
+		return
+	}
+	suggestions := set.Suggestions()
+	writeJSON(w, http.StatusOK, lastResponse{
+		GameID:      set.GameID,
+		Summary:     set.Summary,
+		Messages:    llm.SuggestionTexts(suggestions),
+		Suggestions: suggestions,
+	})
+}
+
+// handleRegenerate starts regeneration in the background; the new messages arrive as a
+// "messages" event and from GET /api/last
+func (s *Server) handleRegenerate(w http.ResponseWriter, r *http.Request) {
+	go func() {
+		if err := s.bot.Regenerate(); err != nil {
+			log.Printf("[API] Regenerate failed: %v", err)
+		}
+	}()
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "regenerating"})
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	s.bot.SetListening(false)
+	writeJSON(w, http.StatusOK, s.bot.Status())
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	s.bot.SetListening(true)
+	writeJSON(w, http.StatusOK, s.bot.Status())
+}
+
+func (s *Server) handleMute(w http.ResponseWriter, r *http.Request) {
+	s.bot.SetMuted(true)
+	writeJSON(w, http.StatusOK, s.bot.Status())
+}
+
+func (s *Server) handleUnmute(w http.ResponseWriter, r *http.Request) {
+	s.bot.SetMuted(false)
+	writeJSON(w, http.StatusOK, s.bot.Status())
+}
+
+func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.bot.Config().Redacted())
+}
+
+// handlePatchConfig merges a partial config JSON object into the current config and saves it.
+// Changes to the "api" section take effect after a restart.
+func (s *Server) handlePatchConfig(w http.ResponseWriter, r *http.Request) {
+	patch, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err))
+		return
+	}
+
+	// Round-trip through JSON so the patch can't alias slices of the live config
+	current, err := json.Marshal(s.bot.Config())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to marshal config: %v", err))
+		return
+	}
+	var cfg config.Config
+	if err := json.Unmarshal(current, &cfg); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to copy config: %v", err))
+		return
+	}
+	if err := json.Unmarshal(patch, &cfg); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid config patch: %v", err))
+		return
+	}
+	cfg.RestoreSecrets(s.bot.Config()) // Secrets sent back as we showed them are unchanged
+	if unknown := config.UnknownKeys(patch); len(unknown) > 0 {
+		writeJSON(w, http.StatusBadRequest, &config.ValidationError{Errors: unknown})
+		return
+	}
+
+	if err := s.bot.UpdateConfig(&cfg); err != nil {
+		var validationErr *config.ValidationError
+		if errors.As(err, &validationErr) {
+			writeJSON(w, http.StatusBadRequest, validationErr)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.bot.Config().Redacted())
+}
+
+// handleHistory supports ?limit=, ?champion=, ?profile=, ?since= (RFC 3339) and ?win=true|false
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	q := history.Query{Limit: 20, Champion: params.Get("champion"), Profile: params.Get("profile")}
+
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		q.Limit = n
+	}
+	if since := params.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "since must be an RFC 3339 timestamp")
+			return
+		}
+		q.Since = t
+	}
+	if win := params.Get("win"); win != "" {
+		b, err := strconv.ParseBool(win)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "win must be true or false")
+			return
+		}
+		q.Win = &b
+	}
+
+	entries, err := s.bot.History().Query(q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// handleExportFeedback returns every recorded feedback record and the preferences learned from them
+func (s *Server) handleExportFeedback(w http.ResponseWriter, r *http.Request) {
+	export, err := s.bot.ExportFeedback()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, export)
+}
+
+// handleResetFeedback deletes all recorded feedback
+func (s *Server) handleResetFeedback(w http.ResponseWriter, r *http.Request) {
+	if err := s.bot.ResetFeedback(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEvents streams bot events as Server-Sent Events until the client disconnects
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	events, unsubscribe := s.bot.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.closing:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if cfg, ok := event.Data.(*config.Config); ok {
+				event.Data = cfg.Redacted()
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("[API] Failed to marshal event %s: %v", event.Type, err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("[API] Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
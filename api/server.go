@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"lol-kind-bot/app"
+	"lol-kind-bot/config"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Server is the local HTTP/JSON control API for a running bot.
+// It only listens on 127.0.0.1 and every request must carry the configured token.
+type Server struct {
+	bot      *app.App
+	port     int
+	token    string
+	listener net.Listener
+	server   *http.Server
+	closing  chan struct{} // Closed on Close so event streams end
+}
+
+// NewServer creates a control API server for bot. Nothing listens until Start is called.
+func NewServer(bot *app.App, settings config.APISettings) *Server {
+	return &Server{
+		bot:     bot,
+		port:    settings.Port,
+		token:   settings.Token,
+		closing: make(chan struct{}),
+	}
+}
+
+// GenerateToken returns a random token for APISettings.Token
+func GenerateToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Start begins serving on 127.0.0.1 at the configured port
+func (s *Server) Start() error {
+	if s.token == "" {
+		return fmt.Errorf("API token is not set")
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(s.port)))
+	if err != nil {
+		return fmt.Errorf("failed to start control API: %w", err)
+	}
+	s.listener = listener
+	s.server = &http.Server{
+		Handler:           s.authenticate(s.routes()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("[API] Server error: %v", err)
+		}
+	}()
+
+	log.Printf("[API] Control API listening on http://%s", listener.Addr())
+	return nil
+}
+
+// Addr returns the address the server is listening on
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Close shuts the server down, ending any open event streams
+func (s *Server) Close() error {
+	if s.server == nil {
+		return nil
+	}
+	close(s.closing)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		return s.server.Close()
+	}
+	return nil
+}
+
+// routes registers the API endpoints
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/status", s.handleStatus)
+	mux.HandleFunc("GET /api/last", s.handleLast)
+	mux.HandleFunc("POST /api/regenerate", s.handleRegenerate)
+	mux.HandleFunc("POST /api/pause", s.handlePause)
+	mux.HandleFunc("POST /api/resume", s.handleResume)
+	mux.HandleFunc("POST /api/mute", s.handleMute)
+	mux.HandleFunc("POST /api/unmute", s.handleUnmute)
+	mux.HandleFunc("GET /api/config", s.handleGetConfig)
+	mux.HandleFunc("PATCH /api/config", s.handlePatchConfig)
+	mux.HandleFunc("GET /api/history", s.handleHistory)
+	mux.HandleFunc("GET /api/feedback", s.handleExportFeedback)
+	mux.HandleFunc("DELETE /api/feedback", s.handleResetFeedback)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+	return mux
+}
+
+// authenticate rejects requests without the API token. The token is accepted as a
+// bearer token or, for EventSource clients that can't set headers, a ?token= query parameter.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid API token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
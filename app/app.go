@@ -0,0 +1,323 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/config"
+	"lol-kind-bot/feedback"
+	"lol-kind-bot/history"
+	"lol-kind-bot/lcu"
+	"lol-kind-bot/llm"
+	"lol-kind-bot/monitor"
+	"lol-kind-bot/tts"
+	"sync"
+	"time"
+)
+
+// UI surfaces bot output to the user. The tray build injects the Fyne/Windows
+// implementation; headless runs use ConsoleUI.
+type UI interface {
+	ShowToast(title, message string)
+	ShowMessages(set *MessageSet)
+	ShowReply(set *MessageSet, reply llm.ChatReply) // A post-game chat line with suggested replies
+	ShowOpeners(set *OpenerSet)                     // Champion select openers, until set.Done() closes
+}
+
+// Options configures a new App
+type Options struct {
+	ConfigPath string
+	UI         UI                // Defaults to ConsoleUI
+	Recorder   *lcu.Recorder     // Optional: records all LCU traffic
+	Lockfile   *lcu.LockfileInfo // Optional: fixed connection info (e.g. a replay server) instead of the lockfile
+	History    *history.Store    // Optional: where processed games are stored (defaults to next to the config)
+	Feedback   *feedback.Store   // Optional: where message feedback is stored (defaults to next to the config)
+
+	ForceDebugLogging bool // Keep debug logging on across config reloads (-debug flag)
+}
+
+// App owns the bot's components and their lifecycle
+type App struct {
+	configPath string
+	ui         UI
+	recorder   *lcu.Recorder
+	lockfile   *lcu.LockfileInfo
+	history    *history.Store
+	feedback   *feedback.Store
+	events     eventBus
+	speech     *tts.Queue // In-game announcements
+
+	forceDebugLogging bool
+
+	mu                 sync.RWMutex
+	cfg                *config.Config
+	lcuClient          *lcu.Client
+	llmClient          *llm.Client
+	gameMonitor        *monitor.GameflowMonitor
+	goldMonitor        *monitor.GoldMonitor
+	clutchMonitor      *monitor.ClutchMonitor
+	objectiveMonitor   *monitor.ObjectiveMonitor
+	timelineRecorder   *monitor.TimelineRecorder
+	champSelectMonitor *monitor.ChampSelectMonitor
+	listening          bool
+	currentPhase       string
+	lastGameID         string      // Track last processed game to prevent duplicates
+	lastResult         *MessageSet // Messages for the most recently processed game
+	openers            *OpenerSet  // Openers for the current champion select
+	partyMembers       []string    // Our lobby, for party-based profile rules
+	gameProfile        string      // Profile chosen for the current or last game
+	clientLocale       string      // League client locale (e.g. "es_ES"), for the "auto" language
+
+	eogMu       sync.Mutex // Serializes EoG processing
+	lastEoGTime time.Time  // Track last EoG processing time for rate limiting
+	backfillMu  sync.Mutex // Serializes history backfills
+
+	saves sync.WaitGroup // Message history updates still being written
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates an App for the given config. Nothing runs until Start is called.
+func New(cfg *config.Config, opts Options) *App {
+	ui := opts.UI
+	if ui == nil {
+		ui = ConsoleUI{}
+	}
+	historyStore := opts.History
+	if historyStore == nil {
+		historyStore = history.NewStore(history.PathFor(opts.ConfigPath))
+	}
+	feedbackStore := opts.Feedback
+	if feedbackStore == nil {
+		feedbackStore = feedback.NewStore(feedback.PathFor(opts.ConfigPath))
+	}
+
+	a := &App{
+		configPath: opts.ConfigPath,
+		ui:         ui,
+		recorder:   opts.Recorder,
+		lockfile:   opts.Lockfile,
+		history:    historyStore,
+		feedback:   feedbackStore,
+		speech:     tts.NewQueue(newSpeechEngine(&cfg.TTS)),
+
+		forceDebugLogging: opts.ForceDebugLogging,
+		cfg:               cfg,
+		llmClient:         llm.NewClient(cfg.OllamaURL, cfg.OllamaModel, &cfg.LLMSettings),
+		listening:         true,
+	}
+	a.loadExamples()
+	return a
+}
+
+// Start connects to the League client (reconnecting as needed) and runs the monitors until ctx is cancelled or Stop is called
+func (a *App) Start(ctx context.Context) {
+	a.mu.Lock()
+	if a.cancel != nil {
+		a.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	a.cancel = cancel
+	a.mu.Unlock()
+
+	a.wg.Add(2)
+	go func() {
+		defer a.wg.Done()
+		a.connectionLoop(ctx)
+	}()
+	go func() {
+		defer a.wg.Done()
+		a.watchConfig(ctx)
+	}()
+}
+
+// Stop shuts down the connection loop, all monitors and announcements, and waits for in-flight EoG processing
+func (a *App) Stop() {
+	a.mu.Lock()
+	cancel := a.cancel
+	a.cancel = nil
+	a.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	a.wg.Wait()
+	a.stopMonitors()
+
+	// Wait for any EoG processing and history writes to finish
+	a.eogMu.Lock()
+	a.eogMu.Unlock()
+	a.saves.Wait()
+
+	// Cut off any announcement mid-sentence, so no TTS process outlives us
+	a.speech.Close()
+}
+
+// Config returns the current configuration
+func (a *App) Config() *config.Config {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.cfg
+}
+
+// ConfigPath returns the path the config was loaded from
+func (a *App) ConfigPath() string {
+	return a.configPath
+}
+
+// UpdateConfig validates, saves and applies cfg to every running component.
+// An invalid config is rejected with a *config.ValidationError and nothing changes.
+func (a *App) UpdateConfig(cfg *config.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if err := config.SaveConfig(a.configPath, cfg); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	a.applyConfig(cfg)
+	return nil
+}
+
+// LCUClient returns the connected League client, or nil when disconnected
+func (a *App) LCUClient() *lcu.Client {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.lcuClient
+}
+
+// LLMClient returns the current LLM client
+func (a *App) LLMClient() *llm.Client {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.llmClient
+}
+
+// History returns the store of processed games
+func (a *App) History() *history.Store {
+	return a.history
+}
+
+// Feedback returns the store of message feedback
+func (a *App) Feedback() *feedback.Store {
+	return a.feedback
+}
+
+// LastResult returns the summary and messages for the most recently processed game (nil if none yet)
+func (a *App) LastResult() (*analyzer.GameSummary, []string) {
+	set := a.LastMessageSet()
+	if set == nil {
+		return nil, nil
+	}
+	return set.Summary, set.Messages()
+}
+
+// Status is a snapshot of what the bot is doing
+type Status struct {
+	Connected  bool            `json:"connected"`
+	Listening  bool            `json:"listening"`
+	Muted      bool            `json:"muted"` // In-game announcements muted
+	Phase      string          `json:"phase"`
+	LastGameID string          `json:"lastGameId,omitempty"`
+	Profile    string          `json:"profile,omitempty"` // Profile for the current or last game
+	Monitors   map[string]bool `json:"monitors"`          // Monitor name -> running
+}
+
+// Status returns the current connection, phase and monitor state
+func (a *App) Status() Status {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	status := Status{
+		Connected:  a.lcuClient != nil,
+		Listening:  a.listening,
+		Muted:      a.speech.Muted(),
+		Phase:      a.currentPhase,
+		LastGameID: a.lastGameID,
+		Profile:    a.gameProfile,
+		Monitors: map[string]bool{
+			"gameflow":    a.gameMonitor != nil && a.gameMonitor.IsRunning(),
+			"gold":        a.goldMonitor != nil && a.goldMonitor.IsRunning(),
+			"clutch":      a.clutchMonitor != nil && a.clutchMonitor.IsRunning(),
+			"objectives":  a.objectiveMonitor != nil && a.objectiveMonitor.IsRunning(),
+			"timeline":    a.timelineRecorder != nil && a.timelineRecorder.IsRunning(),
+			"champSelect": a.champSelectMonitor != nil && a.champSelectMonitor.IsRunning(),
+		},
+	}
+	return status
+}
+
+// CurrentPhase returns the last observed gameflow phase
+func (a *App) CurrentPhase() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.currentPhase
+}
+
+// IsListening reports whether the bot is listening for games
+func (a *App) IsListening() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.listening
+}
+
+// SetListening pauses or resumes listening for games
+func (a *App) SetListening(listening bool) {
+	a.mu.Lock()
+	a.listening = listening
+	gameMonitor := a.gameMonitor
+	a.mu.Unlock()
+
+	if gameMonitor != nil {
+		if listening && !gameMonitor.IsRunning() {
+			gameMonitor.Start()
+		} else if !listening {
+			gameMonitor.Stop()
+		}
+	}
+	if listening {
+		log.Println("Listener resumed")
+	} else {
+		log.Println("Listener paused")
+	}
+	a.publish(EventListening, listening)
+}
+
+func (a *App) setPhase(phase string) {
+	a.mu.Lock()
+	changed := a.currentPhase != phase
+	a.currentPhase = phase
+	a.mu.Unlock()
+
+	if changed {
+		a.publish(EventPhaseChanged, phase)
+	}
+}
+
+// stopMonitors stops any running monitors
+func (a *App) stopMonitors() {
+	a.mu.RLock()
+	gameMonitor, goldMonitor, clutchMonitor, objectiveMonitor := a.gameMonitor, a.goldMonitor, a.clutchMonitor, a.objectiveMonitor
+	timelineRecorder := a.timelineRecorder
+	a.mu.RUnlock()
+
+	if gameMonitor != nil {
+		gameMonitor.Stop()
+	}
+	if goldMonitor != nil && goldMonitor.IsRunning() {
+		goldMonitor.Stop()
+	}
+	if clutchMonitor != nil && clutchMonitor.IsRunning() {
+		clutchMonitor.Stop()
+	}
+	if objectiveMonitor != nil && objectiveMonitor.IsRunning() {
+		objectiveMonitor.Stop()
+	}
+	if timelineRecorder != nil && timelineRecorder.IsRunning() {
+		timelineRecorder.Stop()
+	}
+	a.endChampSelect()
+}
@@ -0,0 +1,361 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"lol-kind-bot/history"
+	"lol-kind-bot/lcu"
+	"lol-kind-bot/llm"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	champSelectPollInterval = 2 * time.Second // How often champion select is checked for new picks
+	lossStreakGames         = 5               // Recent games looked at for a teammate's loss streak
+	teammateHistoryGames    = 200             // Our most recent games searched for earlier games with a teammate
+)
+
+// OpenerSet is the openers suggested for one champion select. They are rewritten whenever a
+// pick or position changes, until we send one or the game starts.
+type OpenerSet struct {
+	app     *App
+	profile string
+
+	mu         sync.Mutex
+	team       llm.ChampSelect
+	teamKey    string
+	openers    []string
+	generation int  // Bumped by each rewrite; late LLM openers for an older team are dropped
+	generating bool // LLM openers are still on their way
+	cancel     context.CancelFunc
+	sent       string
+	listeners  changeListeners
+	done       chan struct{} // Closed when champion select ends
+
+	// Looked up once per champion select
+	championNames map[int]string
+	lossStreaks   map[string]int    // Puuid -> losses in a row before this game
+	together      map[string][2]int // Lowercase Riot ID name -> games and wins with us
+}
+
+// startChampSelect starts suggesting openers for a champion select that just began
+func (a *App) startChampSelect(client *lcu.Client) {
+	cfg := a.Config()
+	a.mu.RLock()
+	champSelectMonitor := a.champSelectMonitor
+	a.mu.RUnlock()
+	if !cfg.ChampSelectOpeners || champSelectMonitor == nil {
+		return
+	}
+
+	set := &OpenerSet{
+		app:         a,
+		profile:     a.selectProfileForSession(client),
+		done:        make(chan struct{}),
+		lossStreaks: make(map[string]int),
+		together:    a.teammateRecords(),
+	}
+	if names, err := client.GetChampionNames(); err != nil {
+		log.Printf("[OPENERS] Failed to get champion names, openers won't name champions: %v", err)
+	} else {
+		set.championNames = names
+	}
+
+	a.mu.Lock()
+	if a.currentPhase != "ChampSelect" {
+		a.mu.Unlock()
+		return // Champion select ended while we were getting ready
+	}
+	previous := a.openers
+	a.openers = set
+	// Start while the phase check holds, so an endChampSelect after this always sees the monitor running
+	champSelectMonitor.Start()
+	a.mu.Unlock()
+	if previous != nil {
+		previous.end()
+	}
+}
+
+// endChampSelect stops suggesting openers once champion select is over
+func (a *App) endChampSelect() {
+	a.mu.Lock()
+	champSelectMonitor, set := a.champSelectMonitor, a.openers
+	a.openers = nil
+	a.mu.Unlock()
+
+	if champSelectMonitor != nil && champSelectMonitor.IsRunning() {
+		champSelectMonitor.Stop()
+	}
+	if set != nil {
+		set.end()
+	}
+}
+
+// handleChampSelect rewrites the openers for our team as picks and positions come in
+func (a *App) handleChampSelect(session *lcu.ChampSelectSession) {
+	a.mu.RLock()
+	set, client := a.openers, a.lcuClient
+	a.mu.RUnlock()
+	if set == nil || client == nil {
+		return
+	}
+	set.update(set.teamFor(client, session))
+}
+
+// teammateRecords counts our earlier games and wins with each player, by lowercase Riot ID name
+func (a *App) teammateRecords() map[string][2]int {
+	records := make(map[string][2]int)
+	entries, err := a.history.Query(history.Query{Limit: teammateHistoryGames})
+	if err != nil {
+		log.Printf("[OPENERS] Failed to read history, openers won't greet past teammates: %v", err)
+		return records
+	}
+
+	for _, entry := range entries {
+		summary := entry.Summary
+		if summary == nil {
+			continue
+		}
+		me := summary.Me()
+		for i := range summary.Players {
+			player := &summary.Players[i]
+			if player == me || player.Team != summary.MyTeam {
+				continue
+			}
+			name := riotName(player.SummonerName)
+			if name == "" {
+				continue
+			}
+			record := records[name]
+			record[0]++
+			if summary.MyTeamWon() {
+				record[1]++
+			}
+			records[name] = record
+		}
+	}
+	return records
+}
+
+// riotName lowercases a player name and drops its #tag, so names from history and champion select match
+func riotName(name string) string {
+	name, _, _ = strings.Cut(name, "#")
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// teamFor describes our team for the openers, looking up loss streaks the first time a teammate is seen
+func (s *OpenerSet) teamFor(client *lcu.Client, session *lcu.ChampSelectSession) llm.ChampSelect {
+	var team llm.ChampSelect
+	me := session.Me()
+	if me != nil {
+		team.MyChampion = s.championNames[me.Champion()]
+		team.MyPosition = me.AssignedPosition
+	}
+
+	for _, player := range session.MyTeam {
+		if me != nil && player.CellID == me.CellID {
+			continue
+		}
+		teammate := llm.Teammate{
+			Name:     player.GameName,
+			Champion: s.championNames[player.Champion()],
+			Position: player.AssignedPosition,
+		}
+		if record, ok := s.together[riotName(player.GameName)]; ok && player.GameName != "" {
+			teammate.GamesWithUs, teammate.WinsWithUs = record[0], record[1]
+		}
+		if player.Puuid != "" {
+			teammate.LossStreak = s.lossStreak(client, player.Puuid)
+		}
+		team.Teammates = append(team.Teammates, teammate)
+	}
+	return team
+}
+
+// lossStreak returns how many games in a row a player lost before this one (0 if unknown)
+func (s *OpenerSet) lossStreak(client *lcu.Client, puuid string) int {
+	s.mu.Lock()
+	streak, ok := s.lossStreaks[puuid]
+	s.mu.Unlock()
+	if ok {
+		return streak
+	}
+
+	results, err := client.GetRecentResults(puuid, lossStreakGames)
+	if err != nil {
+		log.Printf("[OPENERS] Failed to get a teammate's recent games: %v", err)
+	}
+	for _, won := range results {
+		if won {
+			break
+		}
+		streak++
+	}
+
+	s.mu.Lock()
+	s.lossStreaks[puuid] = streak // Remember failures too, so we don't ask every poll
+	s.mu.Unlock()
+	return streak
+}
+
+// update shows template openers for the team straight away, then asks the LLM for better ones
+func (s *OpenerSet) update(team llm.ChampSelect) {
+	cfg := s.app.configForProfile(s.profile)
+	key := team.Key()
+
+	s.mu.Lock()
+	if key == s.teamKey || s.sent != "" || s.ended() {
+		s.mu.Unlock()
+		return
+	}
+	first := s.teamKey == ""
+	if s.cancel != nil {
+		s.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.team, s.teamKey = team, key
+	s.openers = llm.OfflineOpeners(team, &cfg.LLMSettings)
+	s.generation++
+	s.generating = true
+	s.cancel = cancel
+	generation, openers := s.generation, append([]string(nil), s.openers...)
+	s.mu.Unlock()
+
+	log.Printf("[OPENERS] Team changed (me: %s %s, %d teammates)", team.MyChampion, team.MyPosition, len(team.Teammates))
+	s.app.publish(EventOpeners, openers)
+	if first {
+		s.app.ui.ShowOpeners(s)
+	}
+	s.changed()
+
+	go func() {
+		defer cancel()
+		client := s.app.llmClientFor(cfg, s.profile)
+		better, err := client.GenerateOpeners(ctx, team, cfg.EnableDebugLogging)
+
+		s.mu.Lock()
+		if generation != s.generation || s.ended() {
+			s.mu.Unlock()
+			return // The team changed or champion select ended meanwhile
+		}
+		s.generating = false
+		s.cancel = nil
+		if err == nil {
+			s.openers = better
+		}
+		openers := append([]string(nil), s.openers...)
+		s.mu.Unlock()
+
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[OPENERS] LLM openers failed: %v. Keeping template openers.", err)
+		}
+		s.app.publish(EventOpeners, openers)
+		s.changed()
+	}()
+}
+
+// end stops the set when champion select is over
+func (s *OpenerSet) end() {
+	s.mu.Lock()
+	if s.ended() {
+		s.mu.Unlock()
+		return
+	}
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	s.generating = false
+	close(s.done)
+	s.mu.Unlock()
+
+	s.changed()
+}
+
+// ended reports whether champion select is over. Must be called with s.mu held.
+func (s *OpenerSet) ended() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Openers returns the suggested openers, best first
+func (s *OpenerSet) Openers() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.openers...)
+}
+
+// Team returns our team as the openers were written for it
+func (s *OpenerSet) Team() llm.ChampSelect {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.team
+}
+
+// Generating reports whether LLM openers are still being written
+func (s *OpenerSet) Generating() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generating
+}
+
+// Sent returns the opener we sent to the team chat ("" if none yet)
+func (s *OpenerSet) Sent() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sent
+}
+
+// Done is closed when champion select ends and the openers should go away
+func (s *OpenerSet) Done() <-chan struct{} {
+	return s.done
+}
+
+// OnChange calls fn whenever the openers change, one is sent or champion select ends, and
+// returns a function to stop
+func (s *OpenerSet) OnChange(fn func()) func() {
+	return s.listeners.add(fn)
+}
+
+// changed notifies the OnChange listeners
+func (s *OpenerSet) changed() {
+	s.listeners.notify()
+}
+
+// Send posts text to the champion select team chat. Once an opener is sent, the openers stop changing.
+func (s *OpenerSet) Send(text string) error {
+	client := s.app.LCUClient()
+	if client == nil {
+		return fmt.Errorf("not connected to the League client")
+	}
+	conversation, err := client.GetChampSelectConversation()
+	if err != nil {
+		return err
+	}
+	if conversation == nil {
+		return fmt.Errorf("champion select chat is not open")
+	}
+	if err := client.SendMessage(conversation.ID, text); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.sent = text
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	s.generating = false
+	s.mu.Unlock()
+
+	log.Printf("[OPENERS] Sent opener: %s", text)
+	s.changed()
+	return nil
+}
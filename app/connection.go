@@ -0,0 +1,445 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"lol-kind-bot/lcu"
+	"lol-kind-bot/monitor"
+	"lol-kind-bot/tts"
+	"time"
+)
+
+// sleepCtx sleeps for d, returning false if ctx was cancelled first
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// connectionLoop connects to the League client (reconnecting as needed) and runs the monitors until ctx is cancelled
+func (a *App) connectionLoop(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if !a.IsListening() {
+			if !sleepCtx(ctx, 5*time.Second) {
+				return
+			}
+			continue
+		}
+
+		lockfileInfo, err := a.locateLCU()
+		if err != nil {
+			log.Printf("Lockfile not found or invalid: %v. Retrying in 3 seconds...", err)
+			if !sleepCtx(ctx, 3*time.Second) {
+				return
+			}
+			continue
+		}
+
+		log.Printf("Found lockfile: port=%s, protocol=%s", lockfileInfo.Port, lockfileInfo.Protocol)
+
+		client, err := lcu.NewClient(lockfileInfo)
+		if err != nil {
+			log.Printf("Failed to create LCU client: %v. Retrying...", err)
+			if !sleepCtx(ctx, 3*time.Second) {
+				return
+			}
+			continue
+		}
+		client.Recorder = a.recorder
+
+		a.connect(client)
+
+		// Keep monitoring until connection is lost
+		for {
+			if !sleepCtx(ctx, 5*time.Second) {
+				return
+			}
+			// Test connection
+			_, err := client.Get("/lol-gameflow/v1/gameflow-phase")
+			if err != nil {
+				log.Printf("LCU connection lost: %v. Reconnecting...", err)
+				a.stopMonitors()
+				a.mu.Lock()
+				a.lcuClient = nil
+				a.mu.Unlock()
+				a.publish(EventDisconnected, nil)
+				break
+			}
+		}
+	}
+}
+
+// connect creates the monitors for a freshly connected client and starts the gameflow monitor
+func (a *App) connect(client *lcu.Client) {
+	cfg := a.Config()
+
+	// Create gold monitor (will be started/stopped based on game phase)
+	goldMonitor := monitor.NewGoldMonitor(client, &cfg.GoldAnnouncements, func(milestone monitor.GoldMilestone) {
+		a.say(tts.Utterance{Text: milestone.Text, Priority: tts.PriorityLow, Key: "gold"})
+		a.publish(EventGoldMilestone, milestone)
+	})
+	log.Printf("Gold monitor created (enabled: %v, mode: %s, thresholds: %v)", cfg.GoldAnnouncements.Enabled, cfg.GoldAnnouncements.Mode, cfg.GoldAnnouncements.Thresholds)
+
+	// Create objective timer monitor (announces spawns and buff expiry during the game)
+	objectiveMonitor := monitor.NewObjectiveMonitor(client, &cfg.ObjectiveTimers, func(text string) {
+		a.say(tts.Utterance{Text: text, Priority: tts.PriorityNormal})
+		a.publish(EventObjectiveTimer, text)
+	})
+
+	// Create clutch event monitor
+	clutchMonitor := monitor.NewClutchMonitor(client, 2*time.Second) // Poll every 2 seconds
+	log.Printf("Clutch monitor created")
+
+	// Create timeline recorder (samples the game for post-game analysis)
+	timelineRecorder := monitor.NewTimelineRecorder(client, timelinePollInterval)
+
+	// Create champ select monitor (suggests openers while picks come in)
+	champSelectMonitor := monitor.NewChampSelectMonitor(client, champSelectPollInterval, a.handleChampSelect)
+
+	// Create gameflow monitor
+	pollInterval := time.Duration(cfg.PollIntervalSeconds) * time.Second
+	cooldown := time.Duration(cfg.EndOfGameCooldownSec) * time.Second
+	gameMonitor := monitor.NewGameflowMonitor(client, pollInterval, cooldown, a.HandleEndOfGame)
+
+	// Set phase change callback to manage gold monitor
+	gameMonitor.SetPhaseChangeCallback(a.handlePhaseChange)
+
+	// The client's locale picks the message language when it is set to "auto"
+	locale := ""
+	if regionLocale, err := client.GetRegionLocale(); err != nil {
+		log.Printf("Failed to get client locale: %v", err)
+	} else {
+		locale = regionLocale.Locale
+		log.Printf("Client locale: %s (region %s)", regionLocale.Locale, regionLocale.Region)
+	}
+
+	a.mu.Lock()
+	a.lcuClient = client
+	a.clientLocale = locale
+	a.goldMonitor = goldMonitor
+	a.clutchMonitor = clutchMonitor
+	a.objectiveMonitor = objectiveMonitor
+	a.timelineRecorder = timelineRecorder
+	a.champSelectMonitor = champSelectMonitor
+	a.gameMonitor = gameMonitor
+	a.mu.Unlock()
+	log.Printf("Connected to LCU at: %s", client.BaseURL)
+	a.publish(EventConnected, client.BaseURL)
+
+	// Check if player was recently in a match or is in post-match screen
+	a.checkRecentMatch(client)
+
+	// Add games played while we weren't running to the history
+	go a.backfillHistory(client)
+
+	// Check if game is currently in progress on startup
+	a.checkActiveGameOnStartup(client)
+
+	if !gameMonitor.IsRunning() {
+		gameMonitor.Start()
+	}
+}
+
+// locateLCU returns connection info for the League client, or the fixed connection info (e.g. a replay server) when set
+func (a *App) locateLCU() (*lcu.LockfileInfo, error) {
+	if a.lockfile != nil {
+		return a.lockfile, nil
+	}
+
+	lockfilePath := lcu.GetLockfilePath()
+	log.Printf("Checking for lockfile at: %s", lockfilePath)
+	return lcu.ParseLockfile(lockfilePath)
+}
+
+// ensureConnected returns the connected client, connecting on demand if the connection loop hasn't yet
+func (a *App) ensureConnected() (*lcu.Client, error) {
+	if client := a.LCUClient(); client != nil {
+		return client, nil
+	}
+
+	lockfileInfo, err := a.locateLCU()
+	if err != nil {
+		return nil, fmt.Errorf("League client not found. Please start League of Legends first: %w", err)
+	}
+	client, err := lcu.NewClient(lockfileInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to League client: %w", err)
+	}
+	client.Recorder = a.recorder
+
+	a.mu.Lock()
+	a.lcuClient = client
+	a.mu.Unlock()
+	return client, nil
+}
+
+// GenerateFromLastMatch regenerates messages for the match on the post-game screen
+func (a *App) GenerateFromLastMatch() error {
+	client, err := a.ensureConnected()
+	if err != nil {
+		return err
+	}
+
+	// Check if we're in EndOfGame phase
+	phaseData, err := client.Get("/lol-gameflow/v1/gameflow-phase")
+	if err == nil {
+		var phase string
+		if err := json.Unmarshal(phaseData, &phase); err == nil && phase != "EndOfGame" {
+			log.Printf("Current phase: %s (not EndOfGame)", phase)
+			return fmt.Errorf("not in post-game screen. Please finish a match first or wait for the post-game screen")
+		}
+	}
+
+	// Small delay to ensure stats are ready
+	time.Sleep(1 * time.Second)
+
+	// Allow the same game to be processed again
+	a.mu.Lock()
+	a.lastGameID = ""
+	a.mu.Unlock()
+
+	return a.HandleEndOfGame()
+}
+
+// checkActiveGameOnStartup checks if a game is currently in progress when app starts
+func (a *App) checkActiveGameOnStartup(client *lcu.Client) {
+	a.mu.RLock()
+	goldMonitor, objectiveMonitor, timelineRecorder := a.goldMonitor, a.objectiveMonitor, a.timelineRecorder
+	a.mu.RUnlock()
+
+	// Check gameflow phase first
+	if phaseData, err := client.Get("/lol-gameflow/v1/gameflow-phase"); err == nil {
+		var phase string
+		if err := json.Unmarshal(phaseData, &phase); err == nil {
+			a.setPhase(phase)
+
+			if phase == "ChampSelect" {
+				log.Println("Detected champion select on startup, suggesting openers...")
+				go a.startChampSelect(client)
+				return
+			}
+
+			// Check if game is in progress
+			if phase == "InProgress" || phase == "GameStart" {
+				log.Printf("Detected active game on startup (phase: %s), starting gold monitor...", phase)
+
+				// Small delay to ensure game is fully loaded
+				time.Sleep(2 * time.Second)
+				a.applyGameProfile(client, goldMonitor)
+
+				// Start gold monitoring
+				if goldMonitor != nil && !goldMonitor.IsRunning() {
+					goldMonitor.Reset() // Reset thresholds for new game
+					goldMonitor.Start()
+				}
+				if objectiveMonitor != nil && !objectiveMonitor.IsRunning() {
+					objectiveMonitor.Start() // Catches up on the game's events so far
+				}
+				if timelineRecorder != nil && !timelineRecorder.IsRunning() {
+					timelineRecorder.Start() // Records the rest of the game; earlier minutes are missing
+				}
+				return
+			}
+		}
+	}
+
+	// Also try to check live game data directly
+	if inProgress, err := client.IsGameInProgress(); err == nil && inProgress {
+		log.Println("Detected active game via live game data, starting gold monitor...")
+		if goldMonitor != nil && !goldMonitor.IsRunning() {
+			goldMonitor.Reset()
+			goldMonitor.Start()
+		}
+		if objectiveMonitor != nil && !objectiveMonitor.IsRunning() {
+			objectiveMonitor.Start()
+		}
+		if timelineRecorder != nil && !timelineRecorder.IsRunning() {
+			timelineRecorder.Start()
+		}
+	}
+}
+
+// recentMatchWindow is how long after a game ends it is still processed on startup
+const recentMatchWindow = 5 * time.Minute
+
+// checkRecentMatch checks if the player is in post-match screen or had a recent match
+func (a *App) checkRecentMatch(client *lcu.Client) {
+	// First, check current gameflow phase
+	if phaseData, err := client.Get("/lol-gameflow/v1/gameflow-phase"); err == nil {
+		var phase string
+		if err := json.Unmarshal(phaseData, &phase); err == nil {
+			a.setPhase(phase)
+
+			if phase == "EndOfGame" {
+				log.Println("Detected EndOfGame phase on startup, processing...")
+				go func() {
+					time.Sleep(2 * time.Second) // Small delay to ensure stats are ready
+					if err := a.HandleEndOfGame(); err != nil {
+						log.Printf("Error processing EndOfGame on startup: %v", err)
+					}
+				}()
+				return // Already handling EndOfGame, no need to check match history
+			}
+		}
+	}
+
+	// Check for recent match history (within last 5 minutes)
+	// This catches cases where the game ended but we're no longer in EndOfGame phase
+	if recentMatch, err := client.GetRecentMatchHistory(); err == nil && recentMatch != nil {
+		maxAge := recentMatchWindow
+		if lcu.IsRecentMatch(recentMatch.EndTimestamp(), maxAge) {
+			gameEndTime := time.Unix(recentMatch.EndTimestamp()/1000, 0)
+			age := time.Since(gameEndTime)
+			log.Printf("Detected recent match ended %v ago (within %v window), attempting to process...", age, maxAge)
+
+			// Try to fetch EoG stats - they might still be available
+			go func() {
+				time.Sleep(1 * time.Second) // Small delay
+				if err := a.HandleEndOfGame(); err != nil {
+					log.Printf("Could not process recent match (stats may no longer be available): %v", err)
+				}
+			}()
+		} else if recentMatch != nil {
+			gameEndTime := time.Unix(recentMatch.EndTimestamp()/1000, 0)
+			age := time.Since(gameEndTime)
+			log.Printf("Most recent match ended %v ago (outside %v window)", age, maxAge)
+		}
+	} else if err != nil {
+		log.Printf("Could not check match history: %v", err)
+	}
+}
+
+// handlePhaseChange manages gold monitor based on game phase changes
+func (a *App) handlePhaseChange(newPhase, oldPhase string) {
+	a.setPhase(newPhase)
+
+	a.mu.RLock()
+	goldMonitor, clutchMonitor, objectiveMonitor := a.goldMonitor, a.clutchMonitor, a.objectiveMonitor
+	timelineRecorder := a.timelineRecorder
+	client := a.lcuClient
+	a.mu.RUnlock()
+
+	log.Printf("Phase change: %s -> %s", oldPhase, newPhase)
+
+	// Track our party while it can still be read from the lobby
+	if client != nil && (newPhase == "Lobby" || newPhase == "Matchmaking" || newPhase == "ReadyCheck" || newPhase == "ChampSelect") {
+		go a.refreshParty(client)
+	}
+
+	// Last look at the post-game chat for messages we copied before it closes
+	if oldPhase == "EndOfGame" && newPhase != "EndOfGame" && client != nil {
+		if set := a.LastMessageSet(); set != nil {
+			go set.checkSent(client)
+		}
+	}
+
+	// Suggest openers while we pick, and stop once champion select is over (the game starts or someone dodges)
+	if newPhase == "ChampSelect" && oldPhase != "ChampSelect" && client != nil {
+		go a.startChampSelect(client)
+	} else if oldPhase == "ChampSelect" && newPhase != "ChampSelect" {
+		a.endChampSelect()
+	}
+
+	// Start monitors when game starts
+	if (newPhase == "InProgress" || newPhase == "GameStart") &&
+		(oldPhase != "InProgress" && oldPhase != "GameStart") {
+		log.Printf("Game started (phase: %s), starting monitors...", newPhase)
+		if client != nil {
+			a.applyGameProfile(client, goldMonitor)
+		}
+
+		// Start gold monitor
+		if goldMonitor != nil {
+			if !goldMonitor.IsRunning() {
+				goldMonitor.Reset()
+				goldMonitor.Start()
+				log.Printf("Gold monitor started successfully")
+			} else {
+				log.Printf("Gold monitor already running")
+			}
+		} else {
+			log.Printf("ERROR: Gold monitor is nil!")
+		}
+
+		// Start objective timer monitor
+		if objectiveMonitor != nil && !objectiveMonitor.IsRunning() {
+			objectiveMonitor.Start()
+		}
+
+		// Start clutch monitor
+		if clutchMonitor != nil {
+			if !clutchMonitor.IsRunning() {
+				clutchMonitor.Reset()
+				clutchMonitor.Start()
+				log.Printf("Clutch monitor started successfully")
+			} else {
+				log.Printf("Clutch monitor already running")
+			}
+		}
+
+		// Start recording the game's timeline
+		if timelineRecorder != nil && !timelineRecorder.IsRunning() {
+			timelineRecorder.Start()
+		}
+	}
+
+	// Stop monitors when game ends
+	if (newPhase == "EndOfGame" || newPhase == "WaitingForStats" || newPhase == "PreEndOfGame") &&
+		(oldPhase == "InProgress" || oldPhase == "GameStart") {
+		log.Printf("Game ended (phase: %s), stopping monitors...", newPhase)
+
+		// Stop gold monitor
+		if goldMonitor != nil && goldMonitor.IsRunning() {
+			goldMonitor.Stop()
+		}
+
+		// Stop objective timer monitor
+		if objectiveMonitor != nil && objectiveMonitor.IsRunning() {
+			objectiveMonitor.Stop()
+		}
+
+		// Stop clutch monitor
+		if clutchMonitor != nil && clutchMonitor.IsRunning() {
+			clutchMonitor.Stop()
+			log.Printf("Clutch monitor stopped - collected %d clutch events", len(clutchMonitor.GetStats()))
+		}
+
+		// Stop the timeline recorder; the recording is kept for the post-game analysis
+		if timelineRecorder != nil && timelineRecorder.IsRunning() {
+			timelineRecorder.Stop()
+		}
+	}
+
+	// Also stop if we're back in lobby/champ select
+	if (newPhase == "Lobby" || newPhase == "ChampSelect" || newPhase == "ReadyCheck") &&
+		(oldPhase == "InProgress" || oldPhase == "GameStart") {
+		log.Printf("Left game (phase: %s), stopping gold monitor...", newPhase)
+		if goldMonitor != nil && goldMonitor.IsRunning() {
+			goldMonitor.Stop()
+		}
+		if objectiveMonitor != nil && objectiveMonitor.IsRunning() {
+			objectiveMonitor.Stop()
+		}
+		if timelineRecorder != nil && timelineRecorder.IsRunning() {
+			timelineRecorder.Stop()
+		}
+	}
+}
+
+// applyGameProfile picks the profile for the game that is starting and applies its gold settings
+func (a *App) applyGameProfile(client *lcu.Client, goldMonitor *monitor.GoldMonitor) {
+	profile := a.selectProfileForSession(client)
+	if goldMonitor != nil {
+		gold := a.configForProfile(profile).GoldAnnouncements
+		goldMonitor.UpdateSettings(&gold)
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"fmt"
+	"lol-kind-bot/llm"
+	"strings"
+	"sync"
+)
+
+// ConsoleUI prints everything to stdout (used for headless runs)
+type ConsoleUI struct{}
+
+func (ConsoleUI) ShowToast(title, message string) {
+	fmt.Printf("[%s] %s\n", title, message)
+}
+
+// ShowMessages prints the instant messages, then prints them again once the better ones are in
+func (ConsoleUI) ShowMessages(set *MessageSet) {
+	printSuggestions(set)
+	if !set.Generating() {
+		return
+	}
+
+	var once sync.Once
+	done := make(chan struct{})
+	printUpdated := func() {
+		if set.Generating() {
+			return
+		}
+		once.Do(func() {
+			fmt.Println("Updated messages:")
+			printSuggestions(set)
+			close(done)
+		})
+	}
+	stop := set.OnChange(printUpdated)
+	printUpdated() // Generation may have finished before we subscribed
+	go func() {
+		<-done
+		stop()
+	}()
+}
+
+// ShowReply prints a post-game chat line and the replies suggested for it
+func (ConsoleUI) ShowReply(set *MessageSet, reply llm.ChatReply) {
+	fmt.Printf("%s (%s): %s\n", reply.From, reply.Intent, reply.Text)
+	for _, text := range reply.Replies {
+		fmt.Printf("   -> %s\n", text)
+	}
+}
+
+// ShowOpeners prints the champion select openers, and again whenever they are rewritten
+func (ConsoleUI) ShowOpeners(set *OpenerSet) {
+	var mu sync.Mutex
+	printed := ""
+	printOpeners := func() {
+		openers := set.Openers()
+		mu.Lock()
+		defer mu.Unlock()
+		if key := strings.Join(openers, "\n"); key != printed && set.Sent() == "" {
+			printed = key
+			fmt.Println("Champ select openers:")
+			for i, opener := range openers {
+				fmt.Printf("%d. %s\n", i+1, opener)
+			}
+		}
+	}
+	printOpeners()
+	stop := set.OnChange(printOpeners)
+	go func() {
+		<-set.Done()
+		stop()
+	}()
+}
+
+// printSuggestions prints each message with the reason it was suggested
+func printSuggestions(set *MessageSet) {
+	for i, s := range set.Suggestions() {
+		fmt.Printf("%d. %s\n   (%s)\n", i+1, s.Text, s.Reason())
+	}
+}
@@ -0,0 +1,532 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/config"
+	"lol-kind-bot/eog"
+	"lol-kind-bot/history"
+	"lol-kind-bot/lcu"
+	"lol-kind-bot/llm"
+	"lol-kind-bot/monitor"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+// timelinePollInterval is how often the live game is sampled for the game's timeline
+const timelinePollInterval = 10 * time.Second
+
+// HandleEndOfGame fetches the EoG stats, analyzes the game, generates messages and presents them
+func (a *App) HandleEndOfGame() error {
+	// Prevent concurrent processing of the same game
+	a.eogMu.Lock()
+	defer a.eogMu.Unlock()
+
+	// Rate limiting: prevent processing if we just processed recently (additional safety)
+	if !a.lastEoGTime.IsZero() && time.Since(a.lastEoGTime) < 5*time.Second {
+		log.Printf("Skipping EoG processing - too soon after last processing (%v ago)", time.Since(a.lastEoGTime))
+		return nil
+	}
+
+	lcuClient := a.LCUClient()
+	if lcuClient == nil {
+		return fmt.Errorf("LCU client not available")
+	}
+
+	log.Println("Processing EndOfGame event...")
+	a.lastEoGTime = time.Now()
+
+	// Fetch EoG stats first
+	data, err := lcuClient.Get("/lol-end-of-game/v1/eog-stats-block")
+	if err != nil {
+		return fmt.Errorf("failed to fetch EoG stats: %w", err)
+	}
+
+	// Extract game ID to prevent duplicate processing and verify it's the latest match
+	var rawData map[string]interface{}
+	var currentGameID string
+	var currentGameIDInt int64
+
+	if err := json.Unmarshal(data, &rawData); err == nil {
+		if gameIDRaw, ok := rawData["gameId"]; ok {
+			// Handle both string and numeric game IDs
+			switch v := gameIDRaw.(type) {
+			case string:
+				currentGameID = v
+			case float64:
+				currentGameIDInt = int64(v)
+				currentGameID = fmt.Sprintf("%d", currentGameIDInt)
+			case int64:
+				currentGameIDInt = v
+				currentGameID = fmt.Sprintf("%d", v)
+			default:
+				currentGameID = fmt.Sprintf("%v", gameIDRaw)
+			}
+			log.Printf("EoG stats game ID: %s", currentGameID)
+
+			// Check if this game was already processed
+			a.mu.RLock()
+			alreadyProcessed := currentGameID == a.lastGameID
+			a.mu.RUnlock()
+			if alreadyProcessed {
+				log.Printf("Game %s already processed, skipping duplicate event", currentGameID)
+				return nil
+			}
+
+			// Verify this is the latest match by checking match history
+			// This ensures we don't process old matches
+			recentMatch, err := lcuClient.GetRecentMatchHistory()
+			if err == nil && recentMatch != nil && recentMatch.GameID != 0 {
+				latestMatchID := fmt.Sprintf("%d", recentMatch.GameID)
+				gameEndTime := time.Unix(recentMatch.EndTimestamp()/1000, 0)
+				age := time.Since(gameEndTime)
+				log.Printf("Latest match from history: GameID=%s, ended %v ago", latestMatchID, age)
+
+				// Compare game IDs (handle both string and int64 comparisons)
+				if currentGameID != latestMatchID {
+					// Also try numeric comparison if both are numeric
+					if currentGameIDInt != 0 && currentGameIDInt != recentMatch.GameID {
+						log.Printf("WARNING: Game ID mismatch! EoG stats ID=%s (%d), Latest match ID=%s (%d). Skipping to avoid processing old match.",
+							currentGameID, currentGameIDInt, latestMatchID, recentMatch.GameID)
+						return fmt.Errorf("game ID mismatch - not processing old match (EoG: %s, Latest: %s)",
+							currentGameID, latestMatchID)
+					} else if currentGameIDInt == 0 {
+						// If we couldn't parse as int64, still compare strings
+						log.Printf("WARNING: Game ID mismatch! EoG stats ID=%s, Latest match ID=%s. Skipping to avoid processing old match.",
+							currentGameID, latestMatchID)
+						return fmt.Errorf("game ID mismatch - not processing old match (EoG: %s, Latest: %s)",
+							currentGameID, latestMatchID)
+					}
+				}
+				log.Printf("Verified: Game ID %s matches latest match", currentGameID)
+			} else if recentMatch == nil {
+				log.Printf("No recent match history available for verification")
+			}
+
+			// Mark this game as processed
+			a.mu.Lock()
+			a.lastGameID = currentGameID
+			a.mu.Unlock()
+			log.Printf("Processing game ID: %s", currentGameID)
+		}
+	}
+
+	// Log raw response for debugging
+	log.Printf("Raw EoG stats response length: %d bytes", len(data))
+	if len(data) > 0 {
+		// Parse as generic JSON to inspect structure
+		var rawData map[string]interface{}
+		if err := json.Unmarshal(data, &rawData); err == nil {
+			keys := getKeys(rawData)
+			log.Printf("Top-level keys in EoG response (%d keys): %v", len(keys), keys)
+
+			// Check for participants in various possible locations
+			if statsBlock, ok := rawData["statsBlock"]; ok {
+				log.Printf("Found statsBlock field")
+				if statsBlockMap, ok := statsBlock.(map[string]interface{}); ok {
+					blockKeys := getKeys(statsBlockMap)
+					log.Printf("statsBlock keys (%d keys): %v", len(blockKeys), blockKeys)
+					if participants, ok := statsBlockMap["participants"]; ok {
+						if participantsArr, ok := participants.([]interface{}); ok {
+							log.Printf("Found %d participants in statsBlock", len(participantsArr))
+						}
+					}
+				}
+			}
+			if teams, ok := rawData["teams"]; ok {
+				log.Printf("Found teams field")
+				if teamsArr, ok := teams.([]interface{}); ok {
+					log.Printf("Found %d teams", len(teamsArr))
+					for i, team := range teamsArr {
+						if teamMap, ok := team.(map[string]interface{}); ok {
+							teamKeys := getKeys(teamMap)
+							log.Printf("Team %d keys: %v", i, teamKeys)
+							if players, ok := teamMap["players"]; ok {
+								if playersArr, ok := players.([]interface{}); ok {
+									log.Printf("Team %d has %d players", i, len(playersArr))
+									if len(playersArr) > 0 {
+										// Log first player structure in detail
+										if firstPlayer, ok := playersArr[0].(map[string]interface{}); ok {
+											playerKeys := getKeys(firstPlayer)
+											log.Printf("Team %d first player keys (%d keys): %v", i, len(playerKeys), playerKeys)
+											// Log some key values to understand structure
+											if summonerName, ok := firstPlayer["summonerName"]; ok {
+												log.Printf("  summonerName: %v", summonerName)
+											}
+											// Check if stats are nested
+											if statsRaw, ok := firstPlayer["stats"]; ok {
+												if statsMap, ok := statsRaw.(map[string]interface{}); ok {
+													log.Printf("  Found nested stats with keys: %v", func() []string {
+														keys := make([]string, 0, len(statsMap))
+														for k := range statsMap {
+															keys = append(keys, k)
+														}
+														return keys
+													}())
+													if dmg, ok := statsMap["totalDamageDealtToChampions"].(float64); ok {
+														log.Printf("  totalDamageDealtToChampions in stats: %.0f", dmg)
+													}
+												}
+											} else {
+												// Check top-level
+												if dmg, ok := firstPlayer["totalDamageDealtToChampions"].(float64); ok {
+													log.Printf("  totalDamageDealtToChampions at top-level: %.0f", dmg)
+												}
+											}
+											if riotIdGameName, ok := firstPlayer["riotIdGameName"]; ok {
+												log.Printf("  riotIdGameName: %v", riotIdGameName)
+											}
+											if displayName, ok := firstPlayer["displayName"]; ok {
+												log.Printf("  displayName: %v", displayName)
+											}
+											if championName, ok := firstPlayer["championName"]; ok {
+												log.Printf("  championName: %v", championName)
+											}
+											if championId, ok := firstPlayer["championId"]; ok {
+												log.Printf("  championId: %v", championId)
+											}
+										}
+									}
+								}
+							}
+							if participants, ok := teamMap["participants"]; ok {
+								if participantsArr, ok := participants.([]interface{}); ok {
+									log.Printf("Team %d has %d participants", i, len(participantsArr))
+								}
+							}
+						}
+					}
+				}
+			}
+			if participants, ok := rawData["participants"]; ok {
+				log.Printf("Found participants at top level, type: %T", participants)
+				if participantsArr, ok := participants.([]interface{}); ok {
+					log.Printf("Found %d participants at top level", len(participantsArr))
+				}
+			}
+		} else {
+			log.Printf("Failed to parse as JSON map: %v", err)
+		}
+	}
+
+	gameSummary, err := a.SummarizeEoG(data)
+	if err != nil {
+		return err
+	}
+
+	// Show offline messages right away, then swap in the agentic ones as they finish
+	set := a.recordResult(currentGameID, gameSummary, a.instantSuggestions(gameSummary))
+	a.upgradeMessages(set)
+	set.watchChat()
+
+	return a.presentMessages(set)
+}
+
+// Regenerate generates fresh messages for the last processed game, or for the post-game screen if none was processed yet
+func (a *App) Regenerate() error {
+	set := a.LastMessageSet()
+	if set == nil {
+		return a.GenerateFromLastMatch()
+	}
+
+	if _, err := set.RegenerateAll(""); err != nil {
+		return err
+	}
+	return a.presentMessages(set)
+}
+
+// LastMessageSet returns the messages for the most recently processed game (nil if none yet)
+func (a *App) LastMessageSet() *MessageSet {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.lastResult
+}
+
+// recordResult keeps the result as the latest game, saves it to history and publishes the
+// summary. The messages are published once generation finishes.
+func (a *App) recordResult(gameID string, gameSummary *analyzer.GameSummary, suggestions []llm.Suggestion) *MessageSet {
+	set := a.newMessageSet(gameID, gameSummary, suggestions)
+	messages := set.Messages()
+
+	a.mu.Lock()
+	a.lastResult = set
+	a.mu.Unlock()
+
+	a.publish(EventGameSummary, gameSummary)
+
+	entry := history.Entry{GameID: gameID, Summary: gameSummary, Messages: messages, Suggestions: suggestions}
+	if gameSummary.HasTimeline {
+		entry.Timeline = a.recordedTimeline(int(gameSummary.GameDurationMinutes * 60))
+	}
+	if err := a.history.Add(entry); err != nil {
+		log.Printf("Failed to save game to history: %v", err)
+	}
+	return set
+}
+
+// presentMessages logs the messages, auto-copies the first one and shows them in the UI
+func (a *App) presentMessages(set *MessageSet) error {
+	cfg := a.configForProfile(set.Summary.Profile)
+	messages := set.Messages()
+
+	// Display messages
+	log.Println("\n=== Suggested Post-Game Messages ===")
+	for i, msg := range messages {
+		log.Printf("%d. %s", i+1, msg)
+	}
+	log.Println("==================================")
+
+	// Auto-copy first message to clipboard if enabled
+	if cfg.AutoCopyToClipboard && len(messages) > 0 {
+		if err := CopyToClipboard(messages[0]); err != nil {
+			log.Printf("Failed to copy to clipboard: %v", err)
+		} else {
+			set.setAutoCopied(messages[0])
+			log.Printf("Copied first message to clipboard: %s", messages[0])
+			// Show toast notification
+			a.ui.ShowToast("LoL Kind Bot", "First message copied to clipboard!")
+		}
+	} else if len(messages) > 0 {
+		// Show toast even if auto-copy is disabled
+		a.ui.ShowToast("LoL Kind Bot", "Post-game messages ready!")
+	}
+
+	// Show popup window with message suggestions
+	a.ui.ShowMessages(set)
+
+	return nil
+}
+
+// SummarizeEoG parses raw EoG stats and analyzes them into a game summary
+func (a *App) SummarizeEoG(data []byte) (*analyzer.GameSummary, error) {
+	cfg := a.Config()
+	stats, err := eog.ParseEoGStats(data)
+	if err != nil {
+		log.Printf("Failed to parse EoG stats. Raw data: %s", string(data))
+		return nil, fmt.Errorf("failed to parse EoG stats: %w", err)
+	}
+
+	if cfg.EnableDetailedLogging {
+		statsJSON, _ := json.MarshalIndent(stats, "", "  ")
+		log.Printf("EoG Stats:\n%s", string(statsJSON))
+	}
+
+	// Check if we have participants
+	if len(stats.Participants) == 0 {
+		log.Printf("EoG stats parsed but no participants found. GameDurationSeconds: %d", stats.GameDurationSeconds)
+		// Check if the response was empty or just had no participants
+		if len(data) == 0 || string(data) == "{}" || string(data) == "null" {
+			return nil, fmt.Errorf("end-of-game stats endpoint returned empty data. Try waiting a few seconds after the game ends.")
+		}
+		return nil, fmt.Errorf("no game data available (empty participants). Make sure you're in the post-game screen or have finished a match recently")
+	}
+
+	// Apply the profile for this game's queue and mode
+	profile := a.selectProfile(a.gameContext(stats.QueueType, stats.GameMode))
+	cfg = cfg.WithProfile(profile)
+
+	// Get clutch stats if monitor was running
+	var clutchStats map[string]*monitor.ClutchStats
+	a.mu.RLock()
+	clutchMonitor := a.clutchMonitor
+	a.mu.RUnlock()
+	if clutchMonitor != nil && clutchMonitor.IsRunning() {
+		clutchStats = clutchMonitor.GetStats()
+		if cfg.EnableDebugLogging && len(clutchStats) > 0 {
+			log.Printf("[CLUTCH] Collected clutch stats for %d champions", len(clutchStats))
+			for champ, stats := range clutchStats {
+				log.Printf("[CLUTCH] %s: LivesSaved=%d, TimesSaved=%d, CriticalSaves=%d",
+					champ, stats.LivesSaved, stats.TimesSaved,
+					func() int {
+						count := 0
+						for _, e := range stats.Events {
+							if e.WasCritical {
+								count++
+							}
+						}
+						return count
+					}())
+			}
+		}
+	}
+
+	// Fill in team objectives from match history, which the EoG stats leave out
+	game := a.matchGame(stats.GameID)
+	if game != nil {
+		applyMatchObjectives(stats, game)
+	}
+
+	// Analyze game
+	gameSummary, err := analyzer.AnalyzeGame(stats, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze game: %w", err)
+	}
+
+	if gameSummary == nil {
+		return nil, fmt.Errorf("no game summary generated (empty participants?)")
+	}
+	gameSummary.Profile = profile
+
+	// Integrate clutch stats into game summary
+	if clutchStats != nil && len(clutchStats) > 0 {
+		analyzer.IntegrateClutchStats(gameSummary, clutchStats)
+		if cfg.EnableDebugLogging {
+			log.Printf("[CLUTCH] Integrated clutch stats into game summary")
+		}
+	}
+
+	// Use the game's timeline for comebacks, lead swings, turning points and the game's story:
+	// the live recording, or match history's if the game wasn't recorded
+	if timeline := a.recordedTimeline(stats.GameDurationSeconds); timeline != nil {
+		analyzer.IntegrateTimeline(gameSummary, timeline)
+		log.Printf("Integrated live game timeline (%d frames, %d events)", len(timeline.Frames), len(timeline.Events))
+	} else if timeline := a.matchTimeline(stats.GameID); timeline != nil {
+		var teams map[int]int
+		if game != nil {
+			teams = game.ParticipantTeams()
+		}
+		analyzer.IntegrateMatchTimeline(gameSummary, timeline, teams)
+		log.Printf("Integrated match-history timeline (%d frames)", len(timeline.Frames))
+	}
+	if story := gameSummary.Story; story != nil {
+		log.Printf("Game story (%s): %s", story.Arc, story.Summary)
+	}
+
+	return gameSummary, nil
+}
+
+// matchTimeline fetches a finished game's timeline from match history (nil if unavailable)
+func (a *App) matchTimeline(gameID int64) *lcu.GameTimeline {
+	client := a.LCUClient()
+	if client == nil || gameID == 0 {
+		return nil
+	}
+	timeline, err := client.GetGameTimeline(gameID)
+	if err != nil {
+		log.Printf("No timeline for game %d, the game's story will be inferred from final stats: %v", gameID, err)
+		return nil
+	}
+	return timeline
+}
+
+// recordedTimeline returns the live recording of a game that lasted durationSec seconds, or nil
+// if the game wasn't recorded (e.g. the app started after it ended)
+func (a *App) recordedTimeline(durationSec int) *monitor.Timeline {
+	a.mu.RLock()
+	timelineRecorder := a.timelineRecorder
+	a.mu.RUnlock()
+	if timelineRecorder == nil {
+		return nil
+	}
+	if timeline := timelineRecorder.Timeline(); timeline.Matches(durationSec) {
+		return timeline
+	}
+	return nil
+}
+
+// GenerateMessages runs the agentic LLM pipeline, falling back to canned messages on failure
+func (a *App) GenerateMessages(gameSummary *analyzer.GameSummary) []string {
+	return llm.SuggestionTexts(a.GenerateSuggestions(gameSummary))
+}
+
+// GenerateSuggestions is GenerateMessages, keeping the reasoning behind each message
+func (a *App) GenerateSuggestions(gameSummary *analyzer.GameSummary) []llm.Suggestion {
+	return a.generateSuggestions(gameSummary, "")
+}
+
+// agenticSystem returns the message pipeline for a game's profile. tone overrides the configured tone; empty keeps it.
+func (a *App) agenticSystem(gameSummary *analyzer.GameSummary, tone string) (*llm.AgenticSystem, bool) {
+	cfg := a.configForProfile(gameSummary.Profile)
+	if tone != "" {
+		cfg.LLMSettings.Tone = tone
+	}
+	agentic := llm.NewAgenticSystem(a.llmClientFor(cfg, gameSummary.Profile), gameSummary, &cfg.LLMSettings)
+	a.applyFeedback(agentic, cfg, tone == "")
+	return agentic, cfg.EnableDebugLogging
+}
+
+func (a *App) generateSuggestions(gameSummary *analyzer.GameSummary, tone string) []llm.Suggestion {
+	cfg := a.configForProfile(gameSummary.Profile)
+	logGenerationInputs(cfg, gameSummary)
+
+	agenticSystem, enableDebug := a.agenticSystem(gameSummary, tone)
+	suggestions, err := agenticSystem.GenerateSuggestions(enableDebug)
+	if err != nil {
+		log.Printf("Agentic message generation failed: %v. Using offline messages.", err)
+		suggestions = llm.OfflineSuggestions(gameSummary, &cfg.LLMSettings)
+	}
+
+	return suggestions
+}
+
+// logGenerationInputs logs the game summary and checks its standout flags before message generation
+func logGenerationInputs(cfg *config.Config, gameSummary *analyzer.GameSummary) {
+	summaryJSON, _ := json.MarshalIndent(gameSummary, "", "  ")
+
+	// Debug logging for standout flags and damage accuracy verification
+	if cfg.EnableDebugLogging {
+		log.Printf("[DEBUG] === DAMAGE ACCURACY CHECK ===")
+		log.Printf("[DEBUG] All players' damage values:")
+		maxDamageSeen := 0
+		maxDamagePlayer := ""
+		for i, player := range gameSummary.Players {
+			log.Printf("[DEBUG] Player %d (%s): totalDamage=%d, highestDamageInGame=%v, highestDamageOnTeam=%v, afk=%v",
+				i, player.Champion, player.TotalDamage, player.HighestDamageInGame, player.HighestDamageOnTeam, player.Afk)
+			if !player.Afk && player.TotalDamage > maxDamageSeen {
+				maxDamageSeen = player.TotalDamage
+				maxDamagePlayer = player.Champion
+			}
+		}
+		log.Printf("[DEBUG] Max damage in game: %d (by %s)", maxDamageSeen, maxDamagePlayer)
+
+		// Verify flags match actual max damage
+		highestDamagePlayers := []string{}
+		for _, player := range gameSummary.Players {
+			if player.HighestDamageInGame && !player.Afk {
+				highestDamagePlayers = append(highestDamagePlayers, player.Champion)
+				if player.TotalDamage != maxDamageSeen {
+					log.Printf("[ERROR] INCONSISTENCY: %s marked as highestDamageInGame but has %d damage, max is %d!",
+						player.Champion, player.TotalDamage, maxDamageSeen)
+				} else {
+					log.Printf("[DEBUG] ✓ %s correctly marked as highestDamageInGame with %d damage", player.Champion, player.TotalDamage)
+				}
+			}
+		}
+		if len(highestDamagePlayers) == 0 && maxDamageSeen > 0 {
+			log.Printf("[ERROR] INCONSISTENCY: No player marked as highestDamageInGame but max damage is %d!", maxDamageSeen)
+		}
+		log.Printf("[DEBUG] === END DAMAGE CHECK ===")
+	}
+
+	if cfg.EnableDetailedLogging {
+		log.Printf("Game Summary:\n%s", string(summaryJSON))
+	}
+
+	// Use agentic system for message generation
+	if cfg.EnableDebugLogging {
+		log.Printf("[AGENTIC] Starting agentic message generation system")
+		log.Printf("[AGENTIC] Game Summary JSON (first 2000 chars):\n%s", func() string {
+			jsonStr := string(summaryJSON)
+			if len(jsonStr) > 2000 {
+				return jsonStr[:2000] + "..."
+			}
+			return jsonStr
+		}())
+	}
+}
+
+// CopyToClipboard copies text to the system clipboard
+func CopyToClipboard(text string) error {
+	return clipboard.WriteAll(text)
+}
+
+func getKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
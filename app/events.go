@@ -0,0 +1,80 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types published on the App's event bus
+const (
+	EventConnected       = "connected"
+	EventDisconnected    = "disconnected"
+	EventPhaseChanged    = "phaseChanged"
+	EventListening       = "listening"
+	EventMuted           = "muted" // In-game announcements were muted or unmuted
+	EventGameSummary     = "gameSummary"
+	EventMessages        = "messages"        // The final messages for a game, published once
+	EventMessagesUpdated = "messagesUpdated" // A better message was swapped in while generation continues, or the messages were regenerated
+	EventChatReply       = "chatReply"       // Replies suggested for a line in the post-game chat
+	EventOpeners         = "openers"         // Openers suggested for champion select, rewritten as picks come in
+	EventGoldMilestone   = "goldMilestone"
+	EventObjectiveTimer  = "objectiveTimer" // An objective spawn, inhibitor respawn or buff expiry was announced
+	EventConfigChanged   = "configChanged"
+	EventProfileChanged  = "profileChanged"
+	EventMessageChoice   = "messageChoice"
+)
+
+// Event is something that happened in the bot, delivered to subscribers
+type Event struct {
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// eventBus fans events out to subscribers without ever blocking the publisher
+type eventBus struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]chan Event
+}
+
+// Subscribe returns a channel of bot events and a function to unsubscribe.
+// Slow subscribers miss events rather than stalling the bot.
+func (a *App) Subscribe() (<-chan Event, func()) {
+	b := &a.events
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs == nil {
+		b.subs = make(map[int]chan Event)
+	}
+	id := b.nextID
+	b.nextID++
+	ch := make(chan Event, 32)
+	b.subs[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			close(ch)
+		})
+	}
+}
+
+// publish delivers an event to all current subscribers
+func (a *App) publish(eventType string, data interface{}) {
+	event := Event{Type: eventType, Time: time.Now(), Data: data}
+
+	b := &a.events
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- event:
+		default: // Subscriber is behind, drop the event for it
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package app
+
+import (
+	"log"
+	"lol-kind-bot/config"
+	"lol-kind-bot/feedback"
+	"lol-kind-bot/history"
+	"lol-kind-bot/lcu"
+	"lol-kind-bot/llm"
+	"strings"
+	"time"
+)
+
+const (
+	sentCheckInterval = 5 * time.Second // How often the post-game chat is checked for a copied message
+	sentCheckTimeout  = 3 * time.Minute // How long after copying we keep looking for it
+)
+
+// applyFeedback makes the message pipeline use what our feedback says we like:
+// candidate re-ranking and example messages, plus tone (when adaptTone) and length
+func (a *App) applyFeedback(agentic *llm.AgenticSystem, cfg *config.Config, adaptTone bool) {
+	if !cfg.Feedback.Enabled {
+		return
+	}
+
+	prefs, err := a.feedback.Preferences()
+	if err != nil {
+		log.Printf("[FEEDBACK] Failed to load feedback: %v", err)
+		return
+	}
+	if prefs.Samples == 0 {
+		return
+	}
+
+	if adaptTone && cfg.Feedback.AdaptTone {
+		if tone := prefs.Tone(cfg.LLMSettings.Tone, cfg.Feedback.MinSamples); tone != cfg.LLMSettings.Tone {
+			log.Printf("[FEEDBACK] Using tone %q instead of %q based on %d ratings", tone, cfg.LLMSettings.Tone, prefs.Samples)
+			cfg.LLMSettings.Tone = tone
+		}
+	}
+	if cfg.Feedback.AdaptLength {
+		if length := prefs.MessageLength(cfg.LLMSettings.MaxMessageLength, cfg.Feedback.MinSamples); length != cfg.LLMSettings.MaxMessageLength {
+			log.Printf("[FEEDBACK] Using max message length %d instead of %d based on %d ratings", length, cfg.LLMSettings.MaxMessageLength, prefs.Samples)
+			cfg.LLMSettings.MaxMessageLength = length
+		}
+	}
+	agentic.SetPreferences(prefs)
+}
+
+// ExportFeedback returns every feedback record along with the preferences learned from them
+func (a *App) ExportFeedback() (*feedback.Export, error) {
+	return a.feedback.Export()
+}
+
+// ResetFeedback forgets all feedback, so generation goes back to the configured settings
+func (a *App) ResetFeedback() error {
+	if err := a.feedback.Reset(); err != nil {
+		return err
+	}
+	log.Printf("[FEEDBACK] Feedback reset")
+	return nil
+}
+
+// feedbackRecord builds a feedback record for a message, with the inputs it was generated from.
+// text is the message as it is now; the record keeps the suggested text and our edit separately.
+func (m *MessageSet) feedbackRecord(signal, text string) feedback.Record {
+	record := feedback.Record{GameID: m.GameID, Signal: signal, Message: m.originalText(text)}
+	if record.Message != text {
+		record.Edited = text
+	}
+
+	cfg := m.app.configForProfile(m.Summary.Profile)
+	record.Tone = cfg.LLMSettings.Tone
+	record.LanguageStyle = cfg.LLMSettings.LanguageStyle
+	record.MaxLength = cfg.LLMSettings.MaxMessageLength
+	record.QueueType = m.Summary.QueueType
+	record.Profile = m.Summary.Profile
+
+	m.mu.Lock()
+	var suggestion llm.Suggestion
+	found := false
+	for _, s := range m.suggestions {
+		if s.Text == text {
+			suggestion, found = s, true
+			break
+		}
+	}
+	m.mu.Unlock()
+	if !found {
+		return record
+	}
+
+	if suggestion.Tone != "" {
+		record.Tone = suggestion.Tone
+	}
+	record.Champion = suggestion.Champion
+	if t := suggestion.Testimony; t != nil {
+		record.Score = t.Score
+		if t.PlayerIndex >= 0 && t.PlayerIndex < len(m.Summary.Players) {
+			player := m.Summary.Players[t.PlayerIndex]
+			record.PlayerTags = player.Tags
+			record.Won = player.Team == m.Summary.WinningTeam
+		}
+	}
+	return record
+}
+
+// originalText returns the text a message was suggested as, undoing our edits
+func (m *MessageSet) originalText(text string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := map[string]bool{text: true}
+	for {
+		original, ok := m.originals[text]
+		if !ok || seen[original] {
+			return text
+		}
+		seen[original] = true
+		text = original
+	}
+}
+
+// addFeedback stores a feedback record if feedback is enabled
+func (m *MessageSet) addFeedback(record feedback.Record) {
+	if !m.app.Config().Feedback.Enabled {
+		return
+	}
+	if err := m.app.feedback.Add(record); err != nil {
+		log.Printf("[FEEDBACK] Failed to save feedback: %v", err)
+	}
+}
+
+// markUsed remembers that we used a message, so it isn't counted as dismissed
+func (m *MessageSet) markUsed(text string) {
+	m.mu.Lock()
+	m.used[text] = true
+	m.mu.Unlock()
+}
+
+// Rate records an explicit thumbs up or down for suggestion i
+func (m *MessageSet) Rate(i int, up bool) {
+	m.interact()
+	m.mu.Lock()
+	if i < 0 || i >= len(m.suggestions) {
+		m.mu.Unlock()
+		return
+	}
+	text := m.suggestions[i].Text
+	m.mu.Unlock()
+
+	signal, action := feedback.SignalThumbsDown, history.ChoiceThumbsDown
+	if up {
+		signal, action = feedback.SignalThumbsUp, history.ChoiceThumbsUp
+		m.markUsed(text)
+	}
+	m.addFeedback(m.feedbackRecord(signal, text))
+	m.save(history.Choice{Action: action, Text: text})
+}
+
+// Dismissed records every suggestion we neither used nor rated when the messages window closes
+func (m *MessageSet) Dismissed() {
+	m.mu.Lock()
+	var dismissed []string
+	for _, s := range m.suggestions {
+		if !m.used[s.Text] && !m.dismissed[s.Text] {
+			m.dismissed[s.Text] = true
+			dismissed = append(dismissed, s.Text)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, text := range dismissed {
+		m.addFeedback(m.feedbackRecord(feedback.SignalDismissed, text))
+	}
+	if len(dismissed) > 0 {
+		m.save(history.Choice{Action: history.ChoiceDismissed, Text: strings.Join(dismissed, "\n")})
+	}
+}
+
+// Sent records that a message showed up in our post-game chat
+func (m *MessageSet) Sent(text string) {
+	m.mu.Lock()
+	if m.sent[text] {
+		m.mu.Unlock()
+		return
+	}
+	m.sent[text] = true
+	m.used[text] = true
+	m.mu.Unlock()
+
+	log.Printf("[FEEDBACK] Message was sent in post-game chat: %s", text)
+	m.addFeedback(m.feedbackRecord(feedback.SignalSent, text))
+	m.save(history.Choice{Action: history.ChoiceSent, Text: text})
+}
+
+// watchSent checks the post-game chat for the messages we copied while we stay in EndOfGame
+func (m *MessageSet) watchSent() {
+	m.mu.Lock()
+	if m.watching {
+		m.mu.Unlock()
+		return
+	}
+	m.watching = true
+	m.mu.Unlock()
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			m.watching = false
+			m.mu.Unlock()
+		}()
+
+		deadline := time.Now().Add(sentCheckTimeout)
+		for time.Now().Before(deadline) {
+			time.Sleep(sentCheckInterval)
+			client := m.app.LCUClient()
+			if client == nil || m.app.CurrentPhase() != "EndOfGame" {
+				return
+			}
+			if m.checkSent(client) {
+				return
+			}
+		}
+	}()
+}
+
+// checkSent looks for our copied messages in the post-game chat and records the ones we sent.
+// It returns true once every copied message has been found.
+func (m *MessageSet) checkSent(client *lcu.Client) bool {
+	m.mu.Lock()
+	var pending []string
+	for text := range m.copied {
+		if !m.sent[text] {
+			pending = append(pending, text)
+		}
+	}
+	m.mu.Unlock()
+	if len(pending) == 0 {
+		return true
+	}
+
+	me, err := client.GetChatMe()
+	if err != nil {
+		log.Printf("[FEEDBACK] Failed to check post-game chat: %v", err)
+		return false
+	}
+	messages, err := client.GetPostGameMessages()
+	if err != nil {
+		log.Printf("[FEEDBACK] Failed to check post-game chat: %v", err)
+		return false
+	}
+
+	found := 0
+	for _, text := range pending {
+		for _, message := range messages {
+			if message.IsFrom(me) && sameMessage(message.Body, text) {
+				m.Sent(text)
+				found++
+				break
+			}
+		}
+	}
+	return found == len(pending)
+}
+
+// sameMessage reports whether a chat message is one of our suggestions, ignoring case,
+// surrounding whitespace and the chat's length limit cutting it short
+func sameMessage(chat, suggestion string) bool {
+	chat = strings.ToLower(strings.TrimSpace(chat))
+	suggestion = strings.ToLower(strings.TrimSpace(suggestion))
+	if chat == "" {
+		return false
+	}
+	return chat == suggestion || (len(chat) >= 20 && strings.HasPrefix(suggestion, chat))
+}
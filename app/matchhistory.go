@@ -0,0 +1,225 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/config"
+	"lol-kind-bot/eog"
+	"lol-kind-bot/history"
+	"lol-kind-bot/lcu"
+	"time"
+)
+
+// backfillGames is how many of our latest games are checked for ones missing from the history
+const backfillGames = 10
+
+// matchGame fetches a finished game's details from match history (nil if unavailable)
+func (a *App) matchGame(gameID int64) *lcu.MatchHistoryGame {
+	client := a.LCUClient()
+	if client == nil || gameID == 0 {
+		return nil
+	}
+	game, err := client.GetGame(gameID)
+	if err != nil {
+		log.Printf("No match details for game %d, team objectives will be left out: %v", gameID, err)
+		return nil
+	}
+	return game
+}
+
+// applyMatchObjectives fills in each team's dragons and barons from the game's match details,
+// which the EoG stats leave out
+func applyMatchObjectives(stats *eog.EoGStatsBlock, game *lcu.MatchHistoryGame) {
+	if len(game.Teams) == 0 {
+		return
+	}
+	if stats.TeamDragons == nil {
+		stats.TeamDragons = make(map[int]int, len(game.Teams))
+		for _, team := range game.Teams {
+			stats.TeamDragons[team.TeamID] = team.DragonKills
+		}
+	}
+	if stats.TeamBarons == nil {
+		stats.TeamBarons = make(map[int]int, len(game.Teams))
+		for _, team := range game.Teams {
+			stats.TeamBarons[team.TeamID] = team.BaronKills
+		}
+	}
+}
+
+// matchStats converts a game's match details into EoG stats, so a game we missed can be analyzed
+func matchStats(game *lcu.MatchHistoryGame, championNames map[int]string, queueType string) *eog.EoGStatsBlock {
+	stats := &eog.EoGStatsBlock{
+		GameID:              game.GameID,
+		GameDurationSeconds: game.GameDuration,
+		GameLength:          game.GameDuration,
+		GameMode:            game.GameMode,
+		QueueType:           queueType,
+		GameType:            game.GameType,
+	}
+
+	for _, participant := range game.Participants {
+		s := participant.Stats
+		p := eog.EoGParticipant{
+			TeamID:                      participant.TeamID,
+			ChampionID:                  participant.ChampionID,
+			ChampionName:                championNames[participant.ChampionID],
+			Kills:                       s.Kills,
+			Deaths:                      s.Deaths,
+			Assists:                     s.Assists,
+			TotalMinionsKilled:          s.TotalMinionsKilled,
+			NeutralMinionsKilled:        s.NeutralMinionsKilled,
+			GoldEarned:                  s.GoldEarned,
+			TotalDamageDealtToChampions: s.TotalDamageDealtToChampions,
+			VisionScore:                 s.VisionScore,
+			Win:                         s.Win,
+			TotalDamageTaken:            s.TotalDamageTaken,
+			TimeCCingOthers:             s.TimeCCingOthers,
+			TotalDamageSelfMitigated:    s.DamageSelfMitigated,
+			Role:                        matchRole(participant.Timeline),
+		}
+		if player := game.Identity(participant.ParticipantID); player != nil {
+			if player.GameName != "" {
+				p.RiotIdGameName = player.GameName
+				p.RiotIdTagLine = player.TagLine
+			} else {
+				p.SummonerName = player.SummonerName
+			}
+		}
+		stats.Participants = append(stats.Participants, p)
+	}
+
+	applyMatchObjectives(stats, game)
+	return stats
+}
+
+// matchRole converts a participant's lane and role to the EoG role names
+func matchRole(timeline lcu.MatchParticipantTimeline) string {
+	switch timeline.Lane {
+	case "TOP", "JUNGLE":
+		return timeline.Lane
+	case "MIDDLE", "MID":
+		return "MIDDLE"
+	case "BOTTOM", "BOT":
+		if timeline.Role == "DUO_SUPPORT" {
+			return "SUPPORT"
+		}
+		return "BOTTOM"
+	}
+	return ""
+}
+
+// backfillHistory adds the games we played since the newest history entry, while the app
+// wasn't running, to the history. They are analyzed from match history and have no messages.
+func (a *App) backfillHistory(client *lcu.Client) {
+	if !a.backfillMu.TryLock() {
+		return // Already backfilling
+	}
+	defer a.backfillMu.Unlock()
+
+	newest, err := a.history.Query(history.Query{Limit: 1})
+	if err != nil {
+		log.Printf("Backfill: Failed to read history: %v", err)
+		return
+	}
+	if len(newest) == 0 {
+		return // Nothing to pick up from; older games weren't missed, they predate us
+	}
+	since := newest[0].Time
+
+	games, err := client.GetMatchHistory("", 0, backfillGames)
+	if err != nil {
+		log.Printf("Backfill: %v", err)
+		return
+	}
+
+	known := make(map[string]bool)
+	if len(games) > 0 {
+		oldest := time.UnixMilli(games[len(games)-1].GameCreation)
+		entries, err := a.history.Query(history.Query{Since: oldest})
+		if err != nil {
+			log.Printf("Backfill: Failed to read history: %v", err)
+			return
+		}
+		for _, entry := range entries {
+			known[entry.GameID] = true
+		}
+	}
+
+	a.mu.RLock()
+	lastGameID, phase := a.lastGameID, a.currentPhase
+	a.mu.RUnlock()
+
+	var missed []lcu.MatchHistoryGame
+	for i, game := range games {
+		gameID := fmt.Sprintf("%d", game.GameID)
+		ended := time.UnixMilli(game.EndTimestamp())
+		switch {
+		case known[gameID] || gameID == lastGameID:
+			// Already in the history
+		case !ended.After(since):
+			// Played before our newest game, so it was seen (or skipped) then
+		case time.Since(ended) < recentMatchWindow:
+			// Processed with messages by checkRecentMatch
+		case i == 0 && phase == "EndOfGame":
+			// Still on its post-game screen, where it's processed with messages
+		default:
+			missed = append(missed, game)
+		}
+	}
+	if len(missed) == 0 {
+		return
+	}
+	log.Printf("Backfill: %d game(s) played since %s are missing from the history", len(missed), since.Format(time.RFC3339))
+
+	championNames, err := client.GetChampionNames()
+	if err != nil {
+		log.Printf("Backfill: Failed to get champion names, champions will be named by ID: %v", err)
+	}
+
+	// Oldest first, so the history stays in the order the games were played
+	for i := len(missed) - 1; i >= 0; i-- {
+		if err := a.backfillGame(client, missed[i].GameID, championNames); err != nil {
+			log.Printf("Backfill: Skipping game %d: %v", missed[i].GameID, err)
+		}
+	}
+}
+
+// backfillGame analyzes a game from match history and adds it to the history
+func (a *App) backfillGame(client *lcu.Client, gameID int64, championNames map[int]string) error {
+	game, err := client.GetGame(gameID)
+	if err != nil {
+		return err
+	}
+
+	queueType, err := client.GetQueueType(game.QueueID)
+	if err != nil {
+		log.Printf("Backfill: Unknown queue %d, profile rules by queue won't apply: %v", game.QueueID, err)
+	}
+	stats := matchStats(game, championNames, queueType)
+
+	cfg := a.Config()
+	profile := cfg.SelectProfile(config.GameContext{QueueType: queueType, GameMode: game.GameMode})
+	gameSummary, err := analyzer.AnalyzeGame(stats, cfg.WithProfile(profile))
+	if err != nil {
+		return fmt.Errorf("failed to analyze game: %w", err)
+	}
+	if gameSummary == nil {
+		return fmt.Errorf("no game summary generated")
+	}
+	gameSummary.Profile = profile
+
+	if timeline, err := client.GetGameTimeline(gameID); err == nil {
+		analyzer.IntegrateMatchTimeline(gameSummary, timeline, game.ParticipantTeams())
+	}
+
+	// Dated when it was played, not now, so it sorts and filters with the games around it
+	ended := time.UnixMilli(game.EndTimestamp())
+	entry := history.Entry{GameID: fmt.Sprintf("%d", gameID), Time: ended, Summary: gameSummary, Backfilled: true}
+	if err := a.history.Add(entry); err != nil {
+		return err
+	}
+	log.Printf("Backfill: Added game %d (%s, %s) to the history", gameID, game.GameMode, ended.Format(time.RFC3339))
+	return nil
+}
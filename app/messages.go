@@ -0,0 +1,307 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/feedback"
+	"lol-kind-bot/history"
+	"lol-kind-bot/llm"
+	"sync"
+	"time"
+)
+
+// MessageSet is one game's suggested messages and the actions the messages dialog can take
+// on them. Every change is saved to the game's history entry.
+type MessageSet struct {
+	GameID  string
+	Summary *analyzer.GameSummary
+
+	app         *App
+	mu          sync.Mutex
+	suggestions []llm.Suggestion
+	pinned      map[string]bool
+
+	// Feedback tracking
+	originals map[string]string // Edited text -> the text it was suggested as
+	used      map[string]bool   // Copied, edited, sent or liked
+	copied    map[string]bool
+	sent      map[string]bool
+	dismissed map[string]bool
+	watching  bool // Checking the post-game chat for copied messages
+
+	pendingSaves []history.Update // History updates not written yet, oldest first
+	saving       bool             // writeHistory is running
+
+	// Progressive delivery: offline messages first, agentic ones swapped in as they finish
+	generation int  // Bumped by each full generation; late messages from an older one are dropped
+	generating bool // Agentic messages are still on their way
+	interacted bool // We acted on the messages, so auto-copy must not change the clipboard
+	autoCopied string
+	listeners  changeListeners
+	cancel     context.CancelFunc // Stops the running generation early
+	drafts     []llm.Suggestion   // Messages still being written
+	draftShown time.Time          // When listeners last heard about a draft
+
+	// Reply assistant for the post-game chat
+	chatReplies      []llm.ChatReply
+	chatConversation string
+	seenChat         map[string]bool // Chat message IDs already classified
+	watchingChat     bool
+}
+
+// newMessageSet creates the message set for a processed game
+func (a *App) newMessageSet(gameID string, gameSummary *analyzer.GameSummary, suggestions []llm.Suggestion) *MessageSet {
+	return &MessageSet{
+		GameID:      gameID,
+		Summary:     gameSummary,
+		app:         a,
+		suggestions: suggestions,
+		pinned:      make(map[string]bool),
+		originals:   make(map[string]string),
+		used:        make(map[string]bool),
+		copied:      make(map[string]bool),
+		sent:        make(map[string]bool),
+		dismissed:   make(map[string]bool),
+		seenChat:    make(map[string]bool),
+	}
+}
+
+// Suggestions returns a copy of the current suggestions
+func (m *MessageSet) Suggestions() []llm.Suggestion {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]llm.Suggestion(nil), m.suggestions...)
+}
+
+// Messages returns the current message texts
+func (m *MessageSet) Messages() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return llm.SuggestionTexts(m.suggestions)
+}
+
+// IsPinned reports whether a message is pinned as a favourite
+func (m *MessageSet) IsPinned(text string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pinned[text]
+}
+
+// Edit replaces the text of suggestion i with our edited version
+func (m *MessageSet) Edit(i int, text string) error {
+	m.mu.Lock()
+	if i < 0 || i >= len(m.suggestions) {
+		m.mu.Unlock()
+		return fmt.Errorf("no suggestion %d", i)
+	}
+	original := m.suggestions[i].Text
+	if text == original {
+		m.mu.Unlock()
+		return nil
+	}
+	m.interacted = true
+	m.suggestions[i].Text = text
+	if m.pinned[original] {
+		delete(m.pinned, original)
+		m.pinned[text] = true
+	}
+	root := original
+	if r, ok := m.originals[original]; ok {
+		root = r
+	}
+	if text == root {
+		delete(m.originals, text) // Edited back to the suggestion
+	} else {
+		m.originals[text] = root
+	}
+	m.used[text] = true
+	m.mu.Unlock()
+
+	m.addFeedback(m.feedbackRecord(feedback.SignalEdited, text))
+	m.save(history.Choice{Action: history.ChoiceEdited, Text: text, Original: original})
+	return nil
+}
+
+// Copied records that we copied a message to send it, and starts watching the
+// post-game chat to see whether we sent it
+func (m *MessageSet) Copied(text string) {
+	m.interact()
+	m.mu.Lock()
+	m.used[text] = true
+	m.copied[text] = true
+	m.mu.Unlock()
+
+	m.addFeedback(m.feedbackRecord(feedback.SignalCopied, text))
+	m.save(history.Choice{Action: history.ChoiceCopied, Text: text})
+	m.watchSent()
+}
+
+// SetPinned pins or unpins a message as a favourite
+func (m *MessageSet) SetPinned(text string, pinned bool) {
+	m.interact()
+	m.mu.Lock()
+	if m.pinned[text] == pinned {
+		m.mu.Unlock()
+		return
+	}
+	if pinned {
+		m.pinned[text] = true
+	} else {
+		delete(m.pinned, text)
+	}
+	m.mu.Unlock()
+
+	action := history.ChoiceUnpinned
+	if pinned {
+		action = history.ChoicePinned
+	}
+	m.save(history.Choice{Action: action, Text: text})
+}
+
+// Rewrite replaces suggestion i with a new message for the same player.
+// tone overrides the configured tone; empty keeps it.
+func (m *MessageSet) Rewrite(i int, tone string) (llm.Suggestion, error) {
+	m.interact()
+	m.mu.Lock()
+	if i < 0 || i >= len(m.suggestions) {
+		m.mu.Unlock()
+		return llm.Suggestion{}, fmt.Errorf("no suggestion %d", i)
+	}
+	old := m.suggestions[i]
+	shown := llm.SuggestionTexts(m.suggestions)
+	m.mu.Unlock()
+
+	agentic, enableDebug := m.app.agenticSystem(m.Summary, tone)
+	suggestion, err := agentic.Rewrite(old, shown, enableDebug)
+	if err != nil {
+		return llm.Suggestion{}, err
+	}
+
+	m.mu.Lock()
+	if i < len(m.suggestions) && m.suggestions[i].Text == old.Text {
+		m.suggestions[i] = suggestion
+	} else {
+		m.suggestions = append(m.suggestions, suggestion) // Edited meanwhile; keep both
+	}
+	m.mu.Unlock()
+
+	m.save(history.Choice{Action: history.ChoiceRegenerated, Text: suggestion.Text, Original: old.Text, Tone: tone})
+	return suggestion, nil
+}
+
+// RegenerateAll replaces every unpinned suggestion by running the full pipeline again.
+// tone overrides the configured tone; empty keeps it.
+func (m *MessageSet) RegenerateAll(tone string) ([]llm.Suggestion, error) {
+	m.interact()
+	generation := m.startGenerating(nil)
+	suggestions := m.app.generateSuggestions(m.Summary, tone)
+
+	m.mu.Lock()
+	if generation == m.generation {
+		m.generating = false
+	}
+	kept := make([]llm.Suggestion, 0, len(m.suggestions)+len(suggestions))
+	for _, s := range m.suggestions {
+		if m.pinned[s.Text] {
+			kept = append(kept, s)
+		}
+	}
+	m.suggestions = append(kept, suggestions...)
+	result := append([]llm.Suggestion(nil), m.suggestions...)
+	m.mu.Unlock()
+
+	m.save(history.Choice{Action: history.ChoiceRegenerated, Tone: tone})
+	m.app.publish(EventMessagesUpdated, llm.SuggestionTexts(result)) // EventMessages is once per game, for webhooks
+	m.changed()
+	return result, nil
+}
+
+// MoreLike adds another message in the spirit of suggestion i
+func (m *MessageSet) MoreLike(i int) (llm.Suggestion, error) {
+	m.interact()
+	m.mu.Lock()
+	if i < 0 || i >= len(m.suggestions) {
+		m.mu.Unlock()
+		return llm.Suggestion{}, fmt.Errorf("no suggestion %d", i)
+	}
+	liked := m.suggestions[i]
+	shown := llm.SuggestionTexts(m.suggestions)
+	m.mu.Unlock()
+
+	agentic, enableDebug := m.app.agenticSystem(m.Summary, "")
+	suggestion, err := agentic.MoreLike(liked, shown, enableDebug)
+	if err != nil {
+		return llm.Suggestion{}, err
+	}
+
+	m.mu.Lock()
+	m.suggestions = append(m.suggestions, suggestion)
+	m.mu.Unlock()
+
+	m.save(history.Choice{Action: history.ChoiceMoreLikeThis, Text: suggestion.Text, Original: liked.Text})
+	return suggestion, nil
+}
+
+// save records a choice and the current suggestions in the game's history entry
+func (m *MessageSet) save(choice history.Choice) {
+	choice.Time = time.Now()
+	m.updateHistory(&choice)
+	m.app.publish(EventMessageChoice, choice)
+}
+
+// saveSuggestions records the current suggestions in the game's history entry
+func (m *MessageSet) saveSuggestions() {
+	m.updateHistory(nil)
+}
+
+// updateHistory queues the current suggestions, and the choice if any, to be appended to the
+// game's history entry. The write happens off the caller's (often the UI's) goroutine.
+func (m *MessageSet) updateHistory(choice *history.Choice) {
+	if m.GameID == "" {
+		return
+	}
+
+	m.mu.Lock()
+	update := history.Update{
+		GameID:      m.GameID,
+		Suggestions: append([]llm.Suggestion(nil), m.suggestions...),
+		Pinned:      make([]string, 0, len(m.pinned)),
+		Choice:      choice,
+	}
+	for _, s := range m.suggestions {
+		if m.pinned[s.Text] {
+			update.Pinned = append(update.Pinned, s.Text)
+		}
+	}
+	m.pendingSaves = append(m.pendingSaves, update)
+	start := !m.saving
+	m.saving = true
+	m.mu.Unlock()
+
+	if start {
+		m.app.saves.Add(1)
+		go m.writeHistory()
+	}
+}
+
+// writeHistory appends the queued updates to the history in order, until none are left
+func (m *MessageSet) writeHistory() {
+	defer m.app.saves.Done()
+	for {
+		m.mu.Lock()
+		if len(m.pendingSaves) == 0 {
+			m.saving = false
+			m.mu.Unlock()
+			return
+		}
+		update := m.pendingSaves[0]
+		m.pendingSaves = m.pendingSaves[1:]
+		m.mu.Unlock()
+
+		if err := m.app.history.AddUpdate(update); err != nil {
+			log.Printf("Failed to save messages to history: %v", err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"lol-kind-bot/config"
+	"lol-kind-bot/lcu"
+	"lol-kind-bot/llm"
+)
+
+// GameProfile returns the profile chosen for the current or last game ("" = base config)
+func (a *App) GameProfile() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.gameProfile
+}
+
+// SetActiveProfile manually selects a profile for all games ("" = pick automatically by rules) and saves it
+func (a *App) SetActiveProfile(name string) error {
+	cfg := *a.Config()
+	if name != "" {
+		if _, ok := cfg.Profiles[name]; !ok {
+			return fmt.Errorf("unknown profile %q", name)
+		}
+	}
+	cfg.ActiveProfile = name
+	if err := a.UpdateConfig(&cfg); err != nil {
+		return err
+	}
+
+	if name == "" {
+		log.Println("Profile selection: automatic")
+	} else {
+		log.Printf("Profile selection: %s", name)
+	}
+	return nil
+}
+
+// configForProfile returns the current config with the named profile applied and the message language resolved
+func (a *App) configForProfile(profile string) *config.Config {
+	cfg := a.Config().WithProfile(profile)
+	a.mu.RLock()
+	cfg.LLMSettings.Language = llm.ResolveLanguage(cfg.LLMSettings.Language, a.clientLocale)
+	a.mu.RUnlock()
+	return cfg
+}
+
+// llmClientFor returns the LLM client to use with a profile's config
+func (a *App) llmClientFor(cfg *config.Config, profile string) *llm.Client {
+	if profile == "" {
+		return a.LLMClient()
+	}
+	return llm.NewClient(cfg.OllamaURL, cfg.OllamaModel, &cfg.LLMSettings)
+}
+
+// gameContext builds the profile-rule context for a game from its queue and game mode
+func (a *App) gameContext(queueType, gameMode string) config.GameContext {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return config.GameContext{
+		QueueType:    queueType,
+		GameMode:     gameMode,
+		PartyMembers: append([]string(nil), a.partyMembers...),
+	}
+}
+
+// selectProfile picks the profile for a game and remembers it as the current game's profile
+func (a *App) selectProfile(game config.GameContext) string {
+	profile := a.Config().SelectProfile(game)
+
+	a.mu.Lock()
+	changed := profile != a.gameProfile
+	a.gameProfile = profile
+	a.mu.Unlock()
+
+	if changed {
+		if profile == "" {
+			log.Printf("Using base config (queue: %s, mode: %s)", game.QueueType, game.GameMode)
+		} else {
+			log.Printf("Using profile %q (queue: %s, mode: %s)", profile, game.QueueType, game.GameMode)
+		}
+		a.publish(EventProfileChanged, profile)
+	}
+	return profile
+}
+
+// selectProfileForSession picks the profile for the game in the current gameflow session
+func (a *App) selectProfileForSession(client *lcu.Client) string {
+	session, err := client.GetGameflowSession()
+	if err != nil {
+		log.Printf("Could not read gameflow session for profile selection: %v", err)
+		return a.selectProfile(a.gameContext("", ""))
+	}
+
+	gameMode := session.GameData.Queue.GameMode
+	if gameMode == "" {
+		gameMode = session.Map.GameMode
+	}
+	return a.selectProfile(a.gameContext(session.GameData.Queue.Type, gameMode))
+}
+
+// refreshParty remembers who is in our lobby, for party-based profile rules
+func (a *App) refreshParty(client *lcu.Client) {
+	members, err := client.GetLobbyMembers()
+	if err != nil {
+		return // Not in a lobby (e.g. custom game or already in champ select)
+	}
+
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		if name := member.Name(); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	a.mu.Lock()
+	a.partyMembers = names
+	a.mu.Unlock()
+}
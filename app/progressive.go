@@ -0,0 +1,291 @@
+package app
+
+import (
+	"context"
+	"log"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/llm"
+	"sync"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+// instantSuggestions returns the offline messages shown while the LLM is still working
+func (a *App) instantSuggestions(gameSummary *analyzer.GameSummary) []llm.Suggestion {
+	cfg := a.configForProfile(gameSummary.Profile)
+	return llm.OfflineSuggestions(gameSummary, &cfg.LLMSettings)
+}
+
+// draftInterval limits how often listeners hear about drafts, which change with every token
+const draftInterval = 100 * time.Millisecond
+
+// upgradeMessages runs the agentic pipeline in the background and swaps each of its messages
+// into the set as soon as it is ready. StopGenerating cancels it.
+func (a *App) upgradeMessages(set *MessageSet) {
+	cfg := a.configForProfile(set.Summary.Profile)
+	ctx, cancel := context.WithCancel(context.Background())
+	generation := set.startGenerating(cancel)
+
+	go func() {
+		defer cancel()
+		started := time.Now()
+		logGenerationInputs(cfg, set.Summary)
+		agentic, enableDebug := a.agenticSystem(set.Summary, "")
+
+		for event := range agentic.StreamSuggestions(ctx, enableDebug) {
+			switch {
+			case event.Draft != nil:
+				set.draft(generation, *event.Draft)
+			case event.Suggestion != nil:
+				log.Printf("[AGENTIC] Message for %s ready after %v", event.Suggestion.Champion, time.Since(started).Round(time.Millisecond))
+				set.upgrade(generation, *event.Suggestion)
+			case event.Done:
+				if ctx.Err() != nil {
+					log.Printf("Message generation stopped early")
+				} else if event.Err != nil {
+					log.Printf("Agentic message generation failed: %v. Keeping offline messages.", event.Err)
+				}
+				log.Printf("Message generation finished after %v", time.Since(started).Round(time.Millisecond))
+				set.finishUpgrade(generation)
+			}
+		}
+	}()
+}
+
+// startGenerating marks the set as waiting for agentic messages and returns the generation
+// they belong to. RegenerateAll starts a new generation, so the old one is stopped and its
+// late messages are dropped. cancel (if set) stops the new generation.
+func (m *MessageSet) startGenerating(cancel context.CancelFunc) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.generation++
+	m.generating = true
+	m.cancel = cancel
+	m.drafts = nil
+	return m.generation
+}
+
+// StopGenerating stops the running generation, keeping the messages already finished
+func (m *MessageSet) StopGenerating() {
+	m.mu.Lock()
+	m.interacted = true // Happy with what's there, so leave the clipboard alone
+	cancel := m.cancel
+	m.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// Drafts returns the messages still being written, with the text generated so far
+func (m *MessageSet) Drafts() []llm.Suggestion {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]llm.Suggestion(nil), m.drafts...)
+}
+
+// draft updates the text of a message still being written
+func (m *MessageSet) draft(generation int, s llm.Suggestion) {
+	m.mu.Lock()
+	if generation != m.generation {
+		m.mu.Unlock()
+		return
+	}
+	if i := draftIndex(m.drafts, s.Champion); i >= 0 {
+		m.drafts[i] = s
+	} else {
+		m.drafts = append(m.drafts, s)
+	}
+	notify := time.Since(m.draftShown) >= draftInterval
+	if notify {
+		m.draftShown = time.Now()
+	}
+	m.mu.Unlock()
+
+	if notify {
+		m.changed()
+	}
+}
+
+// draftIndex returns the index of the draft about champion, or -1
+func draftIndex(drafts []llm.Suggestion, champion string) int {
+	for i, d := range drafts {
+		if d.Champion == champion {
+			return i
+		}
+	}
+	return -1
+}
+
+// Generating reports whether better messages are still being generated
+func (m *MessageSet) Generating() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.generating
+}
+
+// OnChange calls fn whenever messages are swapped in, drafts grow or generation finishes, and returns
+// a function to stop. fn runs on the generating goroutine.
+func (m *MessageSet) OnChange(fn func()) func() {
+	return m.listeners.add(fn)
+}
+
+// changed notifies the OnChange listeners
+func (m *MessageSet) changed() {
+	m.listeners.notify()
+}
+
+// changeListeners are the functions to call when a message or opener set changes. The zero value is ready to use.
+type changeListeners struct {
+	mu   sync.Mutex
+	next int
+	fns  map[int]func()
+}
+
+// add registers fn and returns a function that removes it
+func (l *changeListeners) add(fn func()) func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.fns == nil {
+		l.fns = make(map[int]func())
+	}
+	id := l.next
+	l.next++
+	l.fns[id] = fn
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.fns, id)
+	}
+}
+
+// notify calls every registered function, outside the lock so they may remove themselves
+func (l *changeListeners) notify() {
+	l.mu.Lock()
+	fns := make([]func(), 0, len(l.fns))
+	for _, fn := range l.fns {
+		fns = append(fns, fn)
+	}
+	l.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// interact records that we acted on the messages, so auto-copy leaves the clipboard alone
+func (m *MessageSet) interact() {
+	m.mu.Lock()
+	m.interacted = true
+	m.mu.Unlock()
+}
+
+// upgrade swaps an agentic message in for an offline one we haven't touched (preferring one
+// about the same champion), or adds it if there is none
+func (m *MessageSet) upgrade(generation int, s llm.Suggestion) {
+	m.mu.Lock()
+	if generation != m.generation {
+		m.mu.Unlock()
+		return
+	}
+	if i := draftIndex(m.drafts, s.Champion); i >= 0 {
+		m.drafts = append(m.drafts[:i], m.drafts[i+1:]...)
+	}
+	slot := m.replaceableSlot(s.Champion)
+	if slot >= 0 {
+		m.suggestions[slot] = s
+	} else {
+		m.suggestions = append(m.suggestions, s)
+	}
+	texts := llm.SuggestionTexts(m.suggestions)
+	m.mu.Unlock()
+
+	m.saveSuggestions()
+	m.app.publish(EventMessagesUpdated, texts)
+	m.autoCopyFirst()
+	m.changed()
+}
+
+// finishUpgrade ends a generation, dropping offline messages we haven't touched beyond
+// maxMessages now that agentic ones have taken their place
+func (m *MessageSet) finishUpgrade(generation int) {
+	cfg := m.app.configForProfile(m.Summary.Profile)
+
+	m.mu.Lock()
+	if generation != m.generation {
+		m.mu.Unlock()
+		return
+	}
+	m.generating = false
+	m.cancel = nil
+	m.drafts = nil
+	for i := len(m.suggestions) - 1; i >= 0 && len(m.suggestions) > cfg.LLMSettings.MaxMessages; i-- {
+		if s := m.suggestions[i]; s.IsFallback() && !m.touched(s.Text) {
+			m.suggestions = append(m.suggestions[:i], m.suggestions[i+1:]...)
+		}
+	}
+	texts := llm.SuggestionTexts(m.suggestions)
+	m.mu.Unlock()
+
+	m.saveSuggestions()
+	m.app.publish(EventMessages, texts)
+	m.autoCopyFirst()
+	m.changed()
+}
+
+// replaceableSlot returns the index of the offline message an agentic one about champion
+// should replace, or -1. Must be called with m.mu held.
+func (m *MessageSet) replaceableSlot(champion string) int {
+	slot := -1
+	for i, s := range m.suggestions {
+		if !s.IsFallback() || m.touched(s.Text) {
+			continue
+		}
+		if champion != "" && s.Champion == champion {
+			return i
+		}
+		if slot < 0 {
+			slot = i
+		}
+	}
+	return slot
+}
+
+// touched reports whether we used, edited or pinned a message. Must be called with m.mu held.
+func (m *MessageSet) touched(text string) bool {
+	_, edited := m.originals[text]
+	return m.used[text] || m.pinned[text] || edited
+}
+
+// setAutoCopied remembers the message auto-copy put on the clipboard
+func (m *MessageSet) setAutoCopied(text string) {
+	m.mu.Lock()
+	m.autoCopied = text
+	m.mu.Unlock()
+}
+
+// autoCopyFirst copies the new first message when it changed, as long as we haven't acted on
+// the messages and the clipboard still holds the one auto-copy put there
+func (m *MessageSet) autoCopyFirst() {
+	m.mu.Lock()
+	if m.interacted || m.autoCopied == "" || len(m.suggestions) == 0 || m.suggestions[0].Text == m.autoCopied {
+		m.mu.Unlock()
+		return
+	}
+	previous, first := m.autoCopied, m.suggestions[0].Text
+	m.mu.Unlock()
+
+	if current, err := clipboard.ReadAll(); err != nil || current != previous {
+		return // Something else was copied meanwhile
+	}
+	if err := CopyToClipboard(first); err != nil {
+		log.Printf("Failed to copy to clipboard: %v", err)
+		return
+	}
+	m.setAutoCopied(first)
+	log.Printf("Copied improved first message to clipboard: %s", first)
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"log"
+	"lol-kind-bot/config"
+	"lol-kind-bot/llm"
+	"os"
+	"time"
+)
+
+// configWatchInterval is how often config.json is checked for changes
+const configWatchInterval = 2 * time.Second
+
+// watchConfig reloads config.json whenever it changes on disk, until ctx is cancelled.
+// Invalid edits are rejected and the previous config stays in effect.
+func (a *App) watchConfig(ctx context.Context) {
+	if a.configPath == "" {
+		return
+	}
+
+	examplesPath := llm.ExamplesPathFor(a.configPath)
+	lastMod, lastSize := statConfig(a.configPath)
+	examplesMod, examplesSize := statConfig(examplesPath)
+	for sleepCtx(ctx, configWatchInterval) {
+		if modTime, size := statConfig(examplesPath); !modTime.Equal(examplesMod) || size != examplesSize {
+			examplesMod, examplesSize = modTime, size
+			a.loadExamples()
+		}
+
+		modTime, size := statConfig(a.configPath)
+		if modTime.IsZero() || (modTime.Equal(lastMod) && size == lastSize) {
+			continue // Missing (e.g. mid-save by an editor) or unchanged
+		}
+		lastMod, lastSize = modTime, size
+
+		cfg, err := config.LoadConfig(a.configPath)
+		if err != nil {
+			log.Printf("Rejected config change, keeping previous config: %v", err)
+			a.ui.ShowToast("LoL Kind Bot", "config.json has errors - keeping previous settings")
+			continue
+		}
+		a.applyConfig(cfg)
+	}
+}
+
+// loadExamples loads the user's example messages (examples.json next to the config) on top of
+// the built-in ones. On error the built-in examples are used.
+func (a *App) loadExamples() {
+	if a.configPath == "" {
+		return
+	}
+	library, err := llm.LoadExampleLibrary(llm.ExamplesPathFor(a.configPath))
+	if err != nil {
+		log.Printf("Failed to load example messages, using built-in examples: %v", err)
+		a.ui.ShowToast("LoL Kind Bot", "examples.json has errors - using built-in examples")
+	}
+	llm.SetExampleLibrary(library)
+}
+
+// statConfig returns the config file's modification time and size (zero if it can't be read)
+func statConfig(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
+
+// applyConfig makes cfg the current config and pushes every change to the running components
+func (a *App) applyConfig(cfg *config.Config) {
+	if a.forceDebugLogging {
+		cfg.EnableDebugLogging = true
+	}
+
+	a.mu.Lock()
+	changes := config.Diff(a.cfg, cfg)
+	if len(changes) == 0 {
+		a.mu.Unlock()
+		return
+	}
+	a.cfg = cfg
+	llmChanged := config.Changed(changes, "ollamaModel", "ollamaUrl", "llmSettings")
+	if llmChanged {
+		a.llmClient = llm.NewClient(cfg.OllamaURL, cfg.OllamaModel, &cfg.LLMSettings)
+	}
+	gameMonitor, goldMonitor, objectiveMonitor := a.gameMonitor, a.goldMonitor, a.objectiveMonitor
+	gameProfile := a.gameProfile
+	inGame := a.currentPhase == "InProgress" || a.currentPhase == "GameStart"
+	a.mu.Unlock()
+
+	for _, change := range changes {
+		log.Printf("Config changed: %s", change)
+	}
+	if llmChanged {
+		log.Printf("LLM client rebuilt (model: %s, URL: %s)", cfg.OllamaModel, cfg.OllamaURL)
+	}
+
+	if gameMonitor != nil {
+		gameMonitor.SetPollInterval(time.Duration(cfg.PollIntervalSeconds) * time.Second)
+		gameMonitor.SetCooldown(time.Duration(cfg.EndOfGameCooldownSec) * time.Second)
+	}
+	if goldMonitor != nil {
+		gold := cfg.WithProfile(gameProfile).GoldAnnouncements
+		goldMonitor.UpdateSettings(&gold)
+		// Turning announcements on mid-game starts the monitor straight away
+		if inGame && gold.Enabled && !goldMonitor.IsRunning() {
+			goldMonitor.Start()
+		}
+	}
+
+	if objectiveMonitor != nil {
+		objectiveMonitor.UpdateSettings(&cfg.ObjectiveTimers)
+		if inGame && cfg.ObjectiveTimers.Enabled && !objectiveMonitor.IsRunning() {
+			objectiveMonitor.Start()
+		}
+	}
+
+	if config.Changed(changes, "tts.engine", "tts.voice", "tts.rate", "tts.volume", "tts.piperModel") {
+		a.speech.SetEngine(newSpeechEngine(&cfg.TTS))
+	}
+
+	if config.Changed(changes, "api") {
+		log.Printf("Control API changes take effect after a restart")
+	}
+
+	a.publish(EventConfigChanged, cfg)
+}
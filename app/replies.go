@@ -0,0 +1,179 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/lcu"
+	"lol-kind-bot/llm"
+	"strings"
+	"time"
+)
+
+const (
+	replyCheckInterval = 3 * time.Second  // How often the post-game chat is checked for new lines
+	replyCheckTimeout  = 15 * time.Minute // Longest we keep watching one post-game lobby
+)
+
+// watchChat suggests replies to the lines others write in the post-game chat while we stay in EndOfGame
+func (m *MessageSet) watchChat() {
+	cfg := m.app.configForProfile(m.Summary.Profile)
+	if !cfg.ReplyAssistant {
+		return
+	}
+
+	m.mu.Lock()
+	if m.watchingChat {
+		m.mu.Unlock()
+		return
+	}
+	m.watchingChat = true
+	m.mu.Unlock()
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			m.watchingChat = false
+			m.mu.Unlock()
+		}()
+
+		deadline := time.Now().Add(replyCheckTimeout)
+		for time.Now().Before(deadline) {
+			time.Sleep(replyCheckInterval)
+			client := m.app.LCUClient()
+			if client == nil || m.app.CurrentPhase() != "EndOfGame" {
+				return
+			}
+			m.checkChat(client)
+		}
+	}()
+}
+
+// checkChat suggests replies to the post-game chat lines we haven't seen yet
+func (m *MessageSet) checkChat(client *lcu.Client) {
+	me, err := client.GetChatMe()
+	if err != nil {
+		log.Printf("[REPLIES] Failed to check post-game chat: %v", err)
+		return
+	}
+	conversation, err := client.GetPostGameConversation()
+	if err != nil || conversation == nil {
+		if err != nil {
+			log.Printf("[REPLIES] Failed to check post-game chat: %v", err)
+		}
+		return
+	}
+	messages, err := client.GetConversationMessages(conversation.ID)
+	if err != nil {
+		log.Printf("[REPLIES] Failed to check post-game chat: %v", err)
+		return
+	}
+
+	m.mu.Lock()
+	m.chatConversation = conversation.ID
+	var incoming []lcu.ChatMessage
+	for _, message := range messages {
+		if m.seenChat[message.ID] || message.Type == "system" || message.IsFrom(me) || strings.TrimSpace(message.Body) == "" {
+			continue
+		}
+		m.seenChat[message.ID] = true
+		incoming = append(incoming, message)
+	}
+	m.mu.Unlock()
+	if len(incoming) == 0 {
+		return
+	}
+
+	participants, err := client.GetConversationParticipants(conversation.ID)
+	if err != nil {
+		log.Printf("[REPLIES] Failed to get chat participants, replying without sender context: %v", err)
+	}
+
+	cfg := m.app.configForProfile(m.Summary.Profile)
+	added := false
+	for _, message := range incoming {
+		sender, from := m.chatSender(participants, message)
+		intent, replies := llm.SuggestReplies(message.Body, sender, m.Summary, cfg.LLMSettings.Language, cfg.LLMSettings.MaxMessageLength)
+		if len(replies) == 0 {
+			continue
+		}
+
+		reply := llm.ChatReply{MessageID: message.ID, From: from, Text: message.Body, Intent: intent, Replies: replies}
+		m.mu.Lock()
+		m.chatReplies = append(m.chatReplies, reply)
+		m.mu.Unlock()
+
+		log.Printf("[REPLIES] %s (%s): %s", from, intent, message.Body)
+		m.app.publish(EventChatReply, reply)
+		added = true
+		m.app.ui.ShowReply(m, reply)
+	}
+	if added {
+		m.changed()
+	}
+}
+
+// chatSender finds who wrote a chat line: their player summary (nil if they aren't in it) and
+// the name to show, preferring their champion
+func (m *MessageSet) chatSender(participants []lcu.ChatParticipant, message lcu.ChatMessage) (*analyzer.PlayerSummary, string) {
+	for _, participant := range participants {
+		if !participant.Sent(message) {
+			continue
+		}
+		riotID := participant.GameName
+		if participant.GameTag != "" {
+			riotID += "#" + participant.GameTag
+		}
+		for i := range m.Summary.Players {
+			name := m.Summary.Players[i].SummonerName
+			if name == riotID || (participant.GameName != "" && name == participant.GameName) || (participant.Name != "" && name == participant.Name) {
+				return &m.Summary.Players[i], m.Summary.Players[i].Champion
+			}
+		}
+		if participant.GameName != "" {
+			return nil, participant.GameName
+		}
+		return nil, participant.Name
+	}
+	return nil, "Someone"
+}
+
+// ChatReplies returns the post-game chat lines with suggested replies, oldest first
+func (m *MessageSet) ChatReplies() []llm.ChatReply {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	replies := make([]llm.ChatReply, len(m.chatReplies))
+	for i, reply := range m.chatReplies {
+		reply.Replies = append([]string(nil), reply.Replies...)
+		replies[i] = reply
+	}
+	return replies
+}
+
+// SendReply sends text to the post-game chat as our answer to chat line i
+func (m *MessageSet) SendReply(i int, text string) error {
+	client := m.app.LCUClient()
+	if client == nil {
+		return fmt.Errorf("not connected to the League client")
+	}
+
+	m.mu.Lock()
+	if i < 0 || i >= len(m.chatReplies) {
+		m.mu.Unlock()
+		return fmt.Errorf("no chat line %d", i)
+	}
+	conversationID := m.chatConversation
+	m.mu.Unlock()
+
+	if err := client.SendMessage(conversationID, text); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.chatReplies[i].Answered = text
+	m.mu.Unlock()
+
+	log.Printf("[REPLIES] Sent reply: %s", text)
+	m.changed()
+	return nil
+}
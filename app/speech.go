@@ -0,0 +1,51 @@
+package app
+
+import (
+	"log"
+	"lol-kind-bot/config"
+	"lol-kind-bot/tts"
+)
+
+// newSpeechEngine creates the configured text-to-speech engine, falling back to silence if it isn't available
+func newSpeechEngine(cfg *config.TTSSettings) tts.Engine {
+	engine, err := tts.New(cfg)
+	if err != nil {
+		log.Printf("Text-to-speech unavailable, announcements will only be logged: %v", err)
+		return tts.Null{}
+	}
+	log.Printf("Text-to-speech engine: %s", engine.Name())
+	return engine
+}
+
+// say queues an in-game announcement
+func (a *App) say(u tts.Utterance) {
+	a.speech.Say(u)
+}
+
+// Speech returns the queue in-game announcements are spoken through
+func (a *App) Speech() *tts.Queue {
+	return a.speech
+}
+
+// Muted reports whether in-game announcements are muted
+func (a *App) Muted() bool {
+	return a.speech.Muted()
+}
+
+// SetMuted mutes or unmutes in-game announcements
+func (a *App) SetMuted(muted bool) {
+	a.speech.SetMuted(muted)
+	if muted {
+		log.Println("Announcements muted")
+	} else {
+		log.Println("Announcements unmuted")
+	}
+	a.publish(EventMuted, muted)
+}
+
+// ToggleMute flips muting (the mute hotkey) and returns whether announcements are now muted
+func (a *App) ToggleMute() bool {
+	muted := !a.Muted()
+	a.SetMuted(muted)
+	return muted
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/api"
+	"lol-kind-bot/app"
+	"lol-kind-bot/config"
+	"lol-kind-bot/lcu"
+	"lol-kind-bot/llm"
+	"lol-kind-bot/webhook"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// runServe runs the bot without any GUI until interrupted
+func runServe(bot *app.App) error {
+	logConfigSummary(bot)
+	if bot.Config().MySummonerName == "" {
+		log.Println("Warning: mySummonerName is not set in config - team detection will not work")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	stopAPI := startAPI(bot)
+	defer stopAPI()
+	stopWebhooks := startWebhooks(bot)
+	defer stopWebhooks()
+
+	bot.Start(ctx)
+	<-ctx.Done()
+	log.Println("Shutting down...")
+	bot.Stop()
+	return nil
+}
+
+// startAPI starts the control API if it is enabled, generating and saving a token on first use.
+// The returned function shuts it down.
+func startAPI(bot *app.App) func() {
+	cfg := bot.Config()
+	if !cfg.API.Enabled {
+		return func() {}
+	}
+
+	if cfg.API.Token == "" {
+		token, err := api.GenerateToken()
+		if err != nil {
+			log.Printf("Control API disabled: %v", err)
+			return func() {}
+		}
+		newCfg := *cfg
+		newCfg.API.Token = token
+		if err := bot.UpdateConfig(&newCfg); err != nil {
+			log.Printf("Control API disabled: %v", err)
+			return func() {}
+		}
+		log.Printf("Generated control API token (saved to %s)", bot.ConfigPath())
+		cfg = &newCfg
+	}
+
+	server := api.NewServer(bot, cfg.API)
+	if err := server.Start(); err != nil {
+		log.Printf("Control API disabled: %v", err)
+		return func() {}
+	}
+	return func() { server.Close() }
+}
+
+// startWebhooks forwards bot events to the configured webhooks (picking up config changes).
+// The returned function unsubscribes and waits for in-flight deliveries.
+func startWebhooks(bot *app.App) func() {
+	hooks := bot.Config().Webhooks
+	dispatcher := webhook.NewDispatcher(hooks)
+	events, unsubscribe := bot.Subscribe()
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(events)
+		close(done)
+	}()
+	if len(hooks) > 0 {
+		log.Printf("Sending events to %d webhook(s)", len(hooks))
+	}
+
+	return func() {
+		unsubscribe()
+		<-done
+		dispatcher.Wait()
+	}
+}
+
+// runWebhookTest sends sample events to every configured webhook, using a saved EoG stats response if given
+func runWebhookTest(bot *app.App, args []string) error {
+	hooks := bot.Config().Webhooks
+	if len(hooks) == 0 {
+		return fmt.Errorf("no webhooks configured (add them under \"webhooks\" in %s)", bot.ConfigPath())
+	}
+
+	var gameSummary *analyzer.GameSummary
+	if len(args) > 0 {
+		data, err := readEoGFile("webhook-test", args)
+		if err != nil {
+			return err
+		}
+		if gameSummary, err = bot.SummarizeEoG(data); err != nil {
+			return err
+		}
+	}
+
+	cfg := bot.Config()
+	messages := llm.FallbackMessages(cfg.LLMSettings.Language)
+	if gameSummary != nil {
+		messages = llm.SuggestionTexts(llm.OfflineSuggestions(gameSummary, &cfg.LLMSettings))
+	}
+	now := time.Now()
+
+	dispatcher := webhook.NewDispatcher(hooks)
+	dispatcher.Send(webhook.Payload{Event: webhook.EventGameEnd, Time: now, Summary: gameSummary})
+	dispatcher.Send(webhook.Payload{Event: webhook.EventMessages, Time: now, Summary: gameSummary, Messages: messages, Message: messages[0]})
+	dispatcher.Send(webhook.Payload{Event: webhook.EventGoldMilestone, Time: now, Gold: 1500})
+	dispatcher.Wait()
+	return nil
+}
+
+// runFeedback exports or resets the recorded message feedback
+func runFeedback(bot *app.App, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: feedback export [file] | feedback reset")
+	}
+
+	switch args[0] {
+	case "export":
+		export, err := bot.ExportFeedback()
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal feedback: %w", err)
+		}
+		if len(args) < 2 {
+			fmt.Println(string(data))
+			return nil
+		}
+		if err := os.WriteFile(args[1], data, 0644); err != nil {
+			return fmt.Errorf("failed to write feedback export: %w", err)
+		}
+		fmt.Printf("Exported %d feedback records to %s\n", len(export.Records), args[1])
+		return nil
+	case "reset":
+		if err := bot.ResetFeedback(); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted recorded feedback (%s)\n", bot.Feedback().Path())
+		return nil
+	default:
+		return fmt.Errorf("unknown feedback command %q (want export or reset)", args[0])
+	}
+}
+
+// runAnalyze prints the GameSummary for a saved EoG stats response
+func runAnalyze(bot *app.App, args []string) error {
+	data, err := readEoGFile("analyze", args)
+	if err != nil {
+		return err
+	}
+
+	gameSummary, err := bot.SummarizeEoG(data)
+	if err != nil {
+		return err
+	}
+
+	summaryJSON, err := json.MarshalIndent(gameSummary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal game summary: %w", err)
+	}
+	fmt.Println(string(summaryJSON))
+	return nil
+}
+
+// runGenerate runs the LLM pipeline on a saved EoG stats response and prints the messages
+func runGenerate(bot *app.App, args []string) error {
+	data, err := readEoGFile("generate", args)
+	if err != nil {
+		return err
+	}
+
+	gameSummary, err := bot.SummarizeEoG(data)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range bot.GenerateMessages(gameSummary) {
+		fmt.Println(msg)
+	}
+	return nil
+}
+
+// runReplay runs the bot headless against a recorded LCU session and exits when playback ends
+func runReplay(cfg *config.Config, opts app.Options, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	speed := fs.Float64("speed", 1, "Playback speed (e.g. 10 = ten times faster)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s replay [-speed n] <session.jsonl>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("replay requires exactly one session file")
+	}
+
+	session, err := lcu.LoadSession(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	replayServer := lcu.NewReplayServer(session, *speed)
+	opts.Lockfile, err = replayServer.Start()
+	if err != nil {
+		return err
+	}
+	defer replayServer.Close()
+
+	bot := app.New(cfg, opts)
+	logConfigSummary(bot)
+	bot.Start(context.Background())
+
+	<-replayServer.Done()
+	log.Println("Replay finished, waiting for in-flight processing...")
+
+	// Give the monitors one more poll before shutting down (Stop waits for EoG processing)
+	time.Sleep(time.Duration(cfg.PollIntervalSeconds) * time.Second)
+	bot.Stop()
+	return nil
+}
+
+// runDoctor checks the config, lockfile, LCU auth, LLM reachability and model presence
+func runDoctor(cfg *config.Config, cfgPath string, validationErr *config.ValidationError) error {
+	failures := 0
+	report := func(ok bool, format string, args ...interface{}) {
+		status := "[ OK ]"
+		if !ok {
+			status = "[FAIL]"
+			failures++
+		}
+		fmt.Printf("%s %s\n", status, fmt.Sprintf(format, args...))
+	}
+	warn := func(format string, args ...interface{}) {
+		fmt.Printf("[WARN] %s\n", fmt.Sprintf(format, args...))
+	}
+
+	if validationErr != nil {
+		report(false, "Config at %s has %d problem(s):", cfgPath, len(validationErr.Errors))
+		for _, fieldErr := range validationErr.Errors {
+			fmt.Printf("       - %s\n", fieldErr.Error())
+		}
+	} else {
+		report(true, "Config loaded from %s", cfgPath)
+	}
+	if cfg.MySummonerName == "" {
+		warn("mySummonerName is not set - team detection will not work")
+	}
+	examplesPath := llm.ExamplesPathFor(cfgPath)
+	if library, err := llm.LoadExampleLibrary(examplesPath); err != nil {
+		report(false, "Example messages: %v", err)
+	} else {
+		report(true, "Example messages: %d (custom examples go in %s)", len(library.Examples), examplesPath)
+	}
+
+	// League client
+	lockfilePath := lcu.GetLockfilePath()
+	lockfileInfo, err := lcu.ParseLockfile(lockfilePath)
+	if err != nil {
+		report(false, "Lockfile at %s: %v (is the League client running? set LOL_LOCKFILE_PATH to override)", lockfilePath, err)
+	} else {
+		report(true, "Lockfile at %s (port %s, protocol %s)", lockfilePath, lockfileInfo.Port, lockfileInfo.Protocol)
+
+		client, err := lcu.NewClient(lockfileInfo)
+		if err != nil {
+			report(false, "LCU client: %v", err)
+		} else if summoner, err := client.GetCurrentSummoner(); err != nil {
+			report(false, "LCU auth (%s): %v", client.BaseURL, err)
+		} else {
+			report(true, "LCU auth (%s): logged in as %s", client.BaseURL, summoner.DisplayName)
+		}
+	}
+
+	// LLM server
+	client := llm.NewClient(cfg.OllamaURL, cfg.OllamaModel, &cfg.LLMSettings)
+	models, err := client.ListModels()
+	if err != nil {
+		report(false, "LLM server at %s: %v (is `ollama serve` running?)", cfg.OllamaURL, err)
+	} else {
+		report(true, "LLM server at %s (%d models installed)", cfg.OllamaURL, len(models))
+		if llm.HasModel(models, cfg.OllamaModel) {
+			report(true, "Model %q is installed", cfg.OllamaModel)
+		} else {
+			report(false, "Model %q is not installed (run `ollama pull %s`; available: %s)",
+				cfg.OllamaModel, cfg.OllamaModel, strings.Join(models, ", "))
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d check(s) failed", failures)
+	}
+	return nil
+}
+
+// readEoGFile reads the EoG stats file argument for analyze/generate
+func readEoGFile(command string, args []string) ([]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("usage: %s %s <eog.json>", os.Args[0], command)
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read EoG stats file: %w", err)
+	}
+	return data, nil
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 )
@@ -17,6 +19,7 @@ const (
 	DefaultMaxCsPerMin      = 0.5
 	DefaultMaxDamageToChamp = 1500
 	DefaultMaxGoldEarned    = 4000
+	DefaultAPIPort          = 8765
 )
 
 type AFKThresholds struct {
@@ -40,6 +43,7 @@ type LLMSettings struct {
 	
 	// Language Style
 	LanguageStyle string `json:"languageStyle"` // "casual", "formal", "enthusiastic", "gamer"
+	Language      string `json:"language"`      // "en", "es", "pt-BR", "ko", "de", "fr", or "auto" for the client's locale
 	
 	// Focus Areas - what to highlight in messages
 	FocusAreas []string `json:"focusAreas"` // ["all", "positive", "kda", "teamplay", "vision"]
@@ -57,32 +61,124 @@ type LLMSettings struct {
 
 type GoldAnnouncementSettings struct {
 	Enabled           bool     `json:"enabled"`           // Enable/disable gold announcements
+	Mode              string   `json:"mode"`              // "build" (next item in our build, thresholds if there is none) or "thresholds"
 	Thresholds        []int    `json:"thresholds"`        // Gold thresholds to announce (e.g., [1500, 2000, 3000])
 	PollIntervalSec   int      `json:"pollIntervalSec"`   // How often to check gold (seconds)
+	AlmostGold        int      `json:"almostGold"`        // Announce "N gold to <item>" when this close to the next purchase (0 = off)
+	Builds            map[string][]string `json:"builds"` // Champion name -> planned items (names or IDs), used before the client's item sets
+}
+
+// ObjectiveTimer controls the announcement for one kind of objective timer
+type ObjectiveTimer struct {
+	Enabled     bool `json:"enabled"`
+	LeadSeconds int  `json:"leadSeconds"` // How long before the spawn, respawn or buff expiry to announce it
+}
+
+// ObjectiveTimerSettings controls in-game announcements of objective spawns, inhibitor respawns and buff expiry
+type ObjectiveTimerSettings struct {
+	Enabled         bool           `json:"enabled"`         // Enable/disable all objective announcements
+	PollIntervalSec int            `json:"pollIntervalSec"` // How often to read live game events (seconds)
+	Dragon          ObjectiveTimer `json:"dragon"`          // Dragon and Elder Dragon spawns
+	Herald          ObjectiveTimer `json:"herald"`          // Rift Herald spawn
+	Baron           ObjectiveTimer `json:"baron"`           // Baron Nashor spawns
+	Inhibitor       ObjectiveTimer `json:"inhibitor"`       // Inhibitor respawns, ours and the enemy's
+	BaronBuff       ObjectiveTimer `json:"baronBuff"`       // Hand of Baron expiry
+	ElderBuff       ObjectiveTimer `json:"elderBuff"`       // Aspect of the Elder Dragon expiry
+}
+
+// ObjectiveKinds lists the objective timers by JSON name, in display order
+var ObjectiveKinds = []string{"dragon", "herald", "baron", "inhibitor", "baronBuff", "elderBuff"}
+
+// Timer returns the settings for an objective kind from ObjectiveKinds (nil if unknown)
+func (s *ObjectiveTimerSettings) Timer(kind string) *ObjectiveTimer {
+	switch kind {
+	case "dragon":
+		return &s.Dragon
+	case "herald":
+		return &s.Herald
+	case "baron":
+		return &s.Baron
+	case "inhibitor":
+		return &s.Inhibitor
+	case "baronBuff":
+		return &s.BaronBuff
+	case "elderBuff":
+		return &s.ElderBuff
+	}
+	return nil
+}
+
+// TTSSettings controls how announcements are spoken
+type TTSSettings struct {
+	Engine     string `json:"engine"`     // "auto", "sapi" (Windows), "espeak" (espeak-ng), "piper" or "none"
+	Voice      string `json:"voice"`      // Voice name as the engine lists it ("" = the engine's default)
+	Rate       int    `json:"rate"`       // Speaking rate from -10 (slow) to 10 (fast), 0 = normal
+	Volume     int    `json:"volume"`     // Volume from 0 to 100
+	PiperModel string `json:"piperModel"` // Path to the piper voice model (.onnx), required for the piper engine
+	MuteHotkey string `json:"muteHotkey"` // Global hotkey that mutes and unmutes announcements, e.g. "ctrl+shift+m" ("" = none)
+}
+
+// APISettings controls the local HTTP control API (localhost only, off by default)
+type APISettings struct {
+	Enabled bool   `json:"enabled"` // Enable the control API
+	Port    int    `json:"port"`    // Port on 127.0.0.1 to listen on
+	Token   string `json:"token"`   // Bearer token required on every request (generated on first start if empty)
+}
+
+// WebhookSettings configures one outbound webhook
+type WebhookSettings struct {
+	Name       string   `json:"name"`       // Label used in logs
+	URL        string   `json:"url"`        // Endpoint to POST to
+	Events     []string `json:"events"`     // "gameEnd", "messages", "goldMilestone" (empty = all)
+	Format     string   `json:"format"`     // "json" (default) or "discord"
+	Template   string   `json:"template"`   // Optional Go text/template producing the JSON body (overrides format)
+	MaxRetries int      `json:"maxRetries"` // Retries after the first attempt on network errors, 429 and 5xx (0 = default of 3)
+}
+
+// FeedbackSettings controls learning from how we use the suggested messages
+type FeedbackSettings struct {
+	Enabled     bool `json:"enabled"`     // Record feedback and use it to re-rank candidates and pick examples
+	AdaptTone   bool `json:"adaptTone"`   // Switch to the tone our feedback clearly favours
+	AdaptLength bool `json:"adaptLength"` // Fit the maximum message length to the messages we use
+	MinSamples  int  `json:"minSamples"`  // Feedback records needed before tone and length are adapted
 }
 
 type Config struct {
+	ConfigVersion         int                     `json:"configVersion"` // Schema version, upgraded automatically on load
 	MySummonerName        string                  `json:"mySummonerName"`
 	OllamaModel           string                  `json:"ollamaModel"`
 	OllamaURL             string                  `json:"ollamaUrl"`
 	PollIntervalSeconds   int                     `json:"pollIntervalSeconds"`
 	EndOfGameCooldownSec  int                     `json:"endOfGameCooldownSeconds"`
 	AutoCopyToClipboard   bool                    `json:"autoCopyToClipboard"`
+	ReplyAssistant        bool                    `json:"replyAssistant"` // Suggest replies to incoming post-game chat
+	ChampSelectOpeners    bool                    `json:"champSelectOpeners"` // Suggest good luck openers during champion select
 	EnableDetailedLogging bool                    `json:"enableDetailedLogging"`
 	EnableDebugLogging    bool                    `json:"enableDebugLogging"` // Detailed debug output for LLM and validation (can be overridden by -debug flag)
 	AFKThresholds        AFKThresholds            `json:"afkThresholds"`
 	LLMSettings           LLMSettings             `json:"llmSettings"`
 	GoldAnnouncements     GoldAnnouncementSettings `json:"goldAnnouncements"`
+	ObjectiveTimers       ObjectiveTimerSettings   `json:"objectiveTimers"`
+	TTS                   TTSSettings             `json:"tts"`
+	API                   APISettings             `json:"api"`
+	Webhooks              []WebhookSettings       `json:"webhooks"`
+	Feedback              FeedbackSettings        `json:"feedback"`
+	Profiles              map[string]Profile      `json:"profiles"`      // Named overrides, e.g. "ranked", "aram"
+	ProfileRules          []ProfileRule           `json:"profileRules"`  // Pick a profile from the queue, mode or party
+	ActiveProfile         string                  `json:"activeProfile"` // Manually chosen profile ("" = pick by rules)
 }
 
 func DefaultConfig() *Config {
 	return &Config{
+		ConfigVersion:         CurrentConfigVersion,
 		MySummonerName:        "",
 		OllamaModel:           DefaultOllamaModel,
 		OllamaURL:             DefaultOllamaURL,
 		PollIntervalSeconds:   DefaultPollInterval,
 		EndOfGameCooldownSec:  DefaultEoGCooldown,
 		AutoCopyToClipboard:   true,
+		ReplyAssistant:        true,
+		ChampSelectOpeners:    true,
 		EnableDetailedLogging: false,
 		EnableDebugLogging:    false,
 		AFKThresholds: AFKThresholds{
@@ -97,6 +193,7 @@ func DefaultConfig() *Config {
 			MaxMessages:       3,
 			MaxMessageLength:  150,
 			LanguageStyle:     "casual",
+			Language:          "en",
 			FocusAreas:        []string{"all"},
 			AFKHandling:       "default",
 			Temperature:       0.7,
@@ -105,12 +202,41 @@ func DefaultConfig() *Config {
 		},
 		GoldAnnouncements: GoldAnnouncementSettings{
 			Enabled:         true, // Default enabled as requested
+			Mode:            "build",
+			AlmostGold:      300,
 			Thresholds:      []int{1500, 2000, 3000, 4000, 5000}, // Common item breakpoints
 			PollIntervalSec: 2, // Check every 2 seconds during active game
 		},
+		ObjectiveTimers: ObjectiveTimerSettings{
+			Enabled:         true,
+			PollIntervalSec: 2,
+			Dragon:          ObjectiveTimer{Enabled: true, LeadSeconds: 30},
+			Herald:          ObjectiveTimer{Enabled: true, LeadSeconds: 30},
+			Baron:           ObjectiveTimer{Enabled: true, LeadSeconds: 30},
+			Inhibitor:       ObjectiveTimer{Enabled: true, LeadSeconds: 30},
+			BaronBuff:       ObjectiveTimer{Enabled: true, LeadSeconds: 30},
+			ElderBuff:       ObjectiveTimer{Enabled: true, LeadSeconds: 30},
+		},
+		TTS: TTSSettings{
+			Engine:     "auto",
+			Volume:     100,
+			MuteHotkey: "ctrl+shift+m",
+		},
+		API: APISettings{
+			Enabled: false,
+			Port:    DefaultAPIPort,
+		},
+		Feedback: FeedbackSettings{
+			Enabled:     true,
+			AdaptTone:   true,
+			AdaptLength: true,
+			MinSamples:  20,
+		},
 	}
 }
 
+// LoadConfig reads the config file, creating it with defaults if missing. If the file has
+// invalid or unknown fields, the config is returned along with a *ValidationError listing them.
 func LoadConfig(configPath string) (*Config, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -124,70 +250,59 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	// Upgrade older files step by step, keeping a backup of the original
+	original := data
+	data, fromVersion, err := migrate(data)
+	if err != nil {
+		return nil, err
 	}
+	migrated := fromVersion != CurrentConfigVersion
 
-	// Apply defaults for missing fields
-	if cfg.OllamaModel == "" {
-		cfg.OllamaModel = DefaultOllamaModel
-	}
-	if cfg.OllamaURL == "" {
-		cfg.OllamaURL = DefaultOllamaURL
-	}
-	if cfg.PollIntervalSeconds == 0 {
-		cfg.PollIntervalSeconds = DefaultPollInterval
-	}
-	if cfg.EndOfGameCooldownSec == 0 {
-		cfg.EndOfGameCooldownSec = DefaultEoGCooldown
-	}
-	if cfg.AFKThresholds.MinGameMinutes == 0 {
-		cfg.AFKThresholds = AFKThresholds{
-			MinGameMinutes:   DefaultMinGameMinutes,
-			MaxCsPerMin:      DefaultMaxCsPerMin,
-			MaxDamageToChamp: DefaultMaxDamageToChamp,
-			MaxGoldEarned:    DefaultMaxGoldEarned,
+	// Decode over the defaults: keys missing from the file keep their default value,
+	// while keys that are present (even as 0, false or "") are taken as written
+	cfg := DefaultConfig()
+	var fieldErrors []FieldError
+	if err := json.Unmarshal(data, cfg); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
 		}
+		fieldErrors = append(fieldErrors, typeError(typeErr))
 	}
-	// Apply defaults for LLM settings
-	defaultLLM := DefaultConfig().LLMSettings
-	if cfg.LLMSettings.Tone == "" {
-		cfg.LLMSettings.Tone = defaultLLM.Tone
-	}
-	if cfg.LLMSettings.MinMessages == 0 {
-		cfg.LLMSettings.MinMessages = defaultLLM.MinMessages
-	}
-	if cfg.LLMSettings.MaxMessages == 0 {
-		cfg.LLMSettings.MaxMessages = defaultLLM.MaxMessages
-	}
-	if cfg.LLMSettings.MaxMessageLength == 0 {
-		cfg.LLMSettings.MaxMessageLength = defaultLLM.MaxMessageLength
-	}
-	if cfg.LLMSettings.LanguageStyle == "" {
-		cfg.LLMSettings.LanguageStyle = defaultLLM.LanguageStyle
-	}
-	if len(cfg.LLMSettings.FocusAreas) == 0 {
-		cfg.LLMSettings.FocusAreas = defaultLLM.FocusAreas
-	}
-	if cfg.LLMSettings.AFKHandling == "" {
-		cfg.LLMSettings.AFKHandling = defaultLLM.AFKHandling
+
+	fieldErrors = append(fieldErrors, UnknownKeys(data)...)
+	if err := cfg.Validate(); err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			fieldErrors = append(fieldErrors, validationErr.Errors...)
+		}
 	}
-	if cfg.LLMSettings.Temperature == 0 {
-		cfg.LLMSettings.Temperature = defaultLLM.Temperature
+
+	// Back up the original even when it is invalid, since the fixed config may be saved over it
+	var backupPath string
+	if migrated {
+		if backupPath, err = backupConfig(configPath, original, fromVersion); err != nil {
+			return cfg, err
+		}
 	}
-	
-	// Apply defaults for gold announcements
-	if len(cfg.GoldAnnouncements.Thresholds) == 0 {
-		cfg.GoldAnnouncements = DefaultConfig().GoldAnnouncements
+
+	if len(fieldErrors) > 0 {
+		// Return the config too, so callers like doctor can still report on it.
+		// A migrated config is only written back once it is valid.
+		return cfg, &ValidationError{Errors: fieldErrors}
 	}
-	if cfg.GoldAnnouncements.PollIntervalSec == 0 {
-		cfg.GoldAnnouncements.PollIntervalSec = 2
+
+	if migrated {
+		if err := SaveConfig(configPath, cfg); err != nil {
+			return cfg, fmt.Errorf("failed to save migrated config: %w", err)
+		}
+		log.Printf("Migrated config from version %d to %d (original saved to %s)", fromVersion, CurrentConfigVersion, backupPath)
 	}
 
-	return &cfg, nil
+	return cfg, nil
 }
 
+// SaveConfig writes cfg atomically (temp file plus rename), so a crash can't leave a truncated config
 func SaveConfig(configPath string, cfg *Config) error {
 	// Ensure directory exists
 	dir := filepath.Dir(configPath)
@@ -195,14 +310,37 @@ func SaveConfig(configPath string, cfg *Config) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	out := *cfg
+	out.ConfigVersion = CurrentConfigVersion
+	data, err := json.MarshalIndent(&out, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	tmp, err := os.CreateTemp(dir, filepath.Base(configPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp config file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("failed to set config file permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
 
 	return nil
 }
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes a config file into a temp directory and returns its path
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadKeepsExplicitZeros(t *testing.T) {
+	path := writeConfig(t, `{
+		"configVersion": 1,
+		"endOfGameCooldownSeconds": 0,
+		"llmSettings": {"temperature": 0},
+		"goldAnnouncements": {"enabled": false}
+	}`)
+
+	for _, step := range []string{"load", "reload after save"} {
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("%s: %v", step, err)
+		}
+		if cfg.EndOfGameCooldownSec != 0 || cfg.LLMSettings.Temperature != 0 || cfg.GoldAnnouncements.Enabled {
+			t.Fatalf("%s: explicit zeros replaced by defaults: cooldown %d, temperature %g, gold enabled %v",
+				step, cfg.EndOfGameCooldownSec, cfg.LLMSettings.Temperature, cfg.GoldAnnouncements.Enabled)
+		}
+		if cfg.PollIntervalSeconds != DefaultPollInterval || cfg.LLMSettings.Tone != "friendly" {
+			t.Fatalf("%s: missing keys should keep their defaults, got poll interval %d, tone %q",
+				step, cfg.PollIntervalSeconds, cfg.LLMSettings.Tone)
+		}
+		if err := SaveConfig(path, cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadMigratesV0(t *testing.T) {
+	original := `{
+		"endOfGameCooldownSeconds": 0,
+		"llmSettings": {"temperature": 0, "tone": ""},
+		"afkThresholds": {"minGameMinutes": 5, "maxCsPerMin": 0},
+		"goldAnnouncements": {"enabled": false, "thresholds": []}
+	}`
+	path := writeConfig(t, original)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Zeros the old loader replaced with defaults still get their defaults...
+	if cfg.EndOfGameCooldownSec != DefaultEoGCooldown || cfg.LLMSettings.Temperature != 0.7 || cfg.LLMSettings.Tone != "friendly" {
+		t.Errorf("expected v0 zeros to migrate to defaults, got cooldown %d, temperature %g, tone %q",
+			cfg.EndOfGameCooldownSec, cfg.LLMSettings.Temperature, cfg.LLMSettings.Tone)
+	}
+	if len(cfg.GoldAnnouncements.Thresholds) == 0 {
+		t.Errorf("expected the default gold thresholds")
+	}
+	// ...while the zeros it respected survive the migration
+	if cfg.GoldAnnouncements.Enabled || cfg.AFKThresholds.MaxCsPerMin != 0 || cfg.AFKThresholds.MinGameMinutes != 5 {
+		t.Errorf("explicit values lost in migration: gold enabled %v, maxCsPerMin %g, minGameMinutes %g",
+			cfg.GoldAnnouncements.Enabled, cfg.AFKThresholds.MaxCsPerMin, cfg.AFKThresholds.MinGameMinutes)
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil {
+		t.Fatalf("expected a backup of the v0 file: %v", err)
+	}
+	if string(backup) != original {
+		t.Errorf("backup differs from the original file:\n%s", backup)
+	}
+
+	// The upgraded file is written back, and loads the same without migrating again
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written struct {
+		ConfigVersion int `json:"configVersion"`
+	}
+	if err := json.Unmarshal(data, &written); err != nil || written.ConfigVersion != CurrentConfigVersion {
+		t.Fatalf("expected the file rewritten at version %d, got %d (%v)", CurrentConfigVersion, written.ConfigVersion, err)
+	}
+	if err := os.Remove(path + ".v0.bak"); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.GoldAnnouncements.Enabled || reloaded.AFKThresholds.MaxCsPerMin != 0 {
+		t.Errorf("explicit values lost on reload: gold enabled %v, maxCsPerMin %g", reloaded.GoldAnnouncements.Enabled, reloaded.AFKThresholds.MaxCsPerMin)
+	}
+	if _, err := os.Stat(path + ".v0.bak"); !os.IsNotExist(err) {
+		t.Errorf("a current file should not be migrated or backed up again")
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	original := `{"pollIntervalSeconds": -1, "llmSettings": {"tone": "snarky", "temprature": 0.5}, "autoCopyToClipboard": "yes"}`
+	path := writeConfig(t, original)
+
+	cfg, err := LoadConfig(path)
+	if cfg == nil {
+		t.Fatal("expected the config to be returned with its problems")
+	}
+	for _, field := range []string{"pollIntervalSeconds", "llmSettings.tone", "llmSettings.temprature", "autoCopyToClipboard"} {
+		if _, ok := findError(t, err, field); !ok {
+			t.Errorf("expected a problem with %s, got %v", field, err)
+		}
+	}
+
+	// An invalid v0 file is backed up, but not rewritten until it is fixed
+	if _, err := os.Stat(path + ".v0.bak"); err != nil {
+		t.Errorf("expected a backup of the invalid v0 file: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("an invalid config should not be rewritten")
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	path := writeConfig(t, `{"configVersion": 99}`)
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "newer than this build supports") {
+		t.Fatalf("expected a newer-version error, got %v", err)
+	}
+}
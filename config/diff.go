@@ -0,0 +1,145 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Change is one setting that differs between two configs
+type Change struct {
+	Path string      // JSON path, e.g. "llmSettings.tone"
+	Old  interface{} // Previous value (as decoded JSON)
+	New  interface{} // New value (as decoded JSON)
+}
+
+// secretPaths are never printed in change descriptions, nor is anything below them.
+// Webhook URLs carry the webhook's secret (e.g. Discord's token).
+var secretPaths = []string{"api.token", "webhooks"}
+
+func (c Change) String() string {
+	if Changed([]Change{c}, secretPaths...) {
+		return fmt.Sprintf("%s changed", c.Path)
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Path, jsonString(c.Old), jsonString(c.New))
+}
+
+// Diff lists every setting that differs between old and new, sorted by path.
+// Lists are compared as a whole.
+func Diff(old, new *Config) []Change {
+	oldRaw, newRaw := toRaw(old), toRaw(new)
+	var changes []Change
+	diffValues(&changes, "", oldRaw, newRaw)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// Changed reports whether any change is at or below one of the given paths
+func Changed(changes []Change, paths ...string) bool {
+	for _, change := range changes {
+		for _, path := range paths {
+			if change.Path == path || (len(change.Path) > len(path) && change.Path[:len(path)+1] == path+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func diffValues(changes *[]Change, path string, old, new interface{}) {
+	oldObj, oldIsObj := old.(map[string]interface{})
+	newObj, newIsObj := new.(map[string]interface{})
+	if oldIsObj && newIsObj {
+		for key, oldValue := range oldObj {
+			diffValues(changes, joinPath(path, key), oldValue, newObj[key])
+		}
+		for key, newValue := range newObj {
+			if _, ok := oldObj[key]; !ok {
+				diffValues(changes, joinPath(path, key), nil, newValue)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, Change{Path: path, Old: old, New: new})
+	}
+}
+
+// toRaw converts a config into generic JSON values for comparison
+func toRaw(cfg *Config) map[string]interface{} {
+	raw := make(map[string]interface{})
+	if cfg == nil {
+		return raw
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return raw
+	}
+	json.Unmarshal(data, &raw)
+	return raw
+}
+
+func jsonString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// RedactedSecret stands in for a secret in a redacted config. Sent back in a config update,
+// it keeps the current secret.
+const RedactedSecret = "<redacted>"
+
+// Redacted returns a copy of the config with the API token and webhook URLs replaced by
+// RedactedSecret, for showing the config to clients
+func (c *Config) Redacted() *Config {
+	cfg := *c
+	if cfg.API.Token != "" {
+		cfg.API.Token = RedactedSecret
+	}
+	cfg.Webhooks = append([]WebhookSettings(nil), c.Webhooks...)
+	for i := range cfg.Webhooks {
+		if cfg.Webhooks[i].URL != "" {
+			cfg.Webhooks[i].URL = RedactedSecret
+		}
+	}
+	return &cfg
+}
+
+// RestoreSecrets puts back the secrets that are still RedactedSecret from current. A redacted
+// webhook URL is taken from the current webhook with the same name; unnamed webhooks are matched
+// in order. One with no match is left as is, so validation rejects it.
+func (c *Config) RestoreSecrets(current *Config) {
+	if c.API.Token == RedactedSecret {
+		c.API.Token = current.API.Token
+	}
+
+	var unnamed []string // URLs of the current unnamed webhooks, in order
+	named := make(map[string]string)
+	for _, hook := range current.Webhooks {
+		if hook.Name == "" {
+			unnamed = append(unnamed, hook.URL)
+		} else {
+			named[hook.Name] = hook.URL
+		}
+	}
+	nextUnnamed := 0
+	for i := range c.Webhooks {
+		hook := &c.Webhooks[i]
+		if hook.Name == "" {
+			nextUnnamed++
+		}
+		if hook.URL != RedactedSecret {
+			continue
+		}
+		if hook.Name != "" {
+			if url, ok := named[hook.Name]; ok {
+				hook.URL = url
+			}
+		} else if nextUnnamed <= len(unnamed) {
+			hook.URL = unnamed[nextUnnamed-1]
+		}
+	}
+}
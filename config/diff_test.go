@@ -0,0 +1,151 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		paths  []string
+	}{
+		{
+			name:   "no changes",
+			modify: func(c *Config) {},
+		},
+		{
+			name: "nested settings, sorted by path",
+			modify: func(c *Config) {
+				c.TTS.Voice = "Zira"
+				c.LLMSettings.Tone = "humble"
+				c.LLMSettings.Temperature = 0
+			},
+			paths: []string{"llmSettings.temperature", "llmSettings.tone", "tts.voice"},
+		},
+		{
+			name:   "lists compare as a whole",
+			modify: func(c *Config) { c.LLMSettings.FocusAreas = []string{"all", "vision"} },
+			paths:  []string{"llmSettings.focusAreas"},
+		},
+		{
+			name:   "webhook added",
+			modify: func(c *Config) { c.Webhooks = []WebhookSettings{{URL: "https://example.com/hook"}} },
+			paths:  []string{"webhooks"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, new := DefaultConfig(), DefaultConfig()
+			tt.modify(new)
+			var paths []string
+			for _, change := range Diff(old, new) {
+				paths = append(paths, change.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.paths) {
+				t.Fatalf("expected changes %v, got %v", tt.paths, paths)
+			}
+		})
+	}
+
+	new := DefaultConfig()
+	new.LLMSettings.Tone = "humble"
+	changes := Diff(DefaultConfig(), new)
+	if len(changes) != 1 || changes[0].Old != "friendly" || changes[0].New != "humble" {
+		t.Fatalf("expected tone friendly -> humble, got %+v", changes)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	changes := []Change{{Path: "tts.voice"}, {Path: "llmSettings.tone"}}
+	tests := []struct {
+		paths []string
+		want  bool
+	}{
+		{[]string{"tts.voice"}, true},
+		{[]string{"tts"}, true},                     // A parent covers its children
+		{[]string{"tts.rate", "llmSettings"}, true}, // Any path may match
+		{[]string{"tts.voiceName"}, false},          // No partial key matches
+		{[]string{"llm"}, false},                    // Nor partial section names
+		{[]string{"llmSettings.tone.extra"}, false}, // A child doesn't cover its parent
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := Changed(changes, tt.paths...); got != tt.want {
+			t.Errorf("Changed(%v) = %v, want %v", tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestChangeStringRedactsSecrets(t *testing.T) {
+	old, new := DefaultConfig(), DefaultConfig()
+	old.API.Token = "old-secret"
+	new.API.Token = "new-secret"
+	new.Webhooks = []WebhookSettings{{URL: "https://discord.com/api/webhooks/1/hook-secret"}}
+	new.LLMSettings.Tone = "humble"
+
+	var lines []string
+	for _, change := range Diff(old, new) {
+		lines = append(lines, change.String())
+	}
+	text := strings.Join(lines, "\n")
+	for _, secret := range []string{"old-secret", "new-secret", "hook-secret"} {
+		if strings.Contains(text, secret) {
+			t.Errorf("change descriptions leak %q:\n%s", secret, text)
+		}
+	}
+	for _, want := range []string{"api.token changed", "webhooks changed", `llmSettings.tone: "friendly" -> "humble"`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in change descriptions:\n%s", want, text)
+		}
+	}
+}
+
+func TestRedactedRoundTrip(t *testing.T) {
+	current := DefaultConfig()
+	current.API.Token = "secret"
+	current.Webhooks = []WebhookSettings{
+		{Name: "discord", URL: "https://discord.com/api/webhooks/1/a"},
+		{URL: "https://example.com/b"},
+	}
+
+	redacted := current.Redacted()
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret", "/1/a", "example.com/b"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("redacted config leaks %q", secret)
+		}
+	}
+	if current.API.Token != "secret" || current.Webhooks[0].URL != "https://discord.com/api/webhooks/1/a" {
+		t.Fatalf("Redacted modified the original config")
+	}
+
+	// Sent back as shown, with the named hook moved and a new hook added
+	var patched Config
+	if err := json.Unmarshal(data, &patched); err != nil {
+		t.Fatal(err)
+	}
+	patched.Webhooks = []WebhookSettings{
+		patched.Webhooks[1],
+		patched.Webhooks[0],
+		{Name: "new", URL: RedactedSecret},
+	}
+	patched.RestoreSecrets(current)
+
+	if patched.API.Token != "secret" {
+		t.Errorf("expected the token restored, got %q", patched.API.Token)
+	}
+	want := []string{"https://example.com/b", "https://discord.com/api/webhooks/1/a", RedactedSecret}
+	for i, hook := range patched.Webhooks {
+		if hook.URL != want[i] {
+			t.Errorf("webhook %d: expected URL %q, got %q", i, want[i], hook.URL)
+		}
+	}
+}
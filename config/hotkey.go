@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hotkey is a parsed global hotkey such as "ctrl+shift+m"
+type Hotkey struct {
+	Ctrl, Alt, Shift, Win bool
+	Key                   string // Upper-case letter or digit, or "F1" to "F24"
+}
+
+// ParseHotkey parses a hotkey written as modifiers and one key joined by "+", e.g. "ctrl+alt+f9".
+// At least one modifier is required so the hotkey doesn't swallow normal typing.
+func ParseHotkey(spec string) (Hotkey, error) {
+	var hotkey Hotkey
+	parts := strings.Split(strings.ToLower(strings.ReplaceAll(spec, " ", "")), "+")
+	for i, part := range parts {
+		last := i == len(parts)-1
+		switch {
+		case part == "ctrl" || part == "control":
+			hotkey.Ctrl = true
+		case part == "alt":
+			hotkey.Alt = true
+		case part == "shift":
+			hotkey.Shift = true
+		case part == "win":
+			hotkey.Win = true
+		case last && len(part) == 1 && (part[0] >= 'a' && part[0] <= 'z' || part[0] >= '0' && part[0] <= '9'):
+			hotkey.Key = strings.ToUpper(part)
+		case last && isFunctionKey(part):
+			hotkey.Key = strings.ToUpper(part)
+		default:
+			return Hotkey{}, fmt.Errorf("must be modifiers (ctrl, alt, shift, win) and a letter, digit or F1-F24 joined by \"+\" (got %q)", spec)
+		}
+	}
+	if hotkey.Key == "" {
+		return Hotkey{}, fmt.Errorf("must end with a letter, digit or F1-F24 (got %q)", spec)
+	}
+	if !hotkey.Ctrl && !hotkey.Alt && !hotkey.Shift && !hotkey.Win {
+		return Hotkey{}, fmt.Errorf("must include ctrl, alt, shift or win (got %q)", spec)
+	}
+	return hotkey, nil
+}
+
+// isFunctionKey reports whether key is "f1" to "f24"
+func isFunctionKey(key string) bool {
+	var n int
+	if _, err := fmt.Sscanf(key, "f%d", &n); err != nil {
+		return false
+	}
+	return n >= 1 && n <= 24 && key == fmt.Sprintf("f%d", n)
+}
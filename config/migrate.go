@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+// CurrentConfigVersion is the configVersion written by this build
+const CurrentConfigVersion = 1
+
+// migration upgrades a raw config object from one version to the next
+type migration struct {
+	from        int
+	description string
+	apply       func(raw map[string]interface{})
+}
+
+// migrations run in order; each one upgrades version `from` to `from+1`
+var migrations = []migration{
+	{
+		from:        0,
+		description: "treat zero values as unset, as the loader used to",
+		apply:       migrateZeroValuesToUnset,
+	},
+}
+
+// migrate upgrades raw config JSON to CurrentConfigVersion. It returns the upgraded JSON and
+// the version the file was at; the data is returned unchanged if it is already current.
+func migrate(data []byte) ([]byte, int, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	version := 0 // Files from before configVersion existed
+	if v, ok := raw["configVersion"].(float64); ok {
+		version = int(v)
+	}
+	if version > CurrentConfigVersion {
+		return nil, version, fmt.Errorf("config version %d is newer than this build supports (%d) - please update LoL Kind Bot", version, CurrentConfigVersion)
+	}
+	if version == CurrentConfigVersion {
+		return data, version, nil
+	}
+
+	fromVersion := version
+	for _, m := range migrations {
+		if m.from != version {
+			continue
+		}
+		log.Printf("Migrating config from version %d to %d: %s", m.from, m.from+1, m.description)
+		m.apply(raw)
+		version = m.from + 1
+	}
+	if version != CurrentConfigVersion {
+		return nil, fromVersion, fmt.Errorf("no migration path from config version %d to %d", fromVersion, CurrentConfigVersion)
+	}
+	raw["configVersion"] = CurrentConfigVersion
+
+	upgraded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fromVersion, fmt.Errorf("failed to marshal migrated config: %w", err)
+	}
+	return upgraded, fromVersion, nil
+}
+
+// backupConfig copies the original file contents next to it before a migration rewrites it
+func backupConfig(configPath string, data []byte, version int) (string, error) {
+	backupPath := fmt.Sprintf("%s.v%d.bak", configPath, version)
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to back up config: %w", err)
+	}
+	return backupPath, nil
+}
+
+// migrateZeroValuesToUnset (v0 -> v1): the old loader replaced zero values with defaults, and
+// replaced the whole goldAnnouncements section when thresholds was empty (losing enabled: false).
+// Zero values now mean zero, so drop the keys the old loader ignored to keep their default.
+func migrateZeroValuesToUnset(raw map[string]interface{}) {
+	dropZero(raw, "ollamaModel", "ollamaUrl", "pollIntervalSeconds", "endOfGameCooldownSeconds")
+
+	if afk, ok := raw["afkThresholds"].(map[string]interface{}); ok && isZero(afk["minGameMinutes"]) {
+		delete(raw, "afkThresholds") // The old loader replaced the whole section
+	}
+
+	if llm, ok := raw["llmSettings"].(map[string]interface{}); ok {
+		dropZero(llm, "tone", "minMessages", "maxMessages", "maxMessageLength", "languageStyle",
+			"focusAreas", "afkHandling", "temperature")
+	}
+
+	if gold, ok := raw["goldAnnouncements"].(map[string]interface{}); ok {
+		// Keep enabled as written; only the empty fields fall back to defaults
+		dropZero(gold, "thresholds", "pollIntervalSec")
+	}
+}
+
+// dropZero deletes keys whose value is a JSON zero value (0, "", false, null or empty list)
+func dropZero(obj map[string]interface{}, keys ...string) {
+	for _, key := range keys {
+		if value, ok := obj[key]; ok && isZero(value) {
+			delete(obj, key)
+		}
+	}
+}
+
+func isZero(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
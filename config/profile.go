@@ -0,0 +1,153 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Profile overrides part of the config for a kind of game. Each section is a partial JSON
+// object: only the keys it contains replace the base config's values.
+type Profile struct {
+	LLMSettings         json.RawMessage `json:"llmSettings,omitempty"`         // Partial LLMSettings
+	GoldAnnouncements   json.RawMessage `json:"goldAnnouncements,omitempty"`   // Partial GoldAnnouncementSettings
+	AFKThresholds       json.RawMessage `json:"afkThresholds,omitempty"`       // Partial AFKThresholds
+	AutoCopyToClipboard *bool           `json:"autoCopyToClipboard,omitempty"` // Override auto-copy
+}
+
+// ProfileRule picks a profile automatically. All non-empty conditions must match;
+// the first matching rule wins.
+type ProfileRule struct {
+	Profile      string   `json:"profile"`                // Profile to use
+	QueueTypes   []string `json:"queueTypes,omitempty"`   // e.g. "RANKED_SOLO_5x5", "RANKED_FLEX_SR", "ARAM"
+	GameModes    []string `json:"gameModes,omitempty"`    // e.g. "CLASSIC", "ARAM", "URF"
+	PartyMembers []string `json:"partyMembers,omitempty"` // Matches if any of these summoners is in our party
+}
+
+// GameContext is what profile rules are matched against
+type GameContext struct {
+	QueueType    string
+	GameMode     string
+	PartyMembers []string
+}
+
+// profileSections maps the JSON name of each overridable section to its type, for validation
+var profileSections = map[string]interface{}{
+	"llmSettings":       LLMSettings{},
+	"goldAnnouncements": GoldAnnouncementSettings{},
+	"afkThresholds":     AFKThresholds{},
+}
+
+// SelectProfile returns the profile to use for a game: the manually chosen ActiveProfile if
+// set, otherwise the first matching rule, otherwise "" (the base config)
+func (c *Config) SelectProfile(game GameContext) string {
+	if c.ActiveProfile != "" {
+		return c.ActiveProfile
+	}
+	for _, rule := range c.ProfileRules {
+		if rule.matches(game) {
+			return rule.Profile
+		}
+	}
+	return ""
+}
+
+func (r ProfileRule) matches(game GameContext) bool {
+	if len(r.QueueTypes) == 0 && len(r.GameModes) == 0 && len(r.PartyMembers) == 0 {
+		return false
+	}
+	if len(r.QueueTypes) > 0 && !containsFold(r.QueueTypes, game.QueueType) {
+		return false
+	}
+	if len(r.GameModes) > 0 && !containsFold(r.GameModes, game.GameMode) {
+		return false
+	}
+	if len(r.PartyMembers) > 0 {
+		found := false
+		for _, member := range game.PartyMembers {
+			if containsFold(r.PartyMembers, member) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// WithProfile returns a copy of the config with the named profile applied.
+// An empty or unknown name returns an unmodified copy.
+func (c *Config) WithProfile(name string) *Config {
+	cfg, err := c.applyProfile(name)
+	if err != nil {
+		log.Printf("Failed to apply profile %q, using base config: %v", name, err)
+		return c.Clone()
+	}
+	return cfg
+}
+
+// ProfileNames returns the configured profile names in a stable order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Config) applyProfile(name string) (*Config, error) {
+	cfg := c.Clone()
+	profile, ok := c.Profiles[name]
+	if name == "" || !ok {
+		return cfg, nil
+	}
+
+	if len(profile.LLMSettings) > 0 {
+		if err := json.Unmarshal(profile.LLMSettings, &cfg.LLMSettings); err != nil {
+			return nil, fmt.Errorf("llmSettings: %w", err)
+		}
+	}
+	if len(profile.GoldAnnouncements) > 0 {
+		if err := json.Unmarshal(profile.GoldAnnouncements, &cfg.GoldAnnouncements); err != nil {
+			return nil, fmt.Errorf("goldAnnouncements: %w", err)
+		}
+	}
+	if len(profile.AFKThresholds) > 0 {
+		if err := json.Unmarshal(profile.AFKThresholds, &cfg.AFKThresholds); err != nil {
+			return nil, fmt.Errorf("afkThresholds: %w", err)
+		}
+	}
+	if profile.AutoCopyToClipboard != nil {
+		cfg.AutoCopyToClipboard = *profile.AutoCopyToClipboard
+	}
+	return cfg, nil
+}
+
+// Clone returns a deep copy of the config
+func (c *Config) Clone() *Config {
+	data, err := json.Marshal(c)
+	if err != nil {
+		copied := *c
+		return &copied
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		copied := *c
+		return &copied
+	}
+	return &cfg
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
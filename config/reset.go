@@ -0,0 +1,153 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// maxResetPasses bounds WithDefaultsForInvalid; a setting still invalid after a pass has its
+// parent reset in the next one
+const maxResetPasses = 16
+
+// removedItem marks a list element removed by WithDefaultsForInvalid until the list is compacted
+type removedItem struct{}
+
+// WithDefaultsForInvalid returns a copy of the config with every setting that fails validation
+// reset to its default. Settings that have no default (a webhook, a profile override, a list
+// item) are removed instead. A setting that is still invalid afterwards, such as a default
+// maxMessages below the file's minMessages, has its whole section reset.
+func (c *Config) WithDefaultsForInvalid() *Config {
+	raw := toRaw(c)
+	levels := make(map[string]int) // How far above each invalid path the next reset goes
+
+	for pass := 0; pass < maxResetPasses; pass++ {
+		cfg, err := fromRaw(raw)
+		if err != nil {
+			break
+		}
+		var validationErr *ValidationError
+		if !errors.As(cfg.Validate(), &validationErr) {
+			return cfg
+		}
+
+		for _, fieldErr := range validationErr.Errors {
+			segments := splitPath(fieldErr.Path)
+			target := segments[:max(len(segments)-levels[fieldErr.Path], 0)]
+			levels[fieldErr.Path]++
+			if len(target) == 0 {
+				return DefaultConfig()
+			}
+			// Fresh defaults each time, so later resets can't write into a shared default
+			if value, ok := lookupRaw(toRaw(DefaultConfig()), target); ok {
+				replaceRaw(raw, target, value, false)
+			} else {
+				replaceRaw(raw, target, nil, true)
+			}
+		}
+		compactRaw(raw)
+	}
+	return DefaultConfig()
+}
+
+// fromRaw decodes generic JSON values produced by toRaw back into a config
+func fromRaw(raw map[string]interface{}) (*Config, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// splitPath splits a field path like "webhooks[0].url" into "webhooks", "[0]" and "url"
+func splitPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(strings.ReplaceAll(path, "[", ".["), ".") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// rawIndex parses a "[N]" path segment as an index into a list of length n
+func rawIndex(segment string, n int) (int, bool) {
+	if !strings.HasPrefix(segment, "[") || !strings.HasSuffix(segment, "]") {
+		return 0, false
+	}
+	i, err := strconv.Atoi(segment[1 : len(segment)-1])
+	return i, err == nil && i >= 0 && i < n
+}
+
+// lookupRaw returns the value at path in generic JSON values
+func lookupRaw(node interface{}, path []string) (interface{}, bool) {
+	for _, segment := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			value, ok := n[segment]
+			if !ok {
+				return nil, false
+			}
+			node = value
+		case []interface{}:
+			i, ok := rawIndex(segment, len(n))
+			if !ok {
+				return nil, false
+			}
+			node = n[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// replaceRaw sets the value at path, or removes it if remove is set. Paths that don't exist are
+// left alone.
+func replaceRaw(root interface{}, path []string, value interface{}, remove bool) {
+	parent, ok := lookupRaw(root, path[:len(path)-1])
+	if !ok {
+		return
+	}
+	last := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		if remove {
+			delete(p, last)
+		} else {
+			p[last] = value
+		}
+	case []interface{}:
+		if i, ok := rawIndex(last, len(p)); ok {
+			if remove {
+				p[i] = removedItem{}
+			} else {
+				p[i] = value
+			}
+		}
+	}
+}
+
+// compactRaw drops the list elements replaceRaw removed
+func compactRaw(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			n[key] = compactRaw(value)
+		}
+	case []interface{}:
+		kept := make([]interface{}, 0, len(n))
+		for _, value := range n {
+			if _, removed := value.(removedItem); !removed {
+				kept = append(kept, compactRaw(value))
+			}
+		}
+		return kept
+	}
+	return node
+}
@@ -0,0 +1,393 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Allowed values for enumerated settings
+var (
+	AllowedTones          = []string{"friendly", "professional", "enthusiastic", "humble", "supportive"}
+	AllowedLanguageStyles = []string{"casual", "formal", "enthusiastic", "gamer"}
+	AllowedLanguages      = []string{"auto", "en", "es", "pt-BR", "ko", "de", "fr"} // "auto" follows the League client's locale
+	AllowedFocusAreas     = []string{"all", "positive", "kda", "teamplay", "vision"}
+	AllowedAFKHandling    = []string{"default", "empathetic", "neutral"}
+	AllowedWebhookEvents  = []string{"gameEnd", "messages", "goldMilestone"}
+	AllowedWebhookFormats = []string{"json", "discord"}
+	AllowedTTSEngines     = []string{"auto", "sapi", "espeak", "piper", "none"}
+	AllowedGoldModes      = []string{"build", "thresholds"}
+)
+
+// FieldError describes one invalid config field
+type FieldError struct {
+	Path    string `json:"path"`    // JSON path, e.g. "llmSettings.temperature"
+	Message string `json:"message"` // What is wrong and what is allowed
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationError lists every invalid field in a config
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, fmt.Sprintf("invalid config (%d problem(s)):", len(e.Errors)))
+	for _, fieldErr := range e.Errors {
+		lines = append(lines, "  - "+fieldErr.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// validator collects field errors
+type validator struct {
+	errors []FieldError
+}
+
+func (v *validator) add(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) intRange(path string, value, min, max int) {
+	if value < min || value > max {
+		v.add(path, "must be between %d and %d (got %d)", min, max, value)
+	}
+}
+
+func (v *validator) intMin(path string, value, min int) {
+	if value < min {
+		v.add(path, "must be at least %d (got %d)", min, value)
+	}
+}
+
+func (v *validator) floatRange(path string, value, min, max float64) {
+	if value < min || value > max {
+		v.add(path, "must be between %g and %g (got %g)", min, max, value)
+	}
+}
+
+func (v *validator) oneOf(path, value string, allowed []string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(path, "must be one of %s (got %q)", quoteList(allowed), value)
+}
+
+func (v *validator) httpURL(path, value string) {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.add(path, "must be an http:// or https:// URL (got %q)", value)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errors}
+}
+
+// Validate checks every field and returns a *ValidationError listing all problems, or nil
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if strings.TrimSpace(c.OllamaModel) == "" {
+		v.add("ollamaModel", "must not be empty (e.g. %q)", DefaultOllamaModel)
+	}
+	v.httpURL("ollamaUrl", c.OllamaURL)
+	v.intRange("pollIntervalSeconds", c.PollIntervalSeconds, 1, 60)
+	v.intRange("endOfGameCooldownSeconds", c.EndOfGameCooldownSec, 0, 3600)
+
+	validateSections(v, "", c)
+
+	// Objective timers
+	v.intRange("objectiveTimers.pollIntervalSec", c.ObjectiveTimers.PollIntervalSec, 1, 60)
+	for _, kind := range ObjectiveKinds {
+		v.intRange("objectiveTimers."+kind+".leadSeconds", c.ObjectiveTimers.Timer(kind).LeadSeconds, 0, 120)
+	}
+
+	// Text-to-speech
+	v.oneOf("tts.engine", c.TTS.Engine, AllowedTTSEngines)
+	v.intRange("tts.rate", c.TTS.Rate, -10, 10)
+	v.intRange("tts.volume", c.TTS.Volume, 0, 100)
+	if c.TTS.Engine == "piper" && strings.TrimSpace(c.TTS.PiperModel) == "" {
+		v.add("tts.piperModel", "must be set to a voice model (.onnx) when tts.engine is %q", "piper")
+	}
+	if c.TTS.MuteHotkey != "" {
+		if _, err := ParseHotkey(c.TTS.MuteHotkey); err != nil {
+			v.add("tts.muteHotkey", "%v", err)
+		}
+	}
+
+	// Control API
+	v.intRange("api.port", c.API.Port, 1, 65535)
+
+	// Feedback
+	v.intRange("feedback.minSamples", c.Feedback.MinSamples, 1, 1000)
+
+	// Webhooks
+	for i, hook := range c.Webhooks {
+		path := fmt.Sprintf("webhooks[%d]", i)
+		v.httpURL(path+".url", hook.URL)
+		if hook.Format != "" {
+			v.oneOf(path+".format", hook.Format, AllowedWebhookFormats)
+		}
+		for j, event := range hook.Events {
+			v.oneOf(fmt.Sprintf("%s.events[%d]", path, j), event, AllowedWebhookEvents)
+		}
+		v.intRange(path+".maxRetries", hook.MaxRetries, 0, 10)
+	}
+
+	// Profiles are validated as the config they produce
+	for _, name := range c.ProfileNames() {
+		path := "profiles." + name
+		cfg, err := c.applyProfile(name)
+		if err != nil {
+			v.add(path, "%v", err)
+			continue
+		}
+		validateSections(v, path+".", cfg)
+	}
+	for i, rule := range c.ProfileRules {
+		path := fmt.Sprintf("profileRules[%d]", i)
+		if _, ok := c.Profiles[rule.Profile]; !ok {
+			v.add(path+".profile", "must be one of the configured profiles (%s) (got %q)", quoteList(c.ProfileNames()), rule.Profile)
+		}
+		if len(rule.QueueTypes) == 0 && len(rule.GameModes) == 0 && len(rule.PartyMembers) == 0 {
+			v.add(path, "must set at least one of queueTypes, gameModes or partyMembers")
+		}
+	}
+	if c.ActiveProfile != "" {
+		if _, ok := c.Profiles[c.ActiveProfile]; !ok {
+			v.add("activeProfile", "must be \"\" (automatic) or one of the configured profiles (%s) (got %q)", quoteList(c.ProfileNames()), c.ActiveProfile)
+		}
+	}
+
+	return v.err()
+}
+
+// validateSections checks the sections a profile can override; prefix is prepended to each path
+func validateSections(v *validator, prefix string, c *Config) {
+	// AFK thresholds
+	if c.AFKThresholds.MinGameMinutes < 0 {
+		v.add(prefix+"afkThresholds.minGameMinutes", "must be at least 0 (got %g)", c.AFKThresholds.MinGameMinutes)
+	}
+	if c.AFKThresholds.MaxCsPerMin < 0 {
+		v.add(prefix+"afkThresholds.maxCsPerMin", "must be at least 0 (got %g)", c.AFKThresholds.MaxCsPerMin)
+	}
+	v.intMin(prefix+"afkThresholds.maxDamageToChamp", c.AFKThresholds.MaxDamageToChamp, 0)
+	v.intMin(prefix+"afkThresholds.maxGoldEarned", c.AFKThresholds.MaxGoldEarned, 0)
+
+	// LLM settings
+	llm := c.LLMSettings
+	v.oneOf(prefix+"llmSettings.tone", llm.Tone, AllowedTones)
+	v.oneOf(prefix+"llmSettings.languageStyle", llm.LanguageStyle, AllowedLanguageStyles)
+	v.oneOf(prefix+"llmSettings.language", llm.Language, AllowedLanguages)
+	v.oneOf(prefix+"llmSettings.afkHandling", llm.AFKHandling, AllowedAFKHandling)
+	v.intRange(prefix+"llmSettings.minMessages", llm.MinMessages, 1, 10)
+	v.intRange(prefix+"llmSettings.maxMessages", llm.MaxMessages, 1, 10)
+	if llm.MaxMessages < llm.MinMessages {
+		v.add(prefix+"llmSettings.maxMessages", "must be at least minMessages (%d) (got %d)", llm.MinMessages, llm.MaxMessages)
+	}
+	v.intRange(prefix+"llmSettings.maxMessageLength", llm.MaxMessageLength, 20, 500)
+	v.floatRange(prefix+"llmSettings.temperature", llm.Temperature, 0, 1)
+	v.intRange(prefix+"llmSettings.maxTokens", llm.MaxTokens, 0, 32768)
+	if len(llm.FocusAreas) == 0 {
+		v.add(prefix+"llmSettings.focusAreas", "must contain at least one of %s", quoteList(AllowedFocusAreas))
+	}
+	for i, area := range llm.FocusAreas {
+		v.oneOf(fmt.Sprintf("%sllmSettings.focusAreas[%d]", prefix, i), area, AllowedFocusAreas)
+	}
+
+	// Gold announcements
+	v.intRange(prefix+"goldAnnouncements.pollIntervalSec", c.GoldAnnouncements.PollIntervalSec, 1, 60)
+	for i, threshold := range c.GoldAnnouncements.Thresholds {
+		v.intRange(fmt.Sprintf("%sgoldAnnouncements.thresholds[%d]", prefix, i), threshold, 1, 100000)
+	}
+	v.oneOf(prefix+"goldAnnouncements.mode", c.GoldAnnouncements.Mode, AllowedGoldModes)
+	v.intRange(prefix+"goldAnnouncements.almostGold", c.GoldAnnouncements.AlmostGold, 0, 5000)
+	for champion, items := range c.GoldAnnouncements.Builds {
+		path := prefix + "goldAnnouncements.builds." + champion
+		if len(items) == 0 {
+			v.add(path, "must list at least one item")
+		}
+		for i, item := range items {
+			if strings.TrimSpace(item) == "" {
+				v.add(fmt.Sprintf("%s[%d]", path, i), "must be an item name or ID")
+			}
+		}
+	}
+}
+
+// UnknownKeys reports JSON keys that don't correspond to any Config field, with typo suggestions
+func UnknownKeys(data []byte) []FieldError {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil // Syntax errors are reported by the real decode
+	}
+	v := &validator{}
+	walkKeys(v, raw, reflect.TypeOf(Config{}), "")
+	return v.errors
+}
+
+// walkKeys compares raw JSON against the fields of t, recursing into nested structs and slices
+func walkKeys(v *validator, raw interface{}, t reflect.Type, path string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// Profile sections are raw JSON checked against the section they override
+	if t == reflect.TypeOf(json.RawMessage{}) {
+		if section, ok := profileSections[path[strings.LastIndex(path, ".")+1:]]; ok {
+			walkKeys(v, raw, reflect.TypeOf(section), path)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return // Type mismatches are reported by the real decode
+		}
+		fields := jsonFields(t)
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldPath := joinPath(path, key)
+			field, ok := fields[key]
+			if !ok {
+				v.add(fieldPath, "unknown key%s", suggestion(key, fields))
+				continue
+			}
+			walkKeys(v, obj[key], field.Type, fieldPath)
+		}
+	case reflect.Map:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, value := range obj {
+			walkKeys(v, value, t.Elem(), joinPath(path, key))
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := raw.([]interface{})
+		if !ok {
+			return
+		}
+		for i, elem := range arr {
+			walkKeys(v, elem, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+// jsonFields maps JSON names to struct fields
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// suggestion returns " (did you mean ...?)" for the closest known key, if any is close enough
+func suggestion(key string, fields map[string]reflect.StructField) string {
+	best := ""
+	bestDist := -1
+	for name := range fields {
+		dist := editDistance(strings.ToLower(key), strings.ToLower(name))
+		if bestDist == -1 || dist < bestDist || (dist == bestDist && name < best) {
+			best, bestDist = name, dist
+		}
+	}
+	if best == "" || bestDist > len(key)/3+1 {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// typeError converts a JSON type mismatch into a field error
+func typeError(err *json.UnmarshalTypeError) FieldError {
+	path := err.Field
+	if path == "" {
+		path = "(root)"
+	}
+	return FieldError{Path: path, Message: fmt.Sprintf("must be %s (got JSON %s)", describeKind(err.Type), err.Value)}
+}
+
+func describeKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return "a whole number"
+	case reflect.Float64, reflect.Float32:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	}
+	return t.String()
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
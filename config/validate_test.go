@@ -0,0 +1,214 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// findError returns the message for path in a *ValidationError, and whether there was one
+func findError(t *testing.T, err error, path string) (string, bool) {
+	t.Helper()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+	for _, fieldErr := range validationErr.Errors {
+		if fieldErr.Path == path {
+			return fieldErr.Message, true
+		}
+	}
+	return "", false
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		path    string // Field expected to be invalid; "" if the config is valid
+		message string // Expected in that field's message
+	}{
+		{
+			name:   "defaults",
+			modify: func(c *Config) {},
+		},
+		{
+			name: "explicit zeros",
+			modify: func(c *Config) {
+				c.LLMSettings.Temperature = 0
+				c.EndOfGameCooldownSec = 0
+				c.GoldAnnouncements.Enabled = false
+			},
+		},
+		{
+			name: "maxMessages below minMessages",
+			modify: func(c *Config) {
+				c.LLMSettings.MinMessages = 4
+				c.LLMSettings.MaxMessages = 2
+			},
+			path:    "llmSettings.maxMessages",
+			message: "must be at least minMessages (4) (got 2)",
+		},
+		{
+			name:    "negative poll interval",
+			modify:  func(c *Config) { c.PollIntervalSeconds = -5 },
+			path:    "pollIntervalSeconds",
+			message: "must be between 1 and 60 (got -5)",
+		},
+		{
+			name:    "unknown tone",
+			modify:  func(c *Config) { c.LLMSettings.Tone = "snarky" },
+			path:    "llmSettings.tone",
+			message: `"friendly"`,
+		},
+		{
+			name:    "temperature above 1",
+			modify:  func(c *Config) { c.LLMSettings.Temperature = 1.5 },
+			path:    "llmSettings.temperature",
+			message: "must be between 0 and 1",
+		},
+		{
+			name:    "unknown focus area",
+			modify:  func(c *Config) { c.LLMSettings.FocusAreas = []string{"kda", "farming"} },
+			path:    "llmSettings.focusAreas[1]",
+			message: `(got "farming")`,
+		},
+		{
+			name:    "webhook without scheme",
+			modify:  func(c *Config) { c.Webhooks = []WebhookSettings{{URL: "discord.com/api/webhooks/1"}} },
+			path:    "webhooks[0].url",
+			message: "must be an http:// or https:// URL",
+		},
+		{
+			name: "invalid profile override",
+			modify: func(c *Config) {
+				c.Profiles = map[string]Profile{"aram": {LLMSettings: json.RawMessage(`{"tone": "snarky"}`)}}
+			},
+			path:    "profiles.aram.llmSettings.tone",
+			message: `(got "snarky")`,
+		},
+		{
+			name:    "rule for a missing profile",
+			modify:  func(c *Config) { c.ProfileRules = []ProfileRule{{Profile: "ranked", QueueTypes: []string{"RANKED_SOLO_5x5"}}} },
+			path:    "profileRules[0].profile",
+			message: `(got "ranked")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.path == "" {
+				if err != nil {
+					t.Fatalf("expected a valid config, got %v", err)
+				}
+				return
+			}
+			message, ok := findError(t, err, tt.path)
+			if !ok {
+				t.Fatalf("expected an error for %s, got %v", tt.path, err)
+			}
+			if !strings.Contains(message, tt.message) {
+				t.Fatalf("expected the %s error to contain %q, got %q", tt.path, tt.message, message)
+			}
+		})
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		path    string
+		message string
+	}{
+		{
+			name:    "typo in a section",
+			json:    `{"llmSettings": {"tone": "friendly", "temprature": 0.5}}`,
+			path:    "llmSettings.temprature",
+			message: `unknown key (did you mean "temperature"?)`,
+		},
+		{
+			name:    "typo at the top level",
+			json:    `{"pollIntervalSecond": 3}`,
+			path:    "pollIntervalSecond",
+			message: `unknown key (did you mean "pollIntervalSeconds"?)`,
+		},
+		{
+			name:    "nothing close",
+			json:    `{"colour": "red"}`,
+			path:    "colour",
+			message: "unknown key",
+		},
+		{
+			name:    "typo in a profile override",
+			json:    `{"profiles": {"aram": {"llmSettings": {"tonne": "humble"}}}}`,
+			path:    "profiles.aram.llmSettings.tonne",
+			message: `unknown key (did you mean "tone"?)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := UnknownKeys([]byte(tt.json))
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 unknown key, got %v", errs)
+			}
+			if errs[0].Path != tt.path || errs[0].Message != tt.message {
+				t.Fatalf("expected %s: %s, got %v", tt.path, tt.message, errs[0])
+			}
+		})
+	}
+
+	if errs := UnknownKeys([]byte(`{"llmSettings": {"tone": "friendly"}, "webhooks": [{"url": "https://example.com"}]}`)); len(errs) != 0 {
+		t.Fatalf("expected no unknown keys, got %v", errs)
+	}
+}
+
+func TestWithDefaultsForInvalid(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MySummonerName = "me"
+	cfg.LLMSettings.Temperature = 0 // Valid, so kept
+	cfg.LLMSettings.Tone = "snarky"
+	cfg.LLMSettings.FocusAreas = []string{"kda", "farming"}
+	cfg.Webhooks = []WebhookSettings{{URL: "nope"}, {URL: "https://example.com/hook"}}
+	cfg.Profiles = map[string]Profile{"aram": {LLMSettings: json.RawMessage(`{"temperature": 3, "tone": "humble"}`)}}
+
+	fixed := cfg.WithDefaultsForInvalid()
+	if err := fixed.Validate(); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+	if fixed.MySummonerName != "me" || fixed.LLMSettings.Temperature != 0 {
+		t.Errorf("valid settings were changed: summoner %q, temperature %g", fixed.MySummonerName, fixed.LLMSettings.Temperature)
+	}
+	if fixed.LLMSettings.Tone != "friendly" {
+		t.Errorf("expected the default tone, got %q", fixed.LLMSettings.Tone)
+	}
+	if got := fixed.LLMSettings.FocusAreas; len(got) != 1 || got[0] != "kda" {
+		t.Errorf("expected only the unknown focus area removed, got %v", got)
+	}
+	if len(fixed.Webhooks) != 1 || fixed.Webhooks[0].URL != "https://example.com/hook" {
+		t.Errorf("expected only the invalid webhook removed, got %+v", fixed.Webhooks)
+	}
+	if got := string(fixed.Profiles["aram"].LLMSettings); got != `{"tone":"humble"}` {
+		t.Errorf("expected the profile's invalid override removed, got %s", got)
+	}
+	if cfg.LLMSettings.Tone != "snarky" || len(cfg.Webhooks) != 2 {
+		t.Errorf("the original config was modified")
+	}
+
+	// The default maxMessages is still below this minMessages, so the section is reset
+	cfg = DefaultConfig()
+	cfg.LLMSettings.MinMessages = 5
+	cfg.LLMSettings.MaxMessages = 4
+	fixed = cfg.WithDefaultsForInvalid()
+	if err := fixed.Validate(); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+	if fixed.LLMSettings.MinMessages != 2 || fixed.LLMSettings.MaxMessages != 3 {
+		t.Errorf("expected the default message counts, got %d-%d", fixed.LLMSettings.MinMessages, fixed.LLMSettings.MaxMessages)
+	}
+}
@@ -83,6 +83,7 @@ type EoGTeam struct {
 }
 
 type EoGStatsBlock struct {
+	GameID              int64            `json:"gameId,omitempty"`
 	GameDurationSeconds int              `json:"gameDuration"`
 	GameLength          int              `json:"gameLength"` // Alternative field name
 	Participants        []EoGParticipant `json:"participants"`
@@ -112,6 +113,12 @@ func ParseEoGStats(data []byte) (*EoGStatsBlock, error) {
 		gameDuration = int(gameDurationRaw)
 	}
 	
+	// Extract game ID (to look the game up in match history)
+	var gameID int64
+	if id, ok := rawData["gameId"].(float64); ok {
+		gameID = int64(id)
+	}
+	
 	// Extract game mode information
 	gameMode := ""
 	if gm, ok := rawData["gameMode"].(string); ok {
@@ -406,6 +413,7 @@ func ParseEoGStats(data []byte) (*EoGStatsBlock, error) {
 	// If we found participants, return them
 	if len(allParticipants) > 0 {
 		return &EoGStatsBlock{
+			GameID:              gameID,
 			Participants:        allParticipants,
 			GameDurationSeconds: gameDuration,
 			GameMode:            gameMode,
@@ -420,6 +428,7 @@ func ParseEoGStats(data []byte) (*EoGStatsBlock, error) {
 		// Check if we have participants at top level
 		if len(wrapper.Participants) > 0 {
 			result := &EoGStatsBlock{
+				GameID:       gameID,
 				Participants: wrapper.Participants,
 			}
 			if wrapper.GameLength > 0 {
@@ -433,6 +442,9 @@ func ParseEoGStats(data []byte) (*EoGStatsBlock, error) {
 		// Check if we have a nested statsBlock
 		if wrapper.StatsBlock != nil && len(wrapper.StatsBlock.Participants) > 0 {
 			result := wrapper.StatsBlock
+			if result.GameID == 0 {
+				result.GameID = gameID
+			}
 			if result.GameDurationSeconds == 0 {
 				if wrapper.GameLength > 0 {
 					result.GameDurationSeconds = wrapper.GameLength
@@ -0,0 +1,182 @@
+package feedback
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileName is the feedback file kept next to config.json
+const FileName = "feedback.jsonl"
+
+// Signals recorded for a message
+const (
+	SignalCopied     = "copied"     // We copied it
+	SignalSent       = "sent"       // It showed up in our post-game chat
+	SignalEdited     = "edited"     // We edited it before use (recorded for the original text)
+	SignalDismissed  = "dismissed"  // The messages window closed without us using it
+	SignalThumbsUp   = "thumbsUp"   // Explicit rating
+	SignalThumbsDown = "thumbsDown" // Explicit rating
+)
+
+// signalWeights is how much each signal says we liked a message
+var signalWeights = map[string]float64{
+	SignalSent:       3,
+	SignalThumbsUp:   2,
+	SignalCopied:     1,
+	SignalEdited:     -0.5,
+	SignalDismissed:  -0.5,
+	SignalThumbsDown: -2,
+}
+
+// Record is one piece of feedback on a message, with the inputs it was generated from
+type Record struct {
+	Time    time.Time `json:"time"`
+	GameID  string    `json:"gameId,omitempty"`
+	Signal  string    `json:"signal"`
+	Message string    `json:"message"`
+	Edited  string    `json:"edited,omitempty"` // Our version, for SignalEdited
+
+	// Prompt inputs
+	Champion      string   `json:"champion,omitempty"` // Champion the message praised
+	PlayerTags    []string `json:"playerTags,omitempty"`
+	Won           bool     `json:"won"` // Whether the praised player's team won
+	Score         float64  `json:"score,omitempty"`
+	Tone          string   `json:"tone,omitempty"`
+	LanguageStyle string   `json:"languageStyle,omitempty"`
+	MaxLength     int      `json:"maxLength,omitempty"`
+	QueueType     string   `json:"queueType,omitempty"`
+	Profile       string   `json:"profile,omitempty"`
+}
+
+// Weight returns how much the record says we liked the message (negative = disliked)
+func (r Record) Weight() float64 {
+	return signalWeights[r.Signal]
+}
+
+// LikedText returns the text we liked for a positive record: our edit if we made one
+func (r Record) LikedText() string {
+	if r.Edited != "" {
+		return r.Edited
+	}
+	return r.Message
+}
+
+// Store appends feedback records to a JSON Lines file
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a store backed by path. The file is created on the first Add.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// PathFor returns the feedback file path for a given config path
+func PathFor(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), FileName)
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Add appends a record to the feedback file
+func (s *Store) Add(record Record) error {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feedback: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create feedback directory: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open feedback file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write feedback: %w", err)
+	}
+	return nil
+}
+
+// All returns every record, oldest first, skipping lines that fail to parse
+func (s *Store) All() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open feedback file: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue // Skip a truncated line from a crash
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read feedback file: %w", err)
+	}
+	return records, nil
+}
+
+// Reset deletes all recorded feedback, forgetting the learned preferences
+func (s *Store) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete feedback file: %w", err)
+	}
+	return nil
+}
+
+// Preferences learns preferences from every recorded piece of feedback
+func (s *Store) Preferences() (*Preferences, error) {
+	records, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	return Learn(records), nil
+}
+
+// Export returns every record along with the preferences learned from them
+func (s *Store) Export() (*Export, error) {
+	records, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		records = []Record{}
+	}
+	return &Export{Preferences: Learn(records), Records: records}, nil
+}
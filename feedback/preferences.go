@@ -0,0 +1,204 @@
+package feedback
+
+import (
+	"lol-kind-bot/analyzer"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	minCandidateSamples = 5   // Feedback needed before candidates are re-ranked
+	minToneSamples      = 5   // Feedback needed on a tone before it can be preferred
+	minLikedForLength   = 5   // Liked messages needed before the length is tuned
+	toneMargin          = 0.5 // How much better a tone must do than the configured one
+	maxCandidateBonus   = 2.0 // Cap on the re-ranking bonus (judge scores are 0-10)
+)
+
+// Preferences is what our feedback says we like
+type Preferences struct {
+	Samples         int                `json:"samples"`
+	TagWeights      map[string]float64 `json:"tagWeights"`      // Average weight of messages praising players with each tag
+	ChampionWeights map[string]float64 `json:"championWeights"` // Average weight of messages praising each champion
+	ToneWeights     map[string]float64 `json:"toneWeights"`     // Average weight of messages written in each tone
+	ToneSamples     map[string]int     `json:"toneSamples"`
+	PreferredLength int                `json:"preferredLength"` // Median length in characters of messages we liked (0 = unknown)
+
+	liked []Record // Positive records, newest first
+}
+
+// Export is everything learned, for backing up or inspecting feedback
+type Export struct {
+	Preferences *Preferences `json:"preferences"`
+	Records     []Record     `json:"records"`
+}
+
+// Learn builds preferences from feedback records (oldest first)
+func Learn(records []Record) *Preferences {
+	p := &Preferences{
+		TagWeights:      make(map[string]float64),
+		ChampionWeights: make(map[string]float64),
+		ToneWeights:     make(map[string]float64),
+		ToneSamples:     make(map[string]int),
+	}
+
+	tagCounts := make(map[string]int)
+	championCounts := make(map[string]int)
+	var likedLengths []int
+
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		weight := record.Weight()
+		if weight == 0 {
+			continue // Unknown signal
+		}
+		p.Samples++
+
+		for _, tag := range record.PlayerTags {
+			p.TagWeights[tag] += weight
+			tagCounts[tag]++
+		}
+		if record.Champion != "" {
+			p.ChampionWeights[record.Champion] += weight
+			championCounts[record.Champion]++
+		}
+		if record.Tone != "" {
+			p.ToneWeights[record.Tone] += weight
+			p.ToneSamples[record.Tone]++
+		}
+		if weight > 0 {
+			p.liked = append(p.liked, record)
+			likedLengths = append(likedLengths, utf8.RuneCountInString(record.LikedText()))
+		}
+	}
+
+	for tag, count := range tagCounts {
+		p.TagWeights[tag] /= float64(count)
+	}
+	for champion, count := range championCounts {
+		p.ChampionWeights[champion] /= float64(count)
+	}
+	for tone, count := range p.ToneSamples {
+		p.ToneWeights[tone] /= float64(count)
+	}
+	if len(likedLengths) >= minLikedForLength {
+		sort.Ints(likedLengths)
+		p.PreferredLength = likedLengths[len(likedLengths)/2]
+	}
+	return p
+}
+
+// CandidateBonus returns how much to add to a candidate's judge score, based on how we
+// rated messages about similar players (0 until there is enough feedback)
+func (p *Preferences) CandidateBonus(player analyzer.PlayerSummary) float64 {
+	if p == nil || p.Samples < minCandidateSamples {
+		return 0
+	}
+
+	var total float64
+	var count int
+	for _, tag := range player.Tags {
+		if weight, ok := p.TagWeights[tag]; ok {
+			total += weight
+			count++
+		}
+	}
+	bonus := 0.0
+	if count > 0 {
+		bonus = total / float64(count)
+	}
+	bonus += p.ChampionWeights[player.Champion] / 2
+
+	return math.Max(-maxCandidateBonus, math.Min(maxCandidateBonus, bonus))
+}
+
+// Examples returns up to n messages we liked about players similar to player, most similar first
+func (p *Preferences) Examples(player analyzer.PlayerSummary, n int) []string {
+	if p == nil || n <= 0 || len(p.liked) == 0 {
+		return nil
+	}
+
+	type candidate struct {
+		text  string
+		score int
+		order int
+	}
+	var candidates []candidate
+	seen := make(map[string]bool)
+	for i, record := range p.liked {
+		text := strings.TrimSpace(record.LikedText())
+		if text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+
+		score := 0
+		for _, tag := range record.PlayerTags {
+			for _, playerTag := range player.Tags {
+				if tag == playerTag {
+					score += 2
+				}
+			}
+		}
+		if record.Champion == player.Champion {
+			score++
+		}
+		candidates = append(candidates, candidate{text: text, score: score, order: i})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].order < candidates[j].order // Newer first
+	})
+
+	examples := make([]string, 0, n)
+	for _, c := range candidates {
+		if len(examples) == n {
+			break
+		}
+		examples = append(examples, c.text)
+	}
+	return examples
+}
+
+// Tone returns the tone our feedback favours, or current if there isn't a clearly better one
+func (p *Preferences) Tone(current string, minSamples int) string {
+	if p == nil || p.Samples < minSamples {
+		return current
+	}
+
+	best, bestWeight := current, p.ToneWeights[current]
+	for tone, weight := range p.ToneWeights {
+		if p.ToneSamples[tone] < minToneSamples {
+			continue
+		}
+		if weight > bestWeight {
+			best, bestWeight = tone, weight
+		}
+	}
+	if best != current && bestWeight < p.ToneWeights[current]+toneMargin {
+		return current
+	}
+	return best
+}
+
+// MessageLength returns a maximum message length fitted to the messages we liked,
+// or current if there isn't enough feedback yet
+func (p *Preferences) MessageLength(current int, minSamples int) int {
+	if p == nil || p.Samples < minSamples || p.PreferredLength == 0 {
+		return current
+	}
+
+	// Leave headroom above the typical liked message, rounded up to 10 characters
+	length := (p.PreferredLength*5/4 + 9) / 10 * 10
+	if length < 40 {
+		length = 40
+	}
+	if length > 300 {
+		length = 300
+	}
+	return length
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package main
+
+import (
+	"log"
+	"lol-kind-bot/app"
+	"lol-kind-bot/config"
+)
+
+// newGUI returns the UI adapter used by the tray build (console output off Windows)
+func newGUI() app.UI {
+	return app.ConsoleUI{}
+}
+
+// runGUI falls back to headless mode - the tray and Fyne UI are Windows-only. Without a settings
+// window to fix them in, config problems are fatal as they are for serve.
+func runGUI(bot *app.App, loadErrors *config.ValidationError) {
+	if loadErrors != nil {
+		log.Fatalf("Failed to load config: %v", loadErrors)
+	}
+	log.Println("The system tray UI is only available on Windows; running headless (same as `serve`)")
+	if err := runServe(bot); err != nil {
+		log.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,367 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"log"
+	"lol-kind-bot/app"
+	"lol-kind-bot/config"
+	"lol-kind-bot/llm"
+	"lol-kind-bot/ui"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/getlantern/systray"
+)
+
+// trayUI shows bot output through the Windows toast/TTS and Fyne dialogs
+type trayUI struct {
+	mu            sync.Mutex
+	dialogShowing bool
+}
+
+func (t *trayUI) ShowToast(title, message string) {
+	ui.ShowToast(title, message)
+}
+
+// ShowMessages shows the popup window with message suggestions (only one at a time)
+func (t *trayUI) ShowMessages(set *app.MessageSet) {
+	t.mu.Lock()
+	if t.dialogShowing {
+		t.mu.Unlock()
+		log.Println("Dialog already showing, skipping")
+		return
+	}
+	t.dialogShowing = true
+	t.mu.Unlock()
+
+	// Ensure Fyne app is initialized before creating windows
+	_ = ui.GetFyneApp()
+
+	// Use a goroutine to create the window, but ensure app is running first
+	go func() {
+		// Small delay to ensure app.Run() has started
+		time.Sleep(200 * time.Millisecond)
+
+		// Use RunOnMain to ensure UI operations happen on the correct thread
+		ui.RunOnMain(func() {
+			defer func() {
+				t.mu.Lock()
+				t.dialogShowing = false
+				t.mu.Unlock()
+			}()
+
+			ui.ShowMessagesDialog(set)
+		})
+	}()
+}
+
+// ShowReply opens the messages window, whose replies section lists the post-game chat,
+// unless it is already open
+func (t *trayUI) ShowReply(set *app.MessageSet, reply llm.ChatReply) {
+	t.ShowMessages(set)
+}
+
+// ShowOpeners opens the champion select openers window, which closes itself when the game starts
+func (t *trayUI) ShowOpeners(set *app.OpenerSet) {
+	ui.ShowToast("LoL Kind Bot", "Champ select openers ready!")
+	go ui.ShowOpenersDialog(set)
+}
+
+// newGUI returns the UI adapter used by the tray build
+func newGUI() app.UI {
+	return &trayUI{}
+}
+
+// runGUI runs the bot with the system tray and Fyne UI. loadErrors are problems found in
+// config.json, whose settings were reset to their defaults; the settings window opens listing them.
+func runGUI(bot *app.App, loadErrors *config.ValidationError) {
+	// Show console if debug mode is enabled
+	if !debugMode {
+		// Hide console window - run in background
+		ui.HideConsole()
+	}
+
+	// Initialize COM for Windows UI components (must be done before any UI operations)
+	if err := ui.InitializeCOM(); err != nil {
+		log.Printf("Warning: Failed to initialize COM: %v (UI dialogs may not work)", err)
+	}
+	defer ui.UninitializeCOM()
+
+	// Show first-run dialog if summoner name is not set
+	if cfg := bot.Config(); cfg.MySummonerName == "" {
+		log.Println("Summoner name not configured. Showing first-run dialog...")
+
+		summonerName, ok := ui.ShowFirstRunDialog()
+		if !ok || summonerName == "" {
+			log.Println("First-run setup cancelled. Exiting...")
+			os.Exit(0)
+		}
+
+		// Update config with summoner name
+		newCfg := *cfg
+		newCfg.MySummonerName = summonerName
+		if err := bot.UpdateConfig(&newCfg); err != nil {
+			log.Printf("Failed to save config: %v", err)
+		} else {
+			log.Printf("Saved summoner name: %s", summonerName)
+		}
+	}
+
+	logConfigSummary(bot)
+
+	// Initialize Fyne app (creates app instance, sets theme)
+	// This must be called early, before any UI operations
+	fyneApp := ui.StartFyneApp()
+
+	// Setup signal handling - will quit Fyne app on signal
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		log.Println("Shutting down...")
+		bot.Stop()
+		// Quit Fyne app - this will cause app.Run() to return
+		fyneApp.Quit()
+		systray.Quit()
+	}()
+
+	// Start system tray in its own goroutine
+	// This must run independently of Fyne to avoid blocking
+	go func() {
+		// systray.Run blocks, so this goroutine will handle all tray events
+		systray.Run(func() { onReady(bot) }, func() { onExit(bot) })
+	}()
+
+	// Give system tray a moment to initialize
+	time.Sleep(100 * time.Millisecond)
+
+	// Optional local control API
+	stopAPI := startAPI(bot)
+	defer stopAPI()
+	stopWebhooks := startWebhooks(bot)
+	defer stopWebhooks()
+
+	// Main loop: connect to LCU and monitor
+	bot.Start(context.Background())
+
+	if loadErrors != nil {
+		ui.ShowToast("LoL Kind Bot", "config.json has invalid settings - using their defaults until you save")
+		go openSettings(bot, loadErrors)
+	}
+
+	// Start Fyne event loop - MUST be called directly from main goroutine
+	// This blocks until app.Quit() is called (handled by signal handler above)
+	// All other operations run in goroutines, so this is fine
+	fyneApp.Run()
+
+	// If we get here, app was quit - cleanup already done by signal handler
+	log.Println("Application exited")
+}
+
+func onReady(bot *app.App) {
+	iconData := getIconData()
+	if len(iconData) > 0 {
+		systray.SetIcon(iconData)
+	}
+	// If icon data is empty, systray will use default icon - that's fine
+	systray.SetTitle("LoL Kind Bot")
+	systray.SetTooltip("LoL Kind Bot - Post-game positivity generator")
+
+	mToggle := systray.AddMenuItem("Toggle Listener", "Pause/Resume listening")
+	mSettings := systray.AddMenuItem("Open Settings", "Configure the bot")
+	addProfileMenu(bot)
+	addMuteMenu(bot)
+	systray.AddSeparator()
+	mQuit := systray.AddMenuItem("Exit", "Quit the application")
+
+	go func() {
+		for {
+			select {
+			case <-mToggle.ClickedCh:
+				listening := !bot.IsListening()
+				bot.SetListening(listening)
+				if listening {
+					mToggle.SetTitle("Pause Listener")
+					mToggle.SetTooltip("Pause listening for games")
+				} else {
+					mToggle.SetTitle("Resume Listener")
+					mToggle.SetTooltip("Resume listening for games")
+				}
+			case <-mSettings.ClickedCh:
+				// Keep console hidden - this is a background app
+				log.Println("Opening settings...")
+
+				// Run settings dialog in a goroutine to avoid blocking system tray
+				go openSettings(bot, nil)
+			case <-mQuit.ClickedCh:
+				systray.Quit()
+				return
+			}
+		}
+	}()
+}
+
+// openSettings shows the settings window and saves what the user saved. loadErrors, if set, are
+// the problems found in config.json, listed in the window until they're fixed.
+func openSettings(bot *app.App, loadErrors *config.ValidationError) {
+	// Create callback function to generate messages from last match
+	generateCallback := func() {
+		if err := bot.GenerateFromLastMatch(); err != nil {
+			log.Printf("Failed to generate messages from last match: %v", err)
+			if !bot.Config().EnableDebugLogging {
+				errorMsg := err.Error()
+				if len(errorMsg) > 80 {
+					errorMsg = errorMsg[:77] + "..."
+				}
+				ui.ShowToast("LoL Kind Bot", errorMsg)
+			}
+		} else if !bot.Config().EnableDebugLogging {
+			ui.ShowToast("LoL Kind Bot", "Messages generated successfully!")
+		}
+	}
+
+	// Show settings dialog - it will handle its own event loop
+	// Preview prompts with the last game when there is one
+	lastSummary, _ := bot.LastResult()
+	newCfg, ok := ui.ShowSettingsWindow(bot.Config(), ui.SettingsOptions{
+		OnGenerate:     generateCallback,
+		PreviewSummary: lastSummary,
+		LoadErrors:     loadErrors,
+	})
+	if ok && newCfg != nil {
+		if err := bot.UpdateConfig(newCfg); err != nil {
+			log.Printf("Failed to save settings: %v", err)
+		} else {
+			log.Printf("Settings saved successfully")
+		}
+	}
+
+	// Ensure console is hidden after dialog closes (unless debug mode)
+	if !debugMode {
+		ui.HideConsole()
+	}
+}
+
+// addProfileMenu adds a "Profile" submenu for switching profiles manually ("Auto" = pick by rules).
+// Profiles added while running appear after a restart.
+func addProfileMenu(bot *app.App) {
+	names := bot.Config().ProfileNames()
+	if len(names) == 0 {
+		return
+	}
+
+	mProfile := systray.AddMenuItem("Profile", "Choose the config profile")
+	items := map[string]*systray.MenuItem{
+		"": mProfile.AddSubMenuItemCheckbox("Auto", "Pick the profile from queue, game mode and party", false),
+	}
+	for _, name := range names {
+		items[name] = mProfile.AddSubMenuItemCheckbox(name, "Always use the "+name+" profile", false)
+	}
+
+	refresh := func() {
+		active := bot.Config().ActiveProfile
+		for name, item := range items {
+			if name == active {
+				item.Check()
+			} else {
+				item.Uncheck()
+			}
+		}
+	}
+	refresh()
+
+	// Keep the checkmarks in sync with edits to config.json
+	go func() {
+		events, _ := bot.Subscribe()
+		for event := range events {
+			if event.Type == app.EventConfigChanged {
+				refresh()
+			}
+		}
+	}()
+
+	for name, item := range items {
+		go func(name string, item *systray.MenuItem) {
+			for range item.ClickedCh {
+				if err := bot.SetActiveProfile(name); err != nil {
+					log.Printf("Failed to switch profile: %v", err)
+				}
+				refresh()
+			}
+		}(name, item)
+	}
+}
+
+// addMuteMenu adds a "Mute Announcements" checkbox and registers the mute hotkey (tts.muteHotkey),
+// re-registering it when the config changes
+func addMuteMenu(bot *app.App) {
+	mMute := systray.AddMenuItemCheckbox("Mute Announcements", "Stop speaking in-game announcements", bot.Muted())
+	refresh := func() {
+		if bot.Muted() {
+			mMute.Check()
+		} else {
+			mMute.Uncheck()
+		}
+	}
+
+	var unregister func()
+	registered := ""
+	register := func(spec string) {
+		if spec == registered {
+			return
+		}
+		if unregister != nil {
+			unregister()
+			unregister = nil
+		}
+		registered = spec
+		if spec == "" {
+			return
+		}
+		hotkey, err := config.ParseHotkey(spec)
+		if err == nil {
+			unregister, err = ui.RegisterHotkey(hotkey, func() { bot.ToggleMute() })
+		}
+		if err != nil {
+			log.Printf("Failed to register mute hotkey %q: %v", spec, err)
+			return
+		}
+		log.Printf("Mute hotkey: %s", spec)
+	}
+	register(bot.Config().TTS.MuteHotkey)
+
+	go func() {
+		events, _ := bot.Subscribe()
+		for event := range events {
+			switch event.Type {
+			case app.EventMuted:
+				refresh()
+			case app.EventConfigChanged:
+				register(bot.Config().TTS.MuteHotkey)
+			}
+		}
+	}()
+
+	go func() {
+		for range mMute.ClickedCh {
+			bot.ToggleMute()
+		}
+	}()
+}
+
+func onExit(bot *app.App) {
+	log.Println("Exiting...")
+	bot.Stop()
+	os.Exit(0)
+}
+
+func getIconData() []byte {
+	// Placeholder icon data - in production, embed a proper icon
+	// For now, return empty data (will use default system icon)
+	return []byte{}
+}
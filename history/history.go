@@ -0,0 +1,240 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/llm"
+	"lol-kind-bot/monitor"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName is the history file kept next to config.json
+const FileName = "history.jsonl"
+
+// Entry is one processed game with the messages generated for it
+type Entry struct {
+	GameID      string                `json:"gameId"`
+	Time        time.Time             `json:"time"`
+	Summary     *analyzer.GameSummary `json:"summary"`
+	Messages    []string              `json:"messages"`
+	Suggestions []llm.Suggestion      `json:"suggestions,omitempty"` // Messages with the reasoning behind them
+	Pinned      []string              `json:"pinned,omitempty"`      // Messages we pinned as favourites
+	Choices     []Choice              `json:"choices,omitempty"`     // What we did with the messages, oldest first
+	Timeline    *monitor.Timeline     `json:"timeline,omitempty"`    // Live recording of the game, if it was recorded
+	Backfilled  bool                  `json:"backfilled,omitempty"`  // Added from match history after a restart, without messages
+}
+
+// Choice actions recorded from the messages dialog
+const (
+	ChoiceCopied       = "copied"
+	ChoiceEdited       = "edited"
+	ChoicePinned       = "pinned"
+	ChoiceUnpinned     = "unpinned"
+	ChoiceRegenerated  = "regenerated"
+	ChoiceMoreLikeThis = "moreLikeThis"
+	ChoiceThumbsUp     = "thumbsUp"
+	ChoiceThumbsDown   = "thumbsDown"
+	ChoiceSent         = "sent"      // Found in our post-game chat
+	ChoiceDismissed    = "dismissed" // The window closed without us using the message
+)
+
+// Choice is one thing we did with a suggested message
+type Choice struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"`
+	Text     string    `json:"text"`               // The message acted on (after the action, for edits and regenerations)
+	Original string    `json:"original,omitempty"` // The message before an edit or regeneration, or the one "more like this" was based on
+	Tone     string    `json:"tone,omitempty"`     // Tone requested for a regeneration
+}
+
+// Update is a change to a game's messages, appended after its entry so the file is never rewritten
+type Update struct {
+	GameID      string           `json:"gameId"`
+	Time        time.Time        `json:"time"`
+	Suggestions []llm.Suggestion `json:"suggestions"` // The game's messages now
+	Pinned      []string         `json:"pinned"`
+	Choice      *Choice          `json:"choice,omitempty"` // What we did, if this update was a choice
+}
+
+// record is one line of the history file: an entry, or an update to an earlier one
+type record struct {
+	Entry
+	Update *Update `json:"update,omitempty"`
+}
+
+// Query filters history entries. Zero values match everything.
+type Query struct {
+	Limit    int       // Maximum number of entries (newest first)
+	Champion string    // Only games where we played this champion (case-insensitive)
+	Since    time.Time // Only games processed at or after this time
+	Win      *bool     // Only wins (true) or losses (false)
+	Profile  string    // Only games played with this config profile
+}
+
+// Store appends game entries to a JSON Lines file
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a store backed by path. The file is created on the first Add.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// PathFor returns the history file path for a given config path
+func PathFor(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), FileName)
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Add appends an entry to the history file
+func (s *Store) Add(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	return s.append(entry)
+}
+
+// AddUpdate appends a change to a game's messages. It applies to the newest entry for the
+// game when the history is read, and is ignored if the game isn't in the history.
+func (s *Store) AddUpdate(update Update) error {
+	if update.Time.IsZero() {
+		update.Time = time.Now()
+	}
+	return s.append(struct {
+		Update *Update `json:"update"`
+	}{&update})
+}
+
+// append writes a line to the end of the history file
+func (s *Store) append(v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write history entry: %w", err)
+	}
+	return nil
+}
+
+// Query returns matching entries, newest first
+func (s *Store) Query(q Query) ([]Entry, error) {
+	entries, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Entry, 0)
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if !q.matches(entry) {
+			continue
+		}
+		results = append(results, entry)
+		if q.Limit > 0 && len(results) >= q.Limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// readAll reads every entry in file order with its updates applied, skipping lines that fail to parse
+func (s *Store) readAll() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readAllLocked()
+}
+
+func (s *Store) readAllLocked() ([]Entry, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	newest := make(map[string]int) // Game ID -> index of its newest entry so far
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // Summaries can be large
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue // Skip a truncated line from a crash rather than losing the whole history
+		}
+		if rec.Update != nil {
+			if i, ok := newest[rec.Update.GameID]; ok {
+				rec.Update.apply(&entries[i])
+			}
+			continue
+		}
+		newest[rec.GameID] = len(entries)
+		entries = append(entries, rec.Entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	return entries, nil
+}
+
+// apply brings an entry's messages up to date
+func (u *Update) apply(entry *Entry) {
+	entry.Suggestions = u.Suggestions
+	entry.Messages = llm.SuggestionTexts(u.Suggestions)
+	entry.Pinned = u.Pinned
+	if u.Choice != nil {
+		entry.Choices = append(entry.Choices, *u.Choice)
+	}
+}
+
+func (q Query) matches(entry Entry) bool {
+	if !q.Since.IsZero() && entry.Time.Before(q.Since) {
+		return false
+	}
+	if entry.Summary == nil {
+		return q.Champion == "" && q.Win == nil && q.Profile == ""
+	}
+	if q.Profile != "" && !strings.EqualFold(entry.Summary.Profile, q.Profile) {
+		return false
+	}
+	if q.Win != nil && entry.Summary.MyTeamWon() != *q.Win {
+		return false
+	}
+	if q.Champion != "" {
+		me := entry.Summary.Me()
+		if me == nil || !strings.EqualFold(me.Champion, q.Champion) {
+			return false
+		}
+	}
+	return true
+}
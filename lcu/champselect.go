@@ -0,0 +1,112 @@
+package lcu
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ChampSelectPlayer is one player on a team in champion select
+type ChampSelectPlayer struct {
+	CellID             int64  `json:"cellId"`
+	ChampionID         int    `json:"championId"`         // 0 until the pick is locked in
+	ChampionPickIntent int    `json:"championPickIntent"` // The champion being hovered
+	AssignedPosition   string `json:"assignedPosition"`   // "top", "jungle", "middle", "bottom", "utility"; empty in blind pick
+	SummonerID         int64  `json:"summonerId"`
+	Puuid              string `json:"puuid"`    // Empty when the queue hides names
+	GameName           string `json:"gameName"` // Riot ID name, empty when the queue hides names
+	TagLine            string `json:"tagLine"`
+}
+
+// Champion returns the champion the player picked, or is hovering if they haven't locked in (0 if neither)
+func (p ChampSelectPlayer) Champion() int {
+	if p.ChampionID > 0 {
+		return p.ChampionID
+	}
+	if p.ChampionPickIntent > 0 {
+		return p.ChampionPickIntent
+	}
+	return 0
+}
+
+// ChampSelectSession is the subset of /lol-champ-select/v1/session the bot uses
+type ChampSelectSession struct {
+	LocalPlayerCellID int64               `json:"localPlayerCellId"`
+	MyTeam            []ChampSelectPlayer `json:"myTeam"`
+}
+
+// Me returns our own player, or nil if it isn't on the team (e.g. spectating)
+func (s *ChampSelectSession) Me() *ChampSelectPlayer {
+	for i := range s.MyTeam {
+		if s.MyTeam[i].CellID == s.LocalPlayerCellID {
+			return &s.MyTeam[i]
+		}
+	}
+	return nil
+}
+
+// Key sums up the team's picks and positions, so changes can be spotted cheaply
+func (s *ChampSelectSession) Key() string {
+	parts := make([]string, 0, len(s.MyTeam))
+	for _, p := range s.MyTeam {
+		parts = append(parts, fmt.Sprintf("%d:%d:%s:%s", p.CellID, p.Champion(), p.AssignedPosition, p.Puuid))
+	}
+	return strings.Join(parts, ",")
+}
+
+// GetChampSelectSession retrieves the current champion select session
+func (c *Client) GetChampSelectSession() (*ChampSelectSession, error) {
+	data, err := c.Get("/lol-champ-select/v1/session")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get champ select session: %w", err)
+	}
+
+	var session ChampSelectSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse champ select session: %w", err)
+	}
+	return &session, nil
+}
+
+// GetChampionNames retrieves every champion's display name by ID
+func (c *Client) GetChampionNames() (map[int]string, error) {
+	data, err := c.Get("/lol-game-data/assets/v1/champion-summary.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get champion summary: %w", err)
+	}
+
+	var champions []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &champions); err != nil {
+		return nil, fmt.Errorf("failed to parse champion summary: %w", err)
+	}
+
+	names := make(map[int]string, len(champions))
+	for _, champion := range champions {
+		if champion.ID > 0 {
+			names[champion.ID] = champion.Name
+		}
+	}
+	return names, nil
+}
+
+// GetRecentResults retrieves whether a player won each of their last count games, newest first
+func (c *Client) GetRecentResults(puuid string, count int) ([]bool, error) {
+	games, err := c.GetMatchHistory(puuid, 0, count)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []bool
+	for i := range games {
+		if participant := games[i].Participant(puuid); participant != nil {
+			results = append(results, participant.Stats.Win)
+		}
+		if len(results) == count {
+			break
+		}
+	}
+	return results, nil
+}
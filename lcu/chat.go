@@ -0,0 +1,161 @@
+package lcu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// ChatMe is our own chat identity (/lol-chat/v1/me)
+type ChatMe struct {
+	ID         string `json:"id"` // e.g. "<puuid>@eu1.pvp.net"
+	PID        string `json:"pid"`
+	SummonerID int64  `json:"summonerId"`
+	Name       string `json:"name"`
+	GameName   string `json:"gameName"`
+}
+
+// ChatConversation is a chat room such as the post-game lobby
+type ChatConversation struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // "postGame", "championSelect", "customGame", "chat", ...
+	Name string `json:"name"`
+}
+
+// ChatMessage is one message in a conversation
+type ChatMessage struct {
+	ID             string `json:"id"`
+	Body           string `json:"body"`
+	FromID         string `json:"fromId"`
+	FromPID        string `json:"fromPid"`
+	FromSummonerID int64  `json:"fromSummonerId"`
+	Type           string `json:"type"`      // "groupchat", "chat", "system", ...
+	Timestamp      string `json:"timestamp"` // RFC 3339
+}
+
+// ChatParticipant is a member of a conversation
+type ChatParticipant struct {
+	ID         string `json:"id"`
+	PID        string `json:"pid"`
+	Puuid      string `json:"puuid"`
+	SummonerID int64  `json:"summonerId"`
+	Name       string `json:"name"`
+	GameName   string `json:"gameName"`
+	GameTag    string `json:"gameTag"`
+}
+
+// Sent reports whether the participant sent a message
+func (p ChatParticipant) Sent(m ChatMessage) bool {
+	return (m.FromID != "" && m.FromID == p.ID) ||
+		(m.FromPID != "" && m.FromPID == p.PID) ||
+		(m.FromSummonerID != 0 && m.FromSummonerID == p.SummonerID)
+}
+
+// IsFrom reports whether the message was sent by the given chat identity
+func (m ChatMessage) IsFrom(me *ChatMe) bool {
+	if me == nil {
+		return false
+	}
+	return (m.FromID != "" && m.FromID == me.ID) ||
+		(m.FromPID != "" && m.FromPID == me.PID) ||
+		(m.FromSummonerID != 0 && m.FromSummonerID == me.SummonerID)
+}
+
+// GetChatMe retrieves our own chat identity
+func (c *Client) GetChatMe() (*ChatMe, error) {
+	data, err := c.Get("/lol-chat/v1/me")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat identity: %w", err)
+	}
+
+	var me ChatMe
+	if err := json.Unmarshal(data, &me); err != nil {
+		return nil, fmt.Errorf("failed to parse chat identity: %w", err)
+	}
+	return &me, nil
+}
+
+// GetConversations retrieves the open chat conversations
+func (c *Client) GetConversations() ([]ChatConversation, error) {
+	data, err := c.Get("/lol-chat/v1/conversations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat conversations: %w", err)
+	}
+
+	var conversations []ChatConversation
+	if err := json.Unmarshal(data, &conversations); err != nil {
+		return nil, fmt.Errorf("failed to parse chat conversations: %w", err)
+	}
+	return conversations, nil
+}
+
+// GetConversationMessages retrieves the messages in a conversation, oldest first
+func (c *Client) GetConversationMessages(conversationID string) ([]ChatMessage, error) {
+	data, err := c.Get("/lol-chat/v1/conversations/" + url.PathEscape(conversationID) + "/messages")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat messages: %w", err)
+	}
+
+	var messages []ChatMessage
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse chat messages: %w", err)
+	}
+	return messages, nil
+}
+
+// GetConversationParticipants retrieves the members of a conversation
+func (c *Client) GetConversationParticipants(conversationID string) ([]ChatParticipant, error) {
+	data, err := c.Get("/lol-chat/v1/conversations/" + url.PathEscape(conversationID) + "/participants")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat participants: %w", err)
+	}
+
+	var participants []ChatParticipant
+	if err := json.Unmarshal(data, &participants); err != nil {
+		return nil, fmt.Errorf("failed to parse chat participants: %w", err)
+	}
+	return participants, nil
+}
+
+// SendMessage posts a message to a conversation
+func (c *Client) SendMessage(conversationID, body string) error {
+	_, err := c.Post("/lol-chat/v1/conversations/"+url.PathEscape(conversationID)+"/messages", map[string]string{"body": body})
+	if err != nil {
+		return fmt.Errorf("failed to send chat message: %w", err)
+	}
+	return nil
+}
+
+// GetPostGameConversation returns the post-game lobby chat (nil if there is none)
+func (c *Client) GetPostGameConversation() (*ChatConversation, error) {
+	return c.conversationOfType("postGame")
+}
+
+// GetChampSelectConversation returns the champion select team chat (nil if there is none)
+func (c *Client) GetChampSelectConversation() (*ChatConversation, error) {
+	return c.conversationOfType("championSelect")
+}
+
+// conversationOfType returns the first open conversation of a type (nil if there is none)
+func (c *Client) conversationOfType(conversationType string) (*ChatConversation, error) {
+	conversations, err := c.GetConversations()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range conversations {
+		if conversations[i].Type == conversationType {
+			return &conversations[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetPostGameMessages retrieves the messages in the post-game lobby chat (nil if there is none)
+func (c *Client) GetPostGameMessages() ([]ChatMessage, error) {
+	conversation, err := c.GetPostGameConversation()
+	if err != nil || conversation == nil {
+		return nil, err
+	}
+	return c.GetConversationMessages(conversation.ID)
+}
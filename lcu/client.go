@@ -1,8 +1,10 @@
 package lcu
 
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -27,6 +29,7 @@ type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	AuthHeader string
+	Recorder   *Recorder // Optional: records every request/response for later replay
 }
 
 func GetLockfilePath() string {
@@ -81,13 +84,34 @@ func NewClient(lockfileInfo *LockfileInfo) (*Client, error) {
 }
 
 func (c *Client) Get(endpoint string) ([]byte, error) {
-	url := c.BaseURL + endpoint
-	req, err := http.NewRequest("GET", url, nil)
+	status, body, err := c.get(endpoint)
+	if c.Recorder != nil {
+		c.Recorder.Record(endpoint, status, body, err)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", status, string(body))
+	}
+
+	return body, nil
+}
+
+// Post sends body as JSON to endpoint and returns the response body
+func (c *Client) Post(endpoint string, body interface{}) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	req, err := http.NewRequest("POST", c.BaseURL+endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 	req.Header.Set("Authorization", c.AuthHeader)
+	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
@@ -95,16 +119,37 @@ func (c *Client) Get(endpoint string) ([]byte, error) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
 	}
 
+	return respBody, nil
+}
+
+// get performs the raw request and returns the status code and body
+func (c *Client) get(endpoint string) (int, []byte, error) {
+	url := c.BaseURL + endpoint
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", c.AuthHeader)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return resp.StatusCode, nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return body, nil
+	return resp.StatusCode, body, nil
 }
-
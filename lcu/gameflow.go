@@ -0,0 +1,69 @@
+package lcu
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GameflowSession is the subset of /lol-gameflow/v1/session the bot uses
+type GameflowSession struct {
+	Phase    string `json:"phase"`
+	GameData struct {
+		GameID int64 `json:"gameId"`
+		Queue  struct {
+			ID       int    `json:"id"`
+			Type     string `json:"type"`     // e.g. "RANKED_SOLO_5x5", "ARAM"
+			GameMode string `json:"gameMode"` // e.g. "CLASSIC", "ARAM"
+		} `json:"queue"`
+	} `json:"gameData"`
+	Map struct {
+		GameMode string `json:"gameMode"`
+	} `json:"map"`
+}
+
+// LobbyMember is a player in our lobby (our party)
+type LobbyMember struct {
+	SummonerName string `json:"summonerName"`
+	GameName     string `json:"gameName"` // Riot ID name, used when summonerName is empty
+	IsLeader     bool   `json:"isLeader"`
+}
+
+// Name returns the member's display name
+func (m LobbyMember) Name() string {
+	if m.SummonerName != "" {
+		return m.SummonerName
+	}
+	return m.GameName
+}
+
+// GetGameflowSession retrieves the current gameflow session (queue and game mode of the current game)
+func (c *Client) GetGameflowSession() (*GameflowSession, error) {
+	data, err := c.Get("/lol-gameflow/v1/session")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gameflow session: %w", err)
+	}
+
+	var session GameflowSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse gameflow session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// GetLobbyMembers retrieves the players in our current lobby
+func (c *Client) GetLobbyMembers() ([]LobbyMember, error) {
+	data, err := c.Get("/lol-lobby/v2/lobby")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lobby: %w", err)
+	}
+
+	var lobby struct {
+		Members []LobbyMember `json:"members"`
+	}
+	if err := json.Unmarshal(data, &lobby); err != nil {
+		return nil, fmt.Errorf("failed to parse lobby: %w", err)
+	}
+
+	return lobby.Members, nil
+}
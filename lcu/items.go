@@ -0,0 +1,119 @@
+package lcu
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// LiveItem is an item in a player's inventory in the live game
+type LiveItem struct {
+	ItemID      int    `json:"itemID"`
+	DisplayName string `json:"displayName"`
+	Count       int    `json:"count"`
+	Price       int    `json:"price"` // Full cost of one
+	Slot        int    `json:"slot"`
+	Consumable  bool   `json:"consumable"`
+}
+
+// Item is an item from the client's game data, with its recipe
+type Item struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Price      int      `json:"price"`      // Combine cost on top of the components
+	PriceTotal int      `json:"priceTotal"` // Full cost with all components
+	From       []int    `json:"from"`       // Components
+	To         []int    `json:"to"`         // Items this builds into
+	Categories []string `json:"categories"` // e.g. "Consumable", "Trinket", "Boots"
+	InStore    bool     `json:"inStore"`
+}
+
+// Consumable reports whether an item is used up or a trinket, rather than part of a build
+func (i Item) Consumable() bool {
+	for _, category := range i.Categories {
+		if category == "Consumable" || category == "Trinket" {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemSetItem is one item in an item set block
+type ItemSetItem struct {
+	ID    string `json:"id"` // Item ID as a string
+	Count int    `json:"count"`
+}
+
+// ItemSet is an item set from the client's item set editor
+type ItemSet struct {
+	Title               string `json:"title"`
+	AssociatedChampions []int  `json:"associatedChampions"` // Empty = any champion
+	AssociatedMaps      []int  `json:"associatedMaps"`      // Empty = any map
+	Blocks              []struct {
+		Type  string        `json:"type"` // Block title, e.g. "Starting Items"
+		Items []ItemSetItem `json:"items"`
+	} `json:"blocks"`
+}
+
+// ItemIDs returns the set's item IDs in order, skipping any that aren't numbers
+func (s ItemSet) ItemIDs() []int {
+	var ids []int
+	for _, block := range s.Blocks {
+		for _, item := range block.Items {
+			if id, err := strconv.Atoi(item.ID); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// For reports whether the set applies to a champion on a map
+func (s ItemSet) For(championID, mapID int) bool {
+	return (len(s.AssociatedChampions) == 0 || containsInt(s.AssociatedChampions, championID)) &&
+		(len(s.AssociatedMaps) == 0 || containsInt(s.AssociatedMaps, mapID))
+}
+
+// GetItems retrieves every item from the client's game data, by ID
+func (c *Client) GetItems() (map[int]Item, error) {
+	data, err := c.Get("/lol-game-data/assets/v1/items.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse items: %w", err)
+	}
+
+	byID := make(map[int]Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	return byID, nil
+}
+
+// GetItemSets retrieves the item sets a summoner made in the client
+func (c *Client) GetItemSets(summonerID int64) ([]ItemSet, error) {
+	data, err := c.Get(fmt.Sprintf("/lol-item-sets/v1/item-sets/%d/sets", summonerID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item sets: %w", err)
+	}
+
+	var sets struct {
+		ItemSets []ItemSet `json:"itemSets"`
+	}
+	if err := json.Unmarshal(data, &sets); err != nil {
+		return nil, fmt.Errorf("failed to parse item sets: %w", err)
+	}
+	return sets.ItemSets, nil
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package lcu
+
+import "strings"
+
+// Live game event names used by the objective timers and the timeline recorder
+const (
+	LiveEventChampionKill   = "ChampionKill"
+	LiveEventAce            = "Ace"
+	LiveEventTurretKilled   = "TurretKilled"
+	LiveEventDragonKill     = "DragonKill"
+	LiveEventHeraldKill     = "HeraldKill"
+	LiveEventBaronKill      = "BaronKill"
+	LiveEventInhibKilled    = "InhibKilled"
+	LiveEventInhibRespawned = "InhibRespawned"
+)
+
+// LiveEvent is one event of the live game (/liveclientdata/eventdata, also part of allgamedata)
+type LiveEvent struct {
+	EventID        int      `json:"EventID"`
+	EventName      string   `json:"EventName"`
+	EventTime      float64  `json:"EventTime"` // Seconds of game time
+	KillerName     string   `json:"KillerName"`
+	VictimName     string   `json:"VictimName"`
+	Assisters      []string `json:"Assisters"`
+	AcingTeam      string   `json:"AcingTeam"`    // "ORDER" or "CHAOS"
+	DragonType     string   `json:"DragonType"`   // "Fire", "Earth", "Water", "Air", "Hextech", "Chemtech" or "Elder"
+	Stolen         string   `json:"Stolen"`       // "True" or "False"
+	TurretKilled   string   `json:"TurretKilled"` // e.g. "Turret_T2_L_03_A"
+	InhibKilled    string   `json:"InhibKilled"`  // e.g. "Barracks_T2_L1"
+	InhibRespawned string   `json:"InhibRespawned"`
+}
+
+// PlayerTeam returns the team ("ORDER" or "CHAOS") of the player with the given name, matching
+// Riot IDs with or without the tag, or "" if nobody has it (e.g. a minion or turret)
+func (d *AllGameData) PlayerTeam(name string) string {
+	if p := d.Player(name); p != nil {
+		return p.Team
+	}
+	return ""
+}
+
+// Player returns the player with the given name, matching Riot IDs with or without the tag
+// (nil if nobody has it, e.g. a minion or turret)
+func (d *AllGameData) Player(name string) *PlayerData {
+	if name == "" {
+		return nil
+	}
+	for i := range d.AllPlayers {
+		p := &d.AllPlayers[i]
+		if strings.EqualFold(p.RiotID, name) || strings.EqualFold(p.RiotIDGameName, name) || strings.EqualFold(p.SummonerName, name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Me returns the active player's entry in AllPlayers, or nil if it can't be found
+func (d *AllGameData) Me() *PlayerData {
+	if me := d.Player(d.ActivePlayer.RiotID); me != nil {
+		return me
+	}
+	return d.Player(d.ActivePlayer.SummonerName)
+}
+
+// MyTeam returns the active player's team ("ORDER" or "CHAOS"), or "" if it can't be found
+func (d *AllGameData) MyTeam() string {
+	if me := d.Me(); me != nil {
+		return me.Team
+	}
+	return ""
+}
+
+// InhibitorTeam returns the team owning an inhibitor ("Barracks_T1_L1" -> "ORDER") and its lane
+// ("top", "mid" or "bot"); both are empty for unknown names
+func InhibitorTeam(name string) (team, lane string) {
+	if team = StructureTeam(name); team == "" {
+		return "", ""
+	}
+	switch {
+	case strings.HasSuffix(name, "_L1"):
+		lane = "top"
+	case strings.HasSuffix(name, "_C1"):
+		lane = "mid"
+	case strings.HasSuffix(name, "_R1"):
+		lane = "bot"
+	}
+	return team, lane
+}
+
+// StructureTeam returns the team owning a turret or inhibitor ("Turret_T2_L_03_A" -> "CHAOS"),
+// or "" for unknown names
+func StructureTeam(name string) string {
+	switch {
+	case strings.Contains(name, "_T1_"):
+		return "ORDER"
+	case strings.Contains(name, "_T2_"):
+		return "CHAOS"
+	}
+	return ""
+}
@@ -10,17 +10,32 @@ type ActivePlayerData struct {
 	CurrentGold float64 `json:"currentGold"`
 	Level       int     `json:"level"`
 	ChampionName string `json:"championName"`
+	SummonerName string `json:"summonerName"`
+	RiotID       string `json:"riotId"` // "Name#TAG"
 }
 
 // PlayerData represents a player's live game data
 type PlayerData struct {
 	ChampionName string  `json:"championName"`
 	SummonerName string  `json:"summonerName"`
-	Team         string  `json:"team"`
+	RiotID       string  `json:"riotId"`         // "Name#TAG"
+	RiotIDGameName string `json:"riotIdGameName"` // "Name"
+	Team         string  `json:"team"` // "ORDER" (blue) or "CHAOS" (red)
 	CurrentHealth float64 `json:"currentHealth"`
 	MaxHealth     float64 `json:"maxHealth"`
 	Level         int     `json:"level"`
 	Gold          float64 `json:"gold"`
+	Items         []LiveItem `json:"items"`
+	Scores        PlayerScores `json:"scores"`
+}
+
+// PlayerScores is a player's scoreboard line in the live game
+type PlayerScores struct {
+	Kills      int     `json:"kills"`
+	Deaths     int     `json:"deaths"`
+	Assists    int     `json:"assists"`
+	CreepScore int     `json:"creepScore"`
+	WardScore  float64 `json:"wardScore"`
 }
 
 // AllGameData represents all live game data
@@ -28,8 +43,12 @@ type AllGameData struct {
 	ActivePlayer ActivePlayerData `json:"activePlayer"`
 	AllPlayers   []PlayerData    `json:"allPlayers"`
 	GameData     struct {
-		GameTime float64 `json:"gameTime"`
+		GameTime  float64 `json:"gameTime"`
+		MapNumber int     `json:"mapNumber"` // 11 = Summoner's Rift, 12 = Howling Abyss
 	} `json:"gameData"`
+	Events struct {
+		Events []LiveEvent `json:"Events"`
+	} `json:"events"`
 }
 
 // GetActivePlayerData retrieves the current player's live game data
@@ -0,0 +1,28 @@
+package lcu
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RegionLocale is the client's region and display language (/riotclient/region-locale)
+type RegionLocale struct {
+	Locale      string `json:"locale"` // e.g. "es_ES", "pt_BR", "ko_KR"
+	Region      string `json:"region"` // e.g. "EUW", "BR", "KR"
+	WebLanguage string `json:"webLanguage"`
+	WebRegion   string `json:"webRegion"`
+}
+
+// GetRegionLocale retrieves the client's region and locale
+func (c *Client) GetRegionLocale() (*RegionLocale, error) {
+	data, err := c.Get("/riotclient/region-locale")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get region locale: %w", err)
+	}
+
+	var locale RegionLocale
+	if err := json.Unmarshal(data, &locale); err != nil {
+		return nil, fmt.Errorf("failed to parse region locale: %w", err)
+	}
+	return &locale, nil
+}
@@ -2,73 +2,291 @@ package lcu
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
 	"time"
 )
 
 type MatchHistoryGame struct {
-	GameID            int64  `json:"gameId"`
-	GameCreation      int64  `json:"gameCreation"`
-	GameDuration      int    `json:"gameDuration"`
-	GameEndTimestamp  int64  `json:"gameEndTimestamp"`
-	GameMode          string `json:"gameMode"`
-	GameType          string `json:"gameType"`
-	GameVersion       string `json:"gameVersion"`
-	MapID             int    `json:"mapId"`
-	PlatformID        string `json:"platformId"`
-	QueueID           int    `json:"queueId"`
-	SeasonID          int    `json:"seasonId"`
+	GameID           int64  `json:"gameId"`
+	GameCreation     int64  `json:"gameCreation"`
+	GameDuration     int    `json:"gameDuration"`
+	GameEndTimestamp int64  `json:"gameEndTimestamp"`
+	GameMode         string `json:"gameMode"`
+	GameType         string `json:"gameType"`
+	GameVersion      string `json:"gameVersion"`
+	MapID            int    `json:"mapId"`
+	PlatformID       string `json:"platformId"`
+	QueueID          int    `json:"queueId"`
+	SeasonID         int    `json:"seasonId"`
+
+	// Match lists only include the player whose history it is; GetGame has all ten
+	Participants          []MatchParticipant         `json:"participants,omitempty"`
+	ParticipantIdentities []MatchParticipantIdentity `json:"participantIdentities,omitempty"`
+	Teams                 []MatchTeam                `json:"teams,omitempty"`
 }
 
-type MatchHistory struct {
-	Games []MatchHistoryGame `json:"games"`
+// MatchParticipant is one player's champion and final stats in a finished game
+type MatchParticipant struct {
+	ParticipantID int                      `json:"participantId"`
+	TeamID        int                      `json:"teamId"` // 100 (blue) or 200 (red)
+	ChampionID    int                      `json:"championId"`
+	Spell1ID      int                      `json:"spell1Id"`
+	Spell2ID      int                      `json:"spell2Id"`
+	Stats         MatchParticipantStats    `json:"stats"`
+	Timeline      MatchParticipantTimeline `json:"timeline"`
 }
 
-// GetRecentMatchHistory gets the most recent match from match history
-// Returns nil, nil if no recent match is found (not an error)
-func (c *Client) GetRecentMatchHistory() (*MatchHistoryGame, error) {
-	// Try multiple possible match history endpoints
-	endpoints := []string{
-		"/lol-match-history/v1/matchlist",
-		"/lol-match-history/v1/products/lol/current-summoner/matches",
+// MatchParticipantStats is a participant's end-of-game stats
+type MatchParticipantStats struct {
+	Win                         bool `json:"win"`
+	Kills                       int  `json:"kills"`
+	Deaths                      int  `json:"deaths"`
+	Assists                     int  `json:"assists"`
+	ChampLevel                  int  `json:"champLevel"`
+	GoldEarned                  int  `json:"goldEarned"`
+	TotalMinionsKilled          int  `json:"totalMinionsKilled"`
+	NeutralMinionsKilled        int  `json:"neutralMinionsKilled"`
+	TotalDamageDealtToChampions int  `json:"totalDamageDealtToChampions"`
+	TotalDamageTaken            int  `json:"totalDamageTaken"`
+	DamageSelfMitigated         int  `json:"damageSelfMitigated"`
+	TotalHeal                   int  `json:"totalHeal"`
+	TimeCCingOthers             int  `json:"timeCCingOthers"`
+	VisionScore                 int  `json:"visionScore"`
+	WardsPlaced                 int  `json:"wardsPlaced"`
+	WardsKilled                 int  `json:"wardsKilled"`
+	TurretKills                 int  `json:"turretKills"`
+	InhibitorKills              int  `json:"inhibitorKills"`
+	LargestMultiKill            int  `json:"largestMultiKill"`
+	FirstBloodKill              bool `json:"firstBloodKill"`
+	Item0                       int  `json:"item0"`
+	Item1                       int  `json:"item1"`
+	Item2                       int  `json:"item2"`
+	Item3                       int  `json:"item3"`
+	Item4                       int  `json:"item4"`
+	Item5                       int  `json:"item5"`
+	Item6                       int  `json:"item6"` // Trinket
+}
+
+// MatchParticipantTimeline is where a participant played
+type MatchParticipantTimeline struct {
+	Lane string `json:"lane"` // "TOP", "JUNGLE", "MIDDLE", "BOTTOM" or "NONE"
+	Role string `json:"role"` // "SOLO", "DUO_CARRY", "DUO_SUPPORT" or "NONE"
+}
+
+// MatchParticipantIdentity ties a participant to a player
+type MatchParticipantIdentity struct {
+	ParticipantID int         `json:"participantId"`
+	Player        MatchPlayer `json:"player"`
+}
+
+// MatchPlayer is the account behind a participant
+type MatchPlayer struct {
+	Puuid        string `json:"puuid"`
+	SummonerID   int64  `json:"summonerId"`
+	SummonerName string `json:"summonerName"`
+	GameName     string `json:"gameName"`
+	TagLine      string `json:"tagLine"`
+}
+
+// MatchTeam is one team's result and objectives in a finished game
+type MatchTeam struct {
+	TeamID          int        `json:"teamId"`
+	Win             string     `json:"win"` // "Win" or "Fail"
+	FirstBlood      bool       `json:"firstBlood"`
+	FirstTower      bool       `json:"firstTower"`
+	FirstInhibitor  bool       `json:"firstInhibitor"`
+	FirstBaron      bool       `json:"firstBaron"`
+	FirstDragon     bool       `json:"firstDargon"` // Sic, the client's spelling
+	TowerKills      int        `json:"towerKills"`
+	InhibitorKills  int        `json:"inhibitorKills"`
+	BaronKills      int        `json:"baronKills"`
+	DragonKills     int        `json:"dragonKills"`
+	RiftHeraldKills int        `json:"riftHeraldKills"`
+	Bans            []MatchBan `json:"bans"`
+}
+
+// MatchBan is a champion banned by a team
+type MatchBan struct {
+	ChampionID int `json:"championId"`
+	PickTurn   int `json:"pickTurn"`
+}
+
+// Won reports whether the team won
+func (t *MatchTeam) Won() bool {
+	return t.Win == "Win"
+}
+
+// Name returns the player's Riot ID ("Name#TAG"), or their summoner name for older games
+func (p *MatchPlayer) Name() string {
+	if p.GameName == "" {
+		return p.SummonerName
+	}
+	if p.TagLine == "" {
+		return p.GameName
 	}
+	return p.GameName + "#" + p.TagLine
+}
 
-	for _, endpoint := range endpoints {
-		data, err := c.Get(endpoint)
-		if err != nil {
-			continue // Try next endpoint
-		}
+// EndTimestamp returns when the game ended (Unix milliseconds), worked out from its start
+// and length when the client doesn't say
+func (g *MatchHistoryGame) EndTimestamp() int64 {
+	if g.GameEndTimestamp != 0 || g.GameCreation == 0 {
+		return g.GameEndTimestamp
+	}
+	return g.GameCreation + int64(g.GameDuration)*1000
+}
 
-		// Try parsing as MatchHistory struct
-		var history MatchHistory
-		if err := json.Unmarshal(data, &history); err == nil {
-			if len(history.Games) > 0 {
-				return &history.Games[0], nil
-			}
+// Team returns a team's result and objectives (nil if the game has no team details)
+func (g *MatchHistoryGame) Team(teamID int) *MatchTeam {
+	for i := range g.Teams {
+		if g.Teams[i].TeamID == teamID {
+			return &g.Teams[i]
 		}
+	}
+	return nil
+}
 
-		// Try parsing as direct array of games
-		var games []MatchHistoryGame
-		if err := json.Unmarshal(data, &games); err == nil {
-			if len(games) > 0 {
-				return &games[0], nil
-			}
+// Identity returns the player behind a participant (nil if unknown)
+func (g *MatchHistoryGame) Identity(participantID int) *MatchPlayer {
+	for i := range g.ParticipantIdentities {
+		if g.ParticipantIdentities[i].ParticipantID == participantID {
+			return &g.ParticipantIdentities[i].Player
 		}
+	}
+	return nil
+}
 
-		// Try parsing as object with "games" field (different structure)
-		var obj map[string]interface{}
-		if err := json.Unmarshal(data, &obj); err == nil {
-			if gamesData, ok := obj["games"].([]interface{}); ok && len(gamesData) > 0 {
-				// Try to parse first game
-				gameBytes, _ := json.Marshal(gamesData[0])
-				var game MatchHistoryGame
-				if err := json.Unmarshal(gameBytes, &game); err == nil {
-					return &game, nil
-				}
+// Participant returns a player's participant in the game (nil if they didn't play in it).
+// A list from another player's history has only them, without identities.
+func (g *MatchHistoryGame) Participant(puuid string) *MatchParticipant {
+	for _, identity := range g.ParticipantIdentities {
+		if identity.Player.Puuid != puuid {
+			continue
+		}
+		for i := range g.Participants {
+			if g.Participants[i].ParticipantID == identity.ParticipantID {
+				return &g.Participants[i]
 			}
 		}
 	}
+	if len(g.Participants) == 1 {
+		return &g.Participants[0]
+	}
+	return nil
+}
 
-	return nil, nil // No matches found, but not an error
+// ParticipantTeams returns each participant's team ID, by participant ID
+func (g *MatchHistoryGame) ParticipantTeams() map[int]int {
+	teams := make(map[int]int, len(g.Participants))
+	for _, participant := range g.Participants {
+		teams[participant.ParticipantID] = participant.TeamID
+	}
+	return teams
+}
+
+// GetMatchHistory retrieves a page of a player's games, newest first. begIndex and endIndex
+// count back from the latest game (0-20 is the last 20 games); an empty puuid means our own.
+func (c *Client) GetMatchHistory(puuid string, begIndex, endIndex int) ([]MatchHistoryGame, error) {
+	player := "current-summoner"
+	if puuid != "" {
+		player = url.PathEscape(puuid)
+	}
+	endpoint := fmt.Sprintf("/lol-match-history/v1/products/lol/%s/matches?begIndex=%d&endIndex=%d", player, begIndex, endIndex)
+	data, err := c.Get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match history: %w", err)
+	}
+
+	var history struct {
+		Games struct {
+			Games []MatchHistoryGame `json:"games"`
+		} `json:"games"`
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse match history: %w", err)
+	}
+
+	games := history.Games.Games
+	sort.SliceStable(games, func(i, j int) bool { return games[i].GameCreation > games[j].GameCreation })
+	return games, nil
+}
+
+// GetGame retrieves a finished game with every participant's stats and both teams' objectives
+func (c *Client) GetGame(gameID int64) (*MatchHistoryGame, error) {
+	data, err := c.Get(fmt.Sprintf("/lol-match-history/v1/games/%d", gameID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game: %w", err)
+	}
+
+	var game MatchHistoryGame
+	if err := json.Unmarshal(data, &game); err != nil {
+		return nil, fmt.Errorf("failed to parse game: %w", err)
+	}
+	if len(game.Participants) == 0 {
+		return nil, fmt.Errorf("game %d has no participants", gameID)
+	}
+	return &game, nil
+}
+
+// GetQueueType retrieves a queue's type (e.g. "RANKED_SOLO_5x5", "ARAM") from its ID
+func (c *Client) GetQueueType(queueID int) (string, error) {
+	data, err := c.Get(fmt.Sprintf("/lol-game-queues/v1/queues/%d", queueID))
+	if err != nil {
+		return "", fmt.Errorf("failed to get queue: %w", err)
+	}
+
+	var queue struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &queue); err != nil {
+		return "", fmt.Errorf("failed to parse queue: %w", err)
+	}
+	return queue.Type, nil
+}
+
+// GetRecentMatchHistory gets the most recent match from match history, falling back to the
+// older matchlist endpoint when the current one fails or is empty.
+// Returns nil, nil if no recent match is found (not an error)
+func (c *Client) GetRecentMatchHistory() (*MatchHistoryGame, error) {
+	if games, err := c.GetMatchHistory("", 0, 1); err == nil && len(games) > 0 {
+		return &games[0], nil
+	}
+
+	data, err := c.Get("/lol-match-history/v1/matchlist")
+	if err != nil {
+		return nil, nil
+	}
+	games := parseMatchList(data)
+	if len(games) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(games, func(i, j int) bool { return games[i].GameCreation > games[j].GameCreation })
+	return &games[0], nil
+}
+
+// parseMatchList reads the games of a matchlist response, which has been seen as a bare list,
+// {"games": [...]} and {"games": {"games": [...]}}
+func parseMatchList(data []byte) []MatchHistoryGame {
+	var games []MatchHistoryGame
+	if json.Unmarshal(data, &games) == nil {
+		return games
+	}
+	var list struct {
+		Games json.RawMessage `json:"games"`
+	}
+	if json.Unmarshal(data, &list) != nil {
+		return nil
+	}
+	if json.Unmarshal(list.Games, &games) == nil {
+		return games
+	}
+	var nested struct {
+		Games []MatchHistoryGame `json:"games"`
+	}
+	json.Unmarshal(list.Games, &nested)
+	return nested.Games
 }
 
 // IsRecentMatch checks if a match ended recently (within the specified duration)
@@ -76,9 +294,69 @@ func IsRecentMatch(gameEndTimestamp int64, maxAge time.Duration) bool {
 	if gameEndTimestamp == 0 {
 		return false
 	}
-	
+
 	gameEndTime := time.Unix(gameEndTimestamp/1000, 0)
 	age := time.Since(gameEndTime)
 	return age >= 0 && age <= maxAge
 }
 
+// Match-history timeline event types
+const (
+	TimelineChampionKill     = "CHAMPION_KILL"
+	TimelineEliteMonsterKill = "ELITE_MONSTER_KILL"
+	TimelineBuildingKill     = "BUILDING_KILL"
+)
+
+// GameTimeline is a finished game minute by minute, from match history
+type GameTimeline struct {
+	Frames []GameTimelineFrame `json:"frames"`
+}
+
+// GameTimelineFrame is every participant's state at one minute, with the events since the last frame
+type GameTimelineFrame struct {
+	Timestamp         int64                       `json:"timestamp"`         // Milliseconds of game time
+	ParticipantFrames map[string]ParticipantFrame `json:"participantFrames"` // By participant ID
+	Events            []GameTimelineEvent         `json:"events"`
+}
+
+// ParticipantFrame is one participant's state in a timeline frame
+type ParticipantFrame struct {
+	ParticipantID       int `json:"participantId"`
+	TotalGold           int `json:"totalGold"`
+	CurrentGold         int `json:"currentGold"`
+	Level               int `json:"level"`
+	XP                  int `json:"xp"`
+	MinionsKilled       int `json:"minionsKilled"`
+	JungleMinionsKilled int `json:"jungleMinionsKilled"`
+}
+
+// GameTimelineEvent is a kill, objective or other event in a timeline frame
+type GameTimelineEvent struct {
+	Type                    string `json:"type"`      // e.g. "CHAMPION_KILL", "ELITE_MONSTER_KILL", "BUILDING_KILL"
+	Timestamp               int64  `json:"timestamp"` // Milliseconds of game time
+	KillerID                int    `json:"killerId"`  // Participant ID, 0 for minions and turrets
+	VictimID                int    `json:"victimId"`
+	AssistingParticipantIDs []int  `json:"assistingParticipantIds"`
+	MonsterType             string `json:"monsterType"`    // "DRAGON", "BARON_NASHOR", "RIFTHERALD"
+	MonsterSubType          string `json:"monsterSubType"` // e.g. "FIRE_DRAGON", "ELDER_DRAGON"
+	BuildingType            string `json:"buildingType"`   // "TOWER_BUILDING" or "INHIBITOR_BUILDING"
+	LaneType                string `json:"laneType"`
+	TeamID                  int    `json:"teamId"` // Team that owned the building
+}
+
+// GetGameTimeline retrieves the timeline of a finished game from match history
+func (c *Client) GetGameTimeline(gameID int64) (*GameTimeline, error) {
+	data, err := c.Get(fmt.Sprintf("/lol-match-history/v1/game-timelines/%d", gameID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game timeline: %w", err)
+	}
+
+	var timeline GameTimeline
+	if err := json.Unmarshal(data, &timeline); err != nil {
+		return nil, fmt.Errorf("failed to parse game timeline: %w", err)
+	}
+	if len(timeline.Frames) == 0 {
+		return nil, fmt.Errorf("game timeline for game %d is empty", gameID)
+	}
+	return &timeline, nil
+}
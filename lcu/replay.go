@@ -0,0 +1,139 @@
+package lcu
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ReplayServer serves a recorded session back as a fake LCU over plain HTTP.
+// Each request returns the most recent recorded response for that endpoint at
+// the current (scaled) point in the recording, so phase transitions and EoG
+// payloads arrive with the same timing they had in the real game.
+type ReplayServer struct {
+	session  *Session
+	speed    float64
+	byPath   map[string][]SessionEntry
+	listener net.Listener
+	server   *http.Server
+	start    time.Time
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// NewReplayServer creates a replay server. speed scales playback (1 = real time, 10 = ten times faster).
+func NewReplayServer(session *Session, speed float64) *ReplayServer {
+	if speed <= 0 {
+		speed = 1
+	}
+
+	byPath := make(map[string][]SessionEntry)
+	for _, entry := range session.Entries {
+		byPath[entry.Endpoint] = append(byPath[entry.Endpoint], entry)
+	}
+	// lookup relies on each endpoint's entries being in time order, which older recordings
+	// don't guarantee
+	for _, entries := range byPath {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].OffsetMs < entries[j].OffsetMs })
+	}
+
+	return &ReplayServer{
+		session: session,
+		speed:   speed,
+		byPath:  byPath,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start begins serving on a random localhost port and returns lockfile info pointing at it
+func (s *ReplayServer) Start() (*LockfileInfo, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start replay server: %w", err)
+	}
+	s.listener = listener
+	s.server = &http.Server{Handler: http.HandlerFunc(s.handle)}
+	s.start = time.Now()
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("[REPLAY] Server error: %v", err)
+		}
+	}()
+
+	// Signal completion once the whole recording has been played back
+	go func() {
+		time.Sleep(time.Duration(float64(s.session.Duration()) / s.speed))
+		s.doneOnce.Do(func() { close(s.done) })
+	}()
+
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	log.Printf("[REPLAY] Serving %d recorded entries on 127.0.0.1:%s at %.1fx speed (duration %v)",
+		len(s.session.Entries), port, s.speed, s.session.Duration())
+
+	return &LockfileInfo{
+		ProcessName: "replay",
+		PID:         "0",
+		Port:        port,
+		Password:    "replay",
+		Protocol:    "http",
+	}, nil
+}
+
+// Done is closed when playback reaches the end of the recording
+func (s *ReplayServer) Done() <-chan struct{} {
+	return s.done
+}
+
+// Elapsed returns the current position in the recording
+func (s *ReplayServer) Elapsed() time.Duration {
+	return time.Duration(float64(time.Since(s.start)) * s.speed)
+}
+
+// Close stops the replay server
+func (s *ReplayServer) Close() error {
+	s.doneOnce.Do(func() { close(s.done) })
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Close()
+}
+
+func (s *ReplayServer) handle(w http.ResponseWriter, r *http.Request) {
+	endpoint := r.URL.RequestURI()
+	entry, ok := s.lookup(endpoint, s.Elapsed())
+	if !ok {
+		http.Error(w, fmt.Sprintf("no recorded response for %s", endpoint), http.StatusNotFound)
+		return
+	}
+
+	// Transport errors are replayed as an unavailable service
+	if entry.Error != "" && entry.Status == 0 {
+		http.Error(w, entry.Error, http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(entry.Status)
+	w.Write([]byte(entry.Body))
+}
+
+// lookup returns the latest entry for endpoint recorded at or before elapsed
+func (s *ReplayServer) lookup(endpoint string, elapsed time.Duration) (SessionEntry, bool) {
+	entries := s.byPath[endpoint]
+	var found SessionEntry
+	ok := false
+	for _, entry := range entries {
+		if entry.Offset() > elapsed {
+			break
+		}
+		found = entry
+		ok = true
+	}
+	return found, ok
+}
@@ -0,0 +1,144 @@
+package lcu
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SessionEntry is a single recorded request/response pair
+type SessionEntry struct {
+	Time     time.Time `json:"time"`
+	OffsetMs int64     `json:"offsetMs"` // Milliseconds since the recording started
+	Endpoint string    `json:"endpoint"`
+	Status   int       `json:"status"`          // HTTP status (0 if the request never got a response)
+	Body     string    `json:"body,omitempty"`  // Raw response body
+	Error    string    `json:"error,omitempty"` // Transport error, if any
+}
+
+// Offset returns the entry's offset from the start of the recording
+func (e SessionEntry) Offset() time.Duration {
+	return time.Duration(e.OffsetMs) * time.Millisecond
+}
+
+// Session is a recorded LCU session loaded from disk
+type Session struct {
+	Entries []SessionEntry
+}
+
+// Duration returns the offset of the latest recorded entry. Entries from older recordings may
+// be slightly out of order, so the last one isn't always the latest.
+func (s *Session) Duration() time.Duration {
+	var latest time.Duration
+	for _, entry := range s.Entries {
+		if offset := entry.Offset(); offset > latest {
+			latest = offset
+		}
+	}
+	return latest
+}
+
+// Recorder writes every LCU request and response to a session file (one JSON object per line)
+type Recorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	enc   *json.Encoder
+	start time.Time
+}
+
+// NewRecorder creates (or truncates) a session file at path
+func NewRecorder(path string) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session file: %w", err)
+	}
+
+	return &Recorder{
+		file:  file,
+		enc:   json.NewEncoder(file),
+		start: time.Now(),
+	}, nil
+}
+
+// Record appends a request/response pair to the session file
+func (r *Recorder) Record(endpoint string, status int, body []byte, reqErr error) {
+	if r == nil {
+		return
+	}
+
+	entry := SessionEntry{
+		Endpoint: endpoint,
+		Status:   status,
+		Body:     string(body),
+	}
+	if reqErr != nil {
+		entry.Error = reqErr.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return
+	}
+	// Timestamp under the lock so concurrent monitors' entries are written in time order
+	now := time.Now()
+	entry.Time = now
+	entry.OffsetMs = now.Sub(r.start).Milliseconds()
+	// Recording is best effort - a failed write must never break the caller
+	_ = r.enc.Encode(entry)
+}
+
+// Close flushes and closes the session file
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// LoadSession reads a session file written by Recorder
+func LoadSession(path string) (*Session, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session file: %w", err)
+	}
+	defer file.Close()
+
+	session := &Session{}
+	scanner := bufio.NewScanner(file)
+	// EoG responses are ~35KB, but allow plenty of room for larger payloads
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry SessionEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid session entry on line %d: %w", line, err)
+		}
+		session.Entries = append(session.Entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	return session, nil
+}
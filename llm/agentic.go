@@ -1,17 +1,17 @@
 package llm
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"lol-kind-bot/analyzer"
 	"lol-kind-bot/config"
 	"log"
-	"net/http"
 	"sort"
 	"strings"
 	"sync"
+	"unicode/utf8"
 )
 
 // AdvocateTestimony represents a worker's advocacy for a player
@@ -47,6 +47,8 @@ type AgenticSystem struct {
 	client      *Client
 	gameSummary *analyzer.GameSummary
 	llmSettings *config.LLMSettings
+	preferences Preferences     // Learned from feedback; nil when disabled
+	ctx         context.Context // Cancels every LLM call of the workflow
 }
 
 // NewAgenticSystem creates a new agentic system
@@ -55,11 +57,55 @@ func NewAgenticSystem(client *Client, gameSummary *analyzer.GameSummary, llmSett
 		client:      client,
 		gameSummary: gameSummary,
 		llmSettings: llmSettings,
+		ctx:         context.Background(),
 	}
 }
 
 // GenerateMessages runs the full agentic workflow
 func (as *AgenticSystem) GenerateMessages(enableDebug bool) ([]string, error) {
+	suggestions, err := as.GenerateSuggestions(enableDebug)
+	if err != nil {
+		return nil, err
+	}
+	return SuggestionTexts(suggestions), nil
+}
+
+// GenerateSuggestions runs the full agentic workflow, keeping the testimony behind each message
+func (as *AgenticSystem) GenerateSuggestions(enableDebug bool) ([]Suggestion, error) {
+	return as.generateSuggestions(enableDebug, nil)
+}
+
+// streamBuffer is how many events StreamSuggestions buffers, so drafts don't hold up generation
+const streamBuffer = 64
+
+// SuggestionEvent is one step of StreamSuggestions
+type SuggestionEvent struct {
+	Suggestion *Suggestion  // A message whose candidate just finished
+	Draft      *Suggestion  // A message still being written, with the text generated so far
+	Done       bool         // Last event: the workflow has finished
+	Final      []Suggestion // On the last event: every message, padded with fallbacks like GenerateSuggestions
+	Err        error        // On the last event: why the workflow failed
+}
+
+// StreamSuggestions runs the full agentic workflow in the background and sends each message
+// as soon as its candidate finishes, instead of waiting for all of them, with drafts of the
+// messages while they are written. Cancelling ctx stops the workflow early, keeping the
+// messages already finished. The last event has Done set; the channel is closed after it.
+func (as *AgenticSystem) StreamSuggestions(ctx context.Context, enableDebug bool) <-chan SuggestionEvent {
+	as.ctx = ctx
+	events := make(chan SuggestionEvent, streamBuffer)
+	go func() {
+		defer close(events)
+		suggestions, err := as.generateSuggestions(enableDebug, func(event SuggestionEvent) {
+			events <- event
+		})
+		events <- SuggestionEvent{Done: true, Final: suggestions, Err: err}
+	}()
+	return events
+}
+
+// generateSuggestions runs the four phases, calling emit (if set) with each message and draft as it is generated
+func (as *AgenticSystem) generateSuggestions(enableDebug bool, emit func(SuggestionEvent)) ([]Suggestion, error) {
 	// Phase 1: Advocate - 10 workers advocate for each player
 	testimonies, err := as.runAdvocatePhase(enableDebug)
 	if err != nil {
@@ -79,12 +125,12 @@ func (as *AgenticSystem) GenerateMessages(enableDebug bool) ([]string, error) {
 	}
 
 	// Phase 4: Generate messages for top N candidates
-	messages, err := as.runMessageGenerationPhase(testimonies, enableDebug)
+	suggestions, err := as.runMessageGenerationPhase(testimonies, enableDebug, emit)
 	if err != nil {
 		return nil, fmt.Errorf("message generation phase failed: %w", err)
 	}
 
-	return messages, nil
+	return suggestions, nil
 }
 
 // runAdvocatePhase creates 10 advocate workers, one per player
@@ -143,7 +189,7 @@ func (as *AgenticSystem) runAdvocatePhase(enableDebug bool) ([]*AdvocateTestimon
 func (as *AgenticSystem) advocateForPlayer(playerIndex int, player analyzer.PlayerSummary, gameSummaryJSON string, enableDebug bool) *AdvocateTestimony {
 	prompt := as.buildAdvocatePrompt(playerIndex, player, gameSummaryJSON)
 
-	response, err := as.client.generateRaw(prompt)
+	response, err := as.client.generateRaw(as.ctx, prompt)
 	if err != nil {
 		log.Printf("[AGENTIC] Advocate worker failed for %s: %v", player.Champion, err)
 		return &AdvocateTestimony{
@@ -235,7 +281,8 @@ Generate your advocacy now:`,
 		player.VisionScore,
 		player.LivesSaved,
 		player.TimesSaved,
-		player.CriticalSaves)
+		player.CriticalSaves,
+		player.LivesSaved)
 }
 
 // parseAdvocateResponse parses the advocate worker's response
@@ -362,7 +409,7 @@ func (as *AgenticSystem) validateTestimonies(testimonies []*AdvocateTestimony, g
 	// Build prompt for validation worker
 	prompt := as.buildValidationPrompt(testimonies, gameSummaryJSON, validatorID)
 
-	response, err := as.client.generateRaw(prompt)
+	response, err := as.client.generateRaw(as.ctx, prompt)
 	if err != nil {
 		log.Printf("[AGENTIC] Validation worker %d failed: %v", validatorID, err)
 		// Default: approve all if validation fails
@@ -549,7 +596,7 @@ func (as *AgenticSystem) runJudgingPhase(testimonies []*AdvocateTestimony, enabl
 func (as *AgenticSystem) judgeTestimonies(testimonies []*AdvocateTestimony, gameSummaryJSON string, judgeID int, enableDebug bool) []float64 {
 	prompt := as.buildJudgePrompt(testimonies, gameSummaryJSON, judgeID)
 
-	response, err := as.client.generateRaw(prompt)
+	response, err := as.client.generateRaw(as.ctx, prompt)
 	if err != nil {
 		log.Printf("[AGENTIC] Judge %d failed: %v", judgeID, err)
 		// Default: equal scores
@@ -658,8 +705,9 @@ func (as *AgenticSystem) parseJudgeResponse(response string, expectedCount int)
 	return scores
 }
 
-// runMessageGenerationPhase generates messages for top N candidates
-func (as *AgenticSystem) runMessageGenerationPhase(testimonies []*AdvocateTestimony, enableDebug bool) ([]string, error) {
+// runMessageGenerationPhase generates messages for top N candidates, passing drafts and each
+// finished message to emit (if set)
+func (as *AgenticSystem) runMessageGenerationPhase(testimonies []*AdvocateTestimony, enableDebug bool, emit func(SuggestionEvent)) ([]Suggestion, error) {
 	if enableDebug {
 		log.Printf("[AGENTIC] Starting message generation phase")
 	}
@@ -670,7 +718,7 @@ func (as *AgenticSystem) runMessageGenerationPhase(testimonies []*AdvocateTestim
 		numMessages = len(testimonies)
 	}
 
-	// Sort testimonies by score (highest first)
+	// Sort testimonies by score (highest first), adjusted by learned preferences
 	sortedTestimonies := make([]*AdvocateTestimony, 0, len(testimonies))
 	for _, t := range testimonies {
 		if t != nil && t.Champion != "" {
@@ -679,7 +727,7 @@ func (as *AgenticSystem) runMessageGenerationPhase(testimonies []*AdvocateTestim
 	}
 
 	sort.Slice(sortedTestimonies, func(i, j int) bool {
-		return sortedTestimonies[i].Score > sortedTestimonies[j].Score
+		return as.rankingScore(sortedTestimonies[i]) > as.rankingScore(sortedTestimonies[j])
 	})
 
 	// Take top N
@@ -698,22 +746,28 @@ func (as *AgenticSystem) runMessageGenerationPhase(testimonies []*AdvocateTestim
 
 	// Generate messages for each top candidate (PARALLELIZED for performance)
 	gameSummaryJSON, _ := json.MarshalIndent(as.gameSummary, "", "  ")
-	messages := make([]string, 0, topN)
+	messages := make([]Suggestion, 0, topN)
 	
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	messageChan := make(chan string, topN)
+	messageChan := make(chan Suggestion, topN)
 	
 	// Generate messages in parallel
 	for _, candidate := range topCandidates {
 		wg.Add(1)
 		go func(cand *AdvocateTestimony) {
 			defer wg.Done()
-			message := as.generateMessageForCandidate(cand, string(gameSummaryJSON), enableDebug)
+			var onText func(string)
+			if emit != nil {
+				onText = func(text string) {
+					emit(SuggestionEvent{Draft: &Suggestion{Text: strings.TrimSpace(text), Champion: cand.Champion, Tone: as.llmSettings.Tone}})
+				}
+			}
+			message := as.generateMessageForCandidate(cand, string(gameSummaryJSON), "", enableDebug, onText)
 			if message != "" {
 				// Validate win/loss context before adding
 				if as.validateWinLossContext(message, cand.PlayerIndex, enableDebug) {
-					messageChan <- message
+					messageChan <- Suggestion{Text: message, Champion: cand.Champion, Tone: as.llmSettings.Tone, Testimony: cand}
 				} else if enableDebug {
 					log.Printf("[AGENTIC] Filtered message for %s due to win/loss mismatch: %s", cand.Champion, message)
 				}
@@ -732,6 +786,9 @@ func (as *AgenticSystem) runMessageGenerationPhase(testimonies []*AdvocateTestim
 		mu.Lock()
 		messages = append(messages, msg)
 		mu.Unlock()
+		if emit != nil {
+			emit(SuggestionEvent{Suggestion: &msg})
+		}
 	}
 
 	// Fallback if no messages generated
@@ -739,22 +796,15 @@ func (as *AgenticSystem) runMessageGenerationPhase(testimonies []*AdvocateTestim
 		if enableDebug {
 			log.Printf("[AGENTIC] No messages generated, using fallback")
 		}
-		messages = generateContextualFallbackMessages(string(gameSummaryJSON))
+		messages = OfflineSuggestions(as.gameSummary, as.llmSettings)
 	}
 
 	// Ensure we have at least MinMessages (pad with fallback if needed)
 	if len(messages) < as.llmSettings.MinMessages {
-		fallback := generateContextualFallbackMessages(string(gameSummaryJSON))
+		fallback := OfflineSuggestions(as.gameSummary, as.llmSettings)
 		for len(messages) < as.llmSettings.MinMessages && len(fallback) > 0 {
-			// Check if fallback message already exists
-			exists := false
-			for _, msg := range messages {
-				if msg == fallback[0] {
-					exists = true
-					break
-				}
-			}
-			if !exists {
+			// Skip fallback messages we already have, or about players we already praised
+			if !hasSuggestion(messages, fallback[0].Text) && !praisesChampion(messages, fallback[0].Champion) {
 				messages = append(messages, fallback[0])
 			}
 			fallback = fallback[1:]
@@ -774,12 +824,20 @@ func (as *AgenticSystem) runMessageGenerationPhase(testimonies []*AdvocateTestim
 }
 
 // generateMessageForCandidate generates a single message for a candidate
-func (as *AgenticSystem) generateMessageForCandidate(candidate *AdvocateTestimony, gameSummaryJSON string, enableDebug bool) string {
+// guidance is optional extra instructions (e.g. a different tone) added to the prompt.
+// onText, if set, streams the response and receives the text written so far.
+func (as *AgenticSystem) generateMessageForCandidate(candidate *AdvocateTestimony, gameSummaryJSON string, guidance string, enableDebug bool, onText func(string)) string {
 	player := as.gameSummary.Players[candidate.PlayerIndex]
 	
-	prompt := as.buildMessagePrompt(candidate, player, gameSummaryJSON)
+	prompt := as.buildMessagePrompt(candidate, player, gameSummaryJSON, guidance)
 
-	response, err := as.client.generateRaw(prompt)
+	var response string
+	var err error
+	if onText != nil {
+		response, err = as.client.GenerateStream(as.ctx, prompt, onText)
+	} else {
+		response, err = as.client.generateRaw(as.ctx, prompt)
+	}
 	if err != nil {
 		log.Printf("[AGENTIC] Message generation failed for %s: %v", candidate.Champion, err)
 		return ""
@@ -805,10 +863,21 @@ func (as *AgenticSystem) generateMessageForCandidate(candidate *AdvocateTestimon
 		}
 	}
 
+	// Examples show the style only; ask once more for an original message
+	if currentExampleLibrary().copiesExample(message) {
+		if enableDebug {
+			log.Printf("[AGENTIC] Message for %s copied an example, regenerating: %s", candidate.Champion, message)
+		}
+		if strings.Contains(guidance, copiedExampleGuidance) {
+			return ""
+		}
+		return as.generateMessageForCandidate(candidate, gameSummaryJSON, strings.TrimSpace(guidance+"\n"+copiedExampleGuidance), enableDebug, onText)
+	}
+
 	// Limit length
 	maxLen := as.llmSettings.MaxMessageLength
-	if maxLen > 0 && len(message) > maxLen {
-		message = message[:maxLen]
+	if maxLen > 0 && utf8.RuneCountInString(message) > maxLen {
+		message = string([]rune(message)[:maxLen]) // Count characters, not bytes, for non-English messages
 		// Try to end at a sentence
 		lastPeriod := strings.LastIndex(message, ".")
 		if lastPeriod > maxLen*2/3 {
@@ -829,29 +898,8 @@ func (as *AgenticSystem) validateWinLossContext(message string, playerIndex int,
 	didWin := player.Team == as.gameSummary.WinningTeam
 	lowerMsg := strings.ToLower(message)
 
-	// List of win-related phrases that should NOT appear for losing players
-	winPhrases := []string{
-		"helped secure the win",
-		"secured the win",
-		"helped win",
-		"great win",
-		"secured the victory",
-		"contributed to victory",
-		"contributed to the win",
-		"helped secure victory",
-		"victory",
-		"won the game",
-		"winning",
-	}
-
-	// Check if message contains win phrases
-	hasWinPhrase := false
-	for _, phrase := range winPhrases {
-		if strings.Contains(lowerMsg, phrase) {
-			hasWinPhrase = true
-			break
-		}
-	}
+	// Win-related phrases (in the message language) that should NOT appear for losing players
+	hasWinPhrase := containsAny(lowerMsg, languageFor(as.llmSettings.Language).Keywords.Win)
 
 	// If player lost but message has win phrases, reject it
 	if !didWin && hasWinPhrase {
@@ -879,7 +927,11 @@ func buildLanguageStyleForAgentic(style string) string {
 }
 
 // buildMessagePrompt creates prompt for generating a message for a candidate
-func (as *AgenticSystem) buildMessagePrompt(candidate *AdvocateTestimony, player analyzer.PlayerSummary, gameSummaryJSON string) string {
+func (as *AgenticSystem) buildMessagePrompt(candidate *AdvocateTestimony, player analyzer.PlayerSummary, gameSummaryJSON string, guidance string) string {
+	if guidance != "" {
+		guidance = "ADDITIONAL GUIDANCE:\n" + guidance + "\n\n"
+	}
+
 	// Determine if this player won or lost
 	didWin := player.Team == as.gameSummary.WinningTeam
 	winLossContext := "WON"
@@ -910,6 +962,7 @@ MESSAGE REQUIREMENTS:
 - Reference the champion by name (%s)
 - Focus on their standout achievements
 - Language style: %s
+- %s
 - No team color references (RED/BLUE)
 - No future game references
 
@@ -931,7 +984,7 @@ CLUTCH MOMENTS HIGHLIGHTING:
 - If TimesSaved > 0: Acknowledge teamwork! Example: "Thanks for keeping me alive!"
 - Use authentic gamer language: "omg", "clutch", "saved my life", etc.
 
-Generate ONE message now (just the message text, no labels or formatting):`,
+%s%s%sGenerate ONE message now (just the message text, no labels or formatting):`,
 		candidate.Champion,
 		winLossContext,
 		as.gameSummary.WinningTeam,
@@ -943,6 +996,7 @@ Generate ONE message now (just the message text, no labels or formatting):`,
 		as.llmSettings.MaxMessageLength,
 		candidate.Champion,
 		buildLanguageStyleForAgentic(as.llmSettings.LanguageStyle),
+		buildToneInstructions(as.llmSettings.Tone),
 		candidate.Champion,
 		candidate.Champion,
 		candidate.Champion,
@@ -952,43 +1006,19 @@ Generate ONE message now (just the message text, no labels or formatting):`,
 		player.CriticalSaves,
 		player.LivesSaved,
 		player.CriticalSaves,
-		player.TimesSaved)
+		as.exampleMessages(player),
+		buildLanguageInstructions(as.llmSettings.Language),
+		guidance)
 }
 
 // generateRaw makes a raw LLM call and returns the response
-func (c *Client) generateRaw(prompt string) (string, error) {
-	reqBody := GenerateRequest{
-		Model:       c.Model,
-		Prompt:      prompt,
-		Stream:      false,
-		Temperature: c.Config.Temperature,
-	}
-	
-	if c.Config.MaxTokens > 0 {
-		reqBody.NumPredict = c.Config.MaxTokens
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// Use shared HTTP client for connection pooling and parallel requests
-	if c.httpClient == nil {
-		c.httpClient = SharedHTTPClient
-	}
-
-	resp, err := c.httpClient.Post(c.URL, "application/json", bytes.NewBuffer(jsonData))
+func (c *Client) generateRaw(ctx context.Context, prompt string) (string, error) {
+	resp, err := c.post(ctx, prompt, false)
 	if err != nil {
-		return "", fmt.Errorf("HTTP request failed: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
-	}
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", fmt.Errorf("failed to read response: %w", err)
@@ -1001,4 +1031,3 @@ func (c *Client) generateRaw(prompt string) (string, error) {
 
 	return genResp.Response, nil
 }
-
@@ -0,0 +1,250 @@
+package llm
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"lol-kind-bot/config"
+	"strings"
+	"unicode/utf8"
+)
+
+// Opener tags: what a champion select opener template needs to apply
+const (
+	OpenerDuo          = "duo"           // We and our bot lane partner have both chosen champions
+	OpenerLossStreak   = "loss_streak"   // A teammate lost their last few games
+	OpenerPlayedBefore = "played_before" // A teammate was in one of our earlier games
+	OpenerWonTogether  = "won_together"  // ...and we won at least one of them
+)
+
+// minLossStreak is how many losses in a row count as a streak worth an encouraging word
+const minLossStreak = 3
+
+//go:embed openers.json
+var builtinOpenersJSON []byte
+
+// builtinOpeners are English champion select openers in the Template format. Slots: {myChampion},
+// {partner} (our bot lane partner's champion), and a teammate's {champion} or {name}.
+var builtinOpeners = func() []Template {
+	var openers []Template
+	if err := json.Unmarshal(builtinOpenersJSON, &openers); err != nil {
+		panic(fmt.Sprintf("invalid built-in openers: %v", err)) // Embedded file; caught by any run
+	}
+	return openers
+}()
+
+// Teammate is another player on our team in champion select
+type Teammate struct {
+	Name        string `json:"name,omitempty"`     // Riot ID name; hidden in some queues
+	Champion    string `json:"champion,omitempty"` // Picked or hovered; empty until they choose
+	Position    string `json:"position,omitempty"` // "top", "jungle", "middle", "bottom", "utility"
+	LossStreak  int    `json:"lossStreak,omitempty"`
+	GamesWithUs int    `json:"gamesWithUs,omitempty"` // Earlier games together, from our history
+	WinsWithUs  int    `json:"winsWithUs,omitempty"`
+}
+
+// ChampSelect is our team in champion select, as openers are written from it
+type ChampSelect struct {
+	MyChampion string     `json:"myChampion,omitempty"`
+	MyPosition string     `json:"myPosition,omitempty"`
+	Teammates  []Teammate `json:"teammates"`
+}
+
+// LanePartner returns the teammate sharing bot lane with us, or nil if we aren't bot or support
+func (c ChampSelect) LanePartner() *Teammate {
+	partner := map[string]string{"bottom": "utility", "utility": "bottom"}[c.MyPosition]
+	if partner == "" {
+		return nil
+	}
+	for i := range c.Teammates {
+		if c.Teammates[i].Position == partner {
+			return &c.Teammates[i]
+		}
+	}
+	return nil
+}
+
+// Key sums up what openers are written from, so they are only rewritten when it changes
+func (c ChampSelect) Key() string {
+	data, _ := json.Marshal(c)
+	return string(data)
+}
+
+// forTeammate reports whether an opener is about one teammate rather than the whole team
+func forTeammate(t Template) bool {
+	return strings.Contains(t.Text, "{champion}") || strings.Contains(t.Text, "{name}")
+}
+
+// OfflineOpeners writes champion select openers without the LLM. English openers come from
+// templates; other languages (and anything left to fill) use the language's good luck wishes.
+func OfflineOpeners(cs ChampSelect, settings *config.LLMSettings) []string {
+	pack := languageFor(settings.Language)
+	var openers []string
+	if pack == languagePacks[DefaultLanguage] {
+		openers = templateOpeners(cs, settings.MaxMessages)
+	}
+
+	for _, opener := range pack.Chat.Openers {
+		if len(openers) >= settings.MinMessages && len(openers) > 0 {
+			break
+		}
+		if !containsString(openers, opener) {
+			openers = append(openers, opener)
+		}
+	}
+
+	var result []string
+	for _, opener := range openers {
+		if settings.MaxMessageLength <= 0 || utf8.RuneCountInString(opener) <= settings.MaxMessageLength {
+			result = append(result, opener)
+		}
+	}
+	return result
+}
+
+// templateOpeners fills up to n opener templates: at most one for the whole team, the rest
+// for different teammates, most specific first
+func templateOpeners(cs ChampSelect, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	teamTags := tagSet(teamOpenerTags(cs))
+	var candidates []templateCandidate
+	for _, template := range builtinOpeners {
+		if !forTeammate(template) {
+			if score, ok := templateScore(template, teamTags); ok {
+				if text, ok := fillOpener(template.Text, cs, nil); ok {
+					candidates = append(candidates, templateCandidate{template: template, text: text, score: score})
+				}
+			}
+			continue
+		}
+		for i := range cs.Teammates {
+			teammate := &cs.Teammates[i]
+			score, ok := templateScore(template, tagSet(teammateOpenerTags(*teammate)))
+			if !ok {
+				continue
+			}
+			text, ok := fillOpener(template.Text, cs, teammate)
+			if !ok {
+				continue
+			}
+			// One opener per teammate, whether it names their champion or them
+			candidates = append(candidates, templateCandidate{template: template, text: text, champion: fmt.Sprintf("teammate %d", i), score: score})
+		}
+	}
+
+	var openers []string
+	for _, c := range pickTemplates(candidates, n) {
+		openers = append(openers, c.text)
+	}
+	return openers
+}
+
+// teamOpenerTags returns the tags describing the whole team
+func teamOpenerTags(cs ChampSelect) []string {
+	var tags []string
+	if partner := cs.LanePartner(); partner != nil && partner.Champion != "" && cs.MyChampion != "" {
+		tags = append(tags, OpenerDuo)
+	}
+	for _, teammate := range cs.Teammates {
+		if teammate.LossStreak >= minLossStreak {
+			tags = append(tags, OpenerLossStreak)
+			break
+		}
+	}
+	return tags
+}
+
+// teammateOpenerTags returns the tags that apply to one teammate
+func teammateOpenerTags(teammate Teammate) []string {
+	var tags []string
+	if teammate.LossStreak >= minLossStreak {
+		tags = append(tags, OpenerLossStreak)
+	}
+	if teammate.GamesWithUs > 0 {
+		tags = append(tags, OpenerPlayedBefore)
+	}
+	if teammate.WinsWithUs > 0 {
+		tags = append(tags, OpenerWonTogether)
+	}
+	return tags
+}
+
+// fillOpener replaces an opener's slots, returning false if one has no value
+func fillOpener(text string, cs ChampSelect, teammate *Teammate) (string, bool) {
+	values := map[string]string{"myChampion": cs.MyChampion}
+	if partner := cs.LanePartner(); partner != nil {
+		values["partner"] = partner.Champion
+	}
+	if teammate != nil {
+		values["champion"] = teammate.Champion
+		values["name"] = teammate.Name
+	}
+	return fillSlots(text, values, nil)
+}
+
+// GenerateOpeners asks the LLM for champion select openers. Cancelling ctx stops it.
+func (c *Client) GenerateOpeners(ctx context.Context, cs ChampSelect, enableDebug bool) ([]string, error) {
+	prompt := buildOpenerPrompt(cs, c.Config)
+	if enableDebug {
+		log.Printf("[DEBUG] Opener prompt:\n%s", prompt)
+	}
+
+	response, err := c.GenerateStream(ctx, prompt, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate openers: %w", err)
+	}
+
+	var openers []string
+	for _, opener := range parseMessages(response, c.Config.MaxMessageLength) {
+		if containsAny(strings.ToLower(opener), openerBannedWords) {
+			if enableDebug {
+				log.Printf("[DEBUG] Dropped opener mentioning losses: %s", opener)
+			}
+			continue
+		}
+		openers = append(openers, opener)
+	}
+	if len(openers) == 0 {
+		return nil, fmt.Errorf("no usable openers in LLM response")
+	}
+	if len(openers) > c.Config.MaxMessages {
+		openers = openers[:c.Config.MaxMessages]
+	}
+	return openers, nil
+}
+
+// openerBannedWords point at a teammate's losses, which an opener must never do
+var openerBannedWords = []string{"streak", "losing", "lost", "loss"}
+
+// buildOpenerPrompt asks for openers for our team
+func buildOpenerPrompt(cs ChampSelect, settings *config.LLMSettings) string {
+	team, _ := json.MarshalIndent(cs, "", "  ")
+
+	language := ""
+	if pack := languageFor(settings.Language); pack != languagePacks[DefaultLanguage] {
+		language = fmt.Sprintf("- Write every message in %s, the way players from this region talk, e.g. %s. Keep champion names as given.\n", pack.Name, pack.Slang)
+	}
+
+	return fmt.Sprintf(`You are a friendly League of Legends player in champion select. The game hasn't started yet.
+Write %d short opening messages for our team chat, one per line, with no numbering or quotes.
+
+Our team (positions: top, jungle, middle, bottom, utility = support):
+%s
+
+RULES:
+- Wish the team good luck and set a positive mood.
+- If our bot lane partner's champion is known, a message may say how well their champion and ours (myChampion) work together.
+- A teammate with a lossStreak of %d or more could use encouragement, but never mention their losses, streak or past games.
+- A teammate with gamesWithUs above 0 has played with us before; you may greet them by name.
+- Only mention the champions and names listed above. Never mention ranks, stats or the enemy team.
+- Keep each message under %d characters.
+%s
+%s
+%s`, settings.MaxMessages, team, minLossStreak, settings.MaxMessageLength,
+		buildToneInstructions(settings.Tone), buildLanguageStyleInstructions(settings.LanguageStyle), language)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"lol-kind-bot/analyzer"
 	"lol-kind-bot/config"
 	"log"
 	"net"
@@ -12,6 +13,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 type Client struct {
@@ -50,11 +52,32 @@ func init() {
 }
 
 type GenerateRequest struct {
-	Model       string  `json:"model"`
-	Prompt      string  `json:"prompt"`
-	Stream      bool    `json:"stream"`
-	Temperature float64 `json:"temperature,omitempty"`
-	NumPredict  int     `json:"num_predict,omitempty"` // Max tokens for Ollama
+	Model       string           `json:"model"`
+	Prompt      string           `json:"prompt"`
+	Stream      bool             `json:"stream"`
+	Options     *GenerateOptions `json:"options,omitempty"`     // Ollama's sampling options
+	Temperature *float64         `json:"temperature,omitempty"` // For OpenAI-style completions endpoints, which ignore options
+	MaxTokens   int              `json:"max_tokens,omitempty"`  // For OpenAI-style completions endpoints
+}
+
+// GenerateOptions are the Ollama model options sent with a request. Temperature is a pointer
+// so an explicit 0 is sent rather than dropped.
+type GenerateOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"` // Max tokens
+}
+
+// newGenerateRequest builds a request for prompt with the configured temperature and max tokens
+func (c *Client) newGenerateRequest(prompt string, stream bool) GenerateRequest {
+	temperature := c.Config.Temperature
+	return GenerateRequest{
+		Model:       c.Model,
+		Prompt:      prompt,
+		Stream:      stream,
+		Options:     &GenerateOptions{Temperature: &temperature, NumPredict: c.Config.MaxTokens},
+		Temperature: &temperature,
+		MaxTokens:   c.Config.MaxTokens,
+	}
 }
 
 type GenerateResponse struct {
@@ -275,6 +298,7 @@ You have access to rich, detailed game statistics. Analyze beyond surface-level
    - "hadClutchMoments": Indicators of clutch plays (true = highlight clutch moments!)
    - "isIntenseMatch": Intense, competitive match (already handled above)
    - "isComeback": Comeback victory (already handled above)
+   - "goldDiffAt15", "leadSwings", "turningPoints", "story": Only present when the game's timeline is known. "turningPoints" lists the teamfights that swung the game with their time, and "story" has the game's arc (comeback, throw, back-and-forth, stomp, close, steady), its phases and the decisive moment - you may mention one ("that Baron fight at 23:40!"), but never invent fights, leads or comebacks that aren't there
    
    USE THESE TO TAILOR MESSAGES:
    - If "wasClose": "What a close game!" "That was intense!"
@@ -371,6 +395,13 @@ Remember: The data tells a story. Look for patterns, relationships, and context.
 		}
 	}
 	
+	// Pick example messages for this kind of game
+	examplesContext := ""
+	var summary analyzer.GameSummary
+	if err := json.Unmarshal([]byte(gameSummaryJSON), &summary); err == nil {
+		examplesContext = formatExamples(currentExampleLibrary().Pick(gameExampleTags(&summary), maxPromptExamples))
+	}
+
 	// Build tone-specific instructions
 	toneInstructions := buildToneInstructions(llmSettings.Tone)
 	
@@ -435,7 +466,7 @@ CRITICAL MESSAGING RULES:
 
 ` + llmSettings.CustomInstructions + `
 
-CRITICAL ACCURACY RULES - USE EXPLICIT DATA ONLY:
+` + examplesContext + buildLanguageInstructions(llmSettings.Language) + `CRITICAL ACCURACY RULES - USE EXPLICIT DATA ONLY:
 The game summary includes an "achievements" object with EXPLICIT data. Use this data directly - do NOT infer or calculate anything!
 
 1. DAMAGE CLAIMS - USE EXPLICIT ACHIEVEMENTS DATA:
@@ -610,17 +641,7 @@ func buildAFKHandlingInstructions(handling string) string {
 }
 
 func (c *Client) Generate(prompt string, gameSummaryJSON string, enableDebug bool) ([]string, error) {
-	reqBody := GenerateRequest{
-		Model:       c.Model,
-		Prompt:      prompt,
-		Stream:      false,
-		Temperature: c.Config.Temperature,
-	}
-	
-	// Add max tokens if configured
-	if c.Config.MaxTokens > 0 {
-		reqBody.NumPredict = c.Config.MaxTokens
-	}
+	reqBody := c.newGenerateRequest(prompt, false)
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
@@ -665,7 +686,8 @@ func (c *Client) Generate(prompt string, gameSummaryJSON string, enableDebug boo
 	}
 	
 	// Validate messages against game data to catch incorrect claims
-	messages = validateMessages(messages, gameSummaryJSON, enableDebug)
+	messages = validateMessages(messages, gameSummaryJSON, c.Config.Language, enableDebug)
+	messages = dropCopiedExamples(messages, enableDebug)
 	
 	if enableDebug {
 		log.Printf("[DEBUG] After validation: %d messages", len(messages))
@@ -679,7 +701,7 @@ func (c *Client) Generate(prompt string, gameSummaryJSON string, enableDebug boo
 			log.Printf("[DEBUG] No valid messages after validation, using fallback")
 		}
 		// Fallback messages - generate context-aware fallbacks if possible
-		return generateContextualFallbackMessages(gameSummaryJSON), nil
+		return generateContextualFallbackMessages(gameSummaryJSON, c.Config), nil
 	}
 
 	// Limit to configured max messages
@@ -688,7 +710,7 @@ func (c *Client) Generate(prompt string, gameSummaryJSON string, enableDebug boo
 	}
 	// Ensure we have at least min messages (pad with contextual fallback if needed)
 	if len(messages) < c.Config.MinMessages {
-		fallback := generateContextualFallbackMessages(gameSummaryJSON)
+		fallback := generateContextualFallbackMessages(gameSummaryJSON, c.Config)
 		for len(messages) < c.Config.MinMessages && len(fallback) > 0 {
 			messages = append(messages, fallback[0])
 			fallback = fallback[1:]
@@ -751,7 +773,7 @@ func parseMessages(response string, maxLength int) []string {
 		line = strings.TrimSpace(line)
 		
 		// Skip lines that are too long
-		if len(line) > maxLengthWithBuffer {
+		if utf8.RuneCountInString(line) > maxLengthWithBuffer {
 			continue
 		}
 		
@@ -843,8 +865,10 @@ func parseMessages(response string, maxLength int) []string {
 	return messages
 }
 
-// validateMessages checks if LLM claims match actual game data flags
-func validateMessages(messages []string, gameSummaryJSON string, enableDebug bool) []string {
+// validateMessages checks if LLM claims match actual game data flags, using the claim
+// phrases of the language the messages were written in
+func validateMessages(messages []string, gameSummaryJSON string, language string, enableDebug bool) []string {
+	keywords := languageFor(language).Keywords
 	var gameSummary map[string]interface{}
 	if err := json.Unmarshal([]byte(gameSummaryJSON), &gameSummary); err != nil {
 		return messages // If we can't parse, return as-is
@@ -926,12 +950,8 @@ func validateMessages(messages []string, gameSummaryJSON string, enableDebug boo
 		// Check if message claims this champion dealt most damage IN THE GAME (strict check)
 		if strings.Contains(lowerMsg, championLower) {
 			// Check for "most damage in the game" or "most damage" (which implies in-game)
-			claimsMostDamageInGame := strings.Contains(lowerMsg, "most damage in the game") || 
-			                          strings.Contains(lowerMsg, "most damage") ||
-			                          strings.Contains(lowerMsg, "highest damage") ||
-			                          strings.Contains(lowerMsg, "dealt the most")
-			claimsMostDamageOnTeam := strings.Contains(lowerMsg, "most damage on") || 
-			                          strings.Contains(lowerMsg, "most damage on their team")
+			claimsMostDamageInGame := containsAny(lowerMsg, keywords.MostDamage)
+			claimsMostDamageOnTeam := containsAny(lowerMsg, keywords.MostDamageOnTeam)
 			
 			if claimsMostDamageInGame && !flags["highestDamageInGame"] {
 				// Incorrect claim - this champion doesn't have highestDamageInGame flag
@@ -950,7 +970,7 @@ func validateMessages(messages []string, gameSummaryJSON string, enableDebug boo
 				break
 			}
 			// Also check for "carried" with "damage" context
-			if strings.Contains(lowerMsg, "carried") && strings.Contains(lowerMsg, "damage") && !flags["highestDamageInGame"] && !flags["highestDamageOnTeam"] {
+			if containsAny(lowerMsg, keywords.Carried) && containsAny(lowerMsg, keywords.Damage) && !flags["highestDamageInGame"] && !flags["highestDamageOnTeam"] {
 				if enableDebug {
 					log.Printf("[DEBUG] VALIDATION: Filtering incorrect carry claim - %s claimed to carry with damage but no damage flags", champion)
 				}
@@ -959,8 +979,7 @@ func validateMessages(messages []string, gameSummaryJSON string, enableDebug boo
 			}
 			
 			// Check for healing/shielding claims
-			if (strings.Contains(lowerMsg, "healing") || strings.Contains(lowerMsg, "shielding") || strings.Contains(lowerMsg, "heal")) && 
-			   (strings.Contains(lowerMsg, "most") || strings.Contains(lowerMsg, "amazing") || strings.Contains(lowerMsg, "best")) {
+			if containsAny(lowerMsg, keywords.Healing) && containsAny(lowerMsg, keywords.Superlative) {
 				if !flags["mostHealingShielding"] {
 					if enableDebug {
 						log.Printf("[DEBUG] VALIDATION: Filtering incorrect healing/shielding claim about %s", champion)
@@ -978,8 +997,7 @@ func validateMessages(messages []string, gameSummaryJSON string, enableDebug boo
 			}
 		}
 		// Check if message claims this champion had best vision
-		if strings.Contains(lowerMsg, championLower) && 
-		   (strings.Contains(lowerMsg, "vision control") || strings.Contains(lowerMsg, "best vision") || strings.Contains(lowerMsg, "dominated vision") || strings.Contains(lowerMsg, "vision mvp") || strings.Contains(lowerMsg, "amazing vision")) {
+		if strings.Contains(lowerMsg, championLower) && containsAny(lowerMsg, keywords.Vision) {
 			if !flags["highestVisionInGame"] && !flags["highestVisionOnTeam"] {
 				// Incorrect claim - this champion doesn't have highest vision flags
 				if enableDebug {
@@ -992,10 +1010,7 @@ func validateMessages(messages []string, gameSummaryJSON string, enableDebug boo
 	}
 		
 		// Check for team color references (BLUE team, RED team, etc.)
-		hasTeamColorReference := strings.Contains(lowerMsg, "blue team") || 
-		                        strings.Contains(lowerMsg, "red team") ||
-		                        strings.Contains(lowerMsg, "team blue") ||
-		                        strings.Contains(lowerMsg, "team red")
+		hasTeamColorReference := containsAny(lowerMsg, keywords.TeamColors)
 		
 		if enableDebug && (hasInvalidChampion || hasIncorrectDamageClaim || hasIncorrectVisionClaim || hasIncorrectHealingClaim || hasTeamColorReference) {
 			log.Printf("[DEBUG] VALIDATION: Filtering message - invalidChamp=%v, damageClaim=%v, visionClaim=%v, healingClaim=%v, teamColor=%v: %s", hasInvalidChampion, hasIncorrectDamageClaim, hasIncorrectVisionClaim, hasIncorrectHealingClaim, hasTeamColorReference, msg)
@@ -1003,8 +1018,8 @@ func validateMessages(messages []string, gameSummaryJSON string, enableDebug boo
 		
 		if !hasInvalidChampion && !hasIncorrectDamageClaim && !hasIncorrectVisionClaim && !hasIncorrectHealingClaim && !hasTeamColorReference {
 			validated = append(validated, msg)
-		} else if hasTeamColorReference {
-			// Try to fix by removing team color references
+		} else if hasTeamColorReference && languageFor(language) == languageFor(DefaultLanguage) {
+			// Try to fix by removing team color references (English only; other languages drop the message)
 			fixed := strings.ReplaceAll(msg, "BLUE team", "team")
 			fixed = strings.ReplaceAll(fixed, "RED team", "team")
 			fixed = strings.ReplaceAll(fixed, "team BLUE", "team")
@@ -1028,51 +1043,12 @@ func validateMessages(messages []string, gameSummaryJSON string, enableDebug boo
 
 // generateContextualFallbackMessages creates fallback messages based on game context
 // This provides better fallbacks than generic messages when LLM fails
-func generateContextualFallbackMessages(gameSummaryJSON string) []string {
-	var gameSummary map[string]interface{}
-	if err := json.Unmarshal([]byte(gameSummaryJSON), &gameSummary); err != nil {
+func generateContextualFallbackMessages(gameSummaryJSON string, llmSettings *config.LLMSettings) []string {
+	var summary analyzer.GameSummary
+	if err := json.Unmarshal([]byte(gameSummaryJSON), &summary); err != nil {
 		// If we can't parse, use generic fallbacks
-		return []string{
-			"ggwp everyone, thanks for the game!",
-			"Nice effort team, gl in your next games!",
-		}
-	}
-	
-	messages := []string{}
-	
-	// Check for AFK situations
-	afkOnMyTeam, _ := gameSummary["afkOnMyTeam"].(bool)
-	afkOnEnemyTeam, _ := gameSummary["afkOnEnemyTeam"].(bool)
-	winningTeam, _ := gameSummary["winningTeam"].(string)
-	myTeam, _ := gameSummary["myTeam"].(string)
-	didWin := winningTeam == myTeam
-	
-	if afkOnMyTeam && didWin {
-		messages = append(messages, "Great job team, that was tough playing 4v5!")
-		messages = append(messages, "Sorry for the AFK, opponents - you played well!")
-	} else if afkOnMyTeam && !didWin {
-		messages = append(messages, "Good effort team despite the disadvantage!")
-	} else if afkOnEnemyTeam {
-		messages = append(messages, "Sorry for the scuffed game, gl next everyone!")
-	} else {
-		if didWin {
-			messages = append(messages, "ggwp everyone, thanks for the game!")
-		} else {
-			// More casual tone for losses
-			messages = append(messages, "Awe, dang. Sucks to lose, but ggwp everyone!")
-		}
+		return FallbackMessages(llmSettings.Language)
 	}
-	
-	// Add a second message if we don't have enough
-	if len(messages) < 2 {
-		if didWin {
-			messages = append(messages, "Well played everyone!")
-		} else {
-			// Casual, supportive tone for losses focusing on individual highlights
-			messages = append(messages, "Rough one, but some great plays out there!")
-		}
-	}
-	
-	return messages
+	return SuggestionTexts(OfflineSuggestions(&summary, llmSettings))
 }
 
@@ -0,0 +1,358 @@
+package llm
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"lol-kind-bot/analyzer"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ExamplesFileName is the user's own example messages, kept next to config.json
+const ExamplesFileName = "examples.json"
+
+// Scenario tags describe the whole game. An example that has any of them is only used
+// when every one applies; other tags (player tags such as hard_carry) only add relevance.
+const (
+	ScenarioStandard     = "standard"            // No AFKs
+	ScenarioAFKMyLoss    = "afk_my_team_loss"    // AFK on our team and we lost
+	ScenarioAFKMyWin     = "afk_my_team_win"     // AFK on our team and we still won
+	ScenarioAFKEnemyWin  = "afk_enemy_team_win"  // AFK on the enemy team and we won
+	ScenarioAFKEnemyLoss = "afk_enemy_team_loss" // AFK on the enemy team and they still won
+	ScenarioAFKBoth      = "afk_both"            // AFKs on both teams
+	ScenarioStompWin     = "stomp_win"
+	ScenarioStompLoss    = "stomp_loss"
+	ScenarioComeback     = "comeback"
+	ScenarioCloseGame    = "close_game"
+	ScenarioWin          = "win"  // The praised player's team won (our team for whole-game prompts)
+	ScenarioLoss         = "loss" // The praised player's team lost
+)
+
+var scenarioTags = map[string]bool{
+	ScenarioStandard: true, ScenarioAFKMyLoss: true, ScenarioAFKMyWin: true, ScenarioAFKEnemyWin: true,
+	ScenarioAFKEnemyLoss: true, ScenarioAFKBoth: true, ScenarioStompWin: true, ScenarioStompLoss: true,
+	ScenarioComeback: true, ScenarioCloseGame: true, ScenarioWin: true, ScenarioLoss: true,
+}
+
+// copiedExampleGuidance is added when a generated message copied an example
+const copiedExampleGuidance = "Your last attempt copied one of the example messages. Write something original in your own words."
+
+const (
+	maxPromptExamples = 4 // Examples shown in the whole-game prompt
+	maxPlayerExamples = 2 // Library examples shown when writing one player's shout-out
+)
+
+//go:embed examples.json
+var builtinExamplesJSON []byte
+
+// Example is a sample message for the scenarios and player tags it is tagged with.
+// "[champion]" stands for the praised champion.
+type Example struct {
+	Text string   `json:"text"`
+	Tags []string `json:"tags"`
+}
+
+// ExampleLibrary is the set of example messages the prompt builders pick from
+type ExampleLibrary struct {
+	Examples []Example
+}
+
+var (
+	exampleLibraryMu sync.RWMutex
+	exampleLibrary   = DefaultExampleLibrary()
+)
+
+// DefaultExampleLibrary returns the built-in examples
+func DefaultExampleLibrary() *ExampleLibrary {
+	var examples []Example
+	if err := json.Unmarshal(builtinExamplesJSON, &examples); err != nil {
+		panic(fmt.Sprintf("invalid built-in examples: %v", err)) // Embedded file; caught by any run
+	}
+	return &ExampleLibrary{Examples: examples}
+}
+
+// ExamplesPathFor returns the user's examples file path for a given config path
+func ExamplesPathFor(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), ExamplesFileName)
+}
+
+// LoadExampleLibrary returns the built-in examples plus the user's examples from path.
+// A missing file just means there are no user examples.
+func LoadExampleLibrary(path string) (*ExampleLibrary, error) {
+	library := DefaultExampleLibrary()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return library, nil
+		}
+		return library, fmt.Errorf("failed to read examples file: %w", err)
+	}
+
+	var custom []Example
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return library, fmt.Errorf("failed to parse examples file %s: %w", path, err)
+	}
+	for i, example := range custom {
+		if strings.TrimSpace(example.Text) == "" {
+			return library, fmt.Errorf("examples file %s: example %d has no text", path, i)
+		}
+	}
+
+	// User examples come first so they win ties
+	library.Examples = append(custom, library.Examples...)
+	return library, nil
+}
+
+// SetExampleLibrary replaces the examples used by BuildPrompt and the agentic message prompt
+func SetExampleLibrary(library *ExampleLibrary) {
+	exampleLibraryMu.Lock()
+	defer exampleLibraryMu.Unlock()
+	exampleLibrary = library
+}
+
+// currentExampleLibrary returns the examples in use
+func currentExampleLibrary() *ExampleLibrary {
+	exampleLibraryMu.RLock()
+	defer exampleLibraryMu.RUnlock()
+	return exampleLibrary
+}
+
+// GameScenarios returns the scenario tags for a game, from our team's point of view
+func GameScenarios(summary *analyzer.GameSummary) []string {
+	won := summary.MyTeamWon()
+	var scenarios []string
+
+	switch {
+	case summary.AfkOnMyTeam && summary.AfkOnEnemyTeam:
+		scenarios = append(scenarios, ScenarioAFKBoth)
+	case summary.AfkOnMyTeam && won:
+		scenarios = append(scenarios, ScenarioAFKMyWin)
+	case summary.AfkOnMyTeam:
+		scenarios = append(scenarios, ScenarioAFKMyLoss)
+	case summary.AfkOnEnemyTeam && won:
+		scenarios = append(scenarios, ScenarioAFKEnemyWin)
+	case summary.AfkOnEnemyTeam:
+		scenarios = append(scenarios, ScenarioAFKEnemyLoss)
+	default:
+		scenarios = append(scenarios, ScenarioStandard)
+	}
+
+	if summary.WasStomp {
+		if won {
+			scenarios = append(scenarios, ScenarioStompWin)
+		} else {
+			scenarios = append(scenarios, ScenarioStompLoss)
+		}
+	}
+	if summary.IsComeback {
+		scenarios = append(scenarios, ScenarioComeback)
+	}
+	if summary.WasClose {
+		scenarios = append(scenarios, ScenarioCloseGame)
+	}
+	return scenarios
+}
+
+// gameExampleTags returns the tags that make an example relevant to a whole-game prompt
+func gameExampleTags(summary *analyzer.GameSummary) []string {
+	tags := GameScenarios(summary)
+	if summary.MyTeamWon() {
+		tags = append(tags, ScenarioWin)
+	} else {
+		tags = append(tags, ScenarioLoss)
+	}
+	for _, player := range summary.Players {
+		if !player.Afk {
+			tags = append(tags, player.Tags...)
+		}
+	}
+	return tags
+}
+
+// playerExampleTags returns the tags that make an example relevant to one player's shout-out.
+// Win, loss and stomps are from the player's side, which may be the enemy team.
+func playerExampleTags(summary *analyzer.GameSummary, player analyzer.PlayerSummary) []string {
+	won := player.Team == summary.WinningTeam
+	var tags []string
+	for _, scenario := range GameScenarios(summary) {
+		if scenario == ScenarioStompWin || scenario == ScenarioStompLoss {
+			scenario = ScenarioStompLoss
+			if won {
+				scenario = ScenarioStompWin
+			}
+		}
+		tags = append(tags, scenario)
+	}
+	if won {
+		tags = append(tags, ScenarioWin)
+	} else {
+		tags = append(tags, ScenarioLoss)
+	}
+	return append(tags, player.Tags...)
+}
+
+// tagWeight is how much a matching tag makes an example relevant: specific scenarios matter
+// most, then player tags, then the generic standard/win/loss
+func tagWeight(tag string) int {
+	switch {
+	case tag == ScenarioStandard || tag == ScenarioWin || tag == ScenarioLoss:
+		return 1
+	case scenarioTags[tag]:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// Pick returns up to n examples most relevant to the given tags. Examples with a scenario
+// tag that doesn't apply are skipped, as are examples matching none of the tags.
+func (l *ExampleLibrary) Pick(tags []string, n int) []Example {
+	if l == nil || n <= 0 {
+		return nil
+	}
+
+	context := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		context[tag] = true
+	}
+
+	type scored struct {
+		example Example
+		score   int
+	}
+	var candidates []scored
+	for _, example := range l.Examples {
+		score := 0
+		applies := true
+		for _, tag := range example.Tags {
+			switch {
+			case context[tag]:
+				score += tagWeight(tag)
+			case scenarioTags[tag]:
+				applies = false
+			}
+		}
+		if applies && score > 0 {
+			candidates = append(candidates, scored{example, score})
+		}
+	}
+
+	// Pick the best remaining example each round, preferring ones that cover tags the picked
+	// examples don't, so a stomp example and a hard_carry example beat two stomp examples.
+	// Ties keep library order (user examples first).
+	picked := make([]Example, 0, n)
+	effective := func(c scored) int {
+		for _, p := range picked {
+			if sharesTag(c.example, p) {
+				c.score--
+			}
+		}
+		return c.score
+	}
+	for len(picked) < n && len(candidates) > 0 {
+		best := 0
+		for i, c := range candidates {
+			if effective(c) > effective(candidates[best]) {
+				best = i
+			}
+		}
+		picked = append(picked, candidates[best].example)
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+	return picked
+}
+
+// sharesTag reports whether two examples have a tag in common
+func sharesTag(a, b Example) bool {
+	for _, x := range a.Tags {
+		for _, y := range b.Tags {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copiesExample reports whether a generated message is one of the examples (or contains
+// one) rather than an original message
+func (l *ExampleLibrary) copiesExample(message string) bool {
+	if l == nil {
+		return false
+	}
+	normalized := normalizeForComparison(message)
+	if normalized == "" {
+		return false
+	}
+	for _, example := range l.Examples {
+		// Compare the parts around the champion placeholder, which the model fills in
+		for _, part := range strings.Split(example.Text, "[champion]") {
+			part = normalizeForComparison(part)
+			if part == normalized || (len(part) >= 25 && strings.Contains(normalized, part)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeForComparison lowercases text and drops punctuation and extra spaces
+func normalizeForComparison(text string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r > 127:
+			if space && sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteRune(r)
+			space = false
+		default:
+			space = true
+		}
+	}
+	return sb.String()
+}
+
+// dropCopiedExamples removes messages that copy an example
+func dropCopiedExamples(messages []string, enableDebug bool) []string {
+	library := currentExampleLibrary()
+	kept := messages[:0]
+	for _, message := range messages {
+		if library.copiesExample(message) {
+			if enableDebug {
+				log.Printf("[DEBUG] Dropped message copied from the examples: %s", message)
+			}
+			continue
+		}
+		kept = append(kept, message)
+	}
+	return kept
+}
+
+// formatExamples builds the prompt block listing example messages (empty if there are none)
+func formatExamples(examples []Example) string {
+	if len(examples) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("EXAMPLE MESSAGES FROM SIMILAR GAMES (they show the style only - write your own wording, never copy or lightly reword them; [champion] stands for a champion's name):\n")
+	for _, example := range examples {
+		sb.WriteString(fmt.Sprintf("- %s\n", example.Text))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// exampleMessages builds the prompt blocks of library examples and messages we liked for a player's shout-out
+func (as *AgenticSystem) exampleMessages(player analyzer.PlayerSummary) string {
+	examples := currentExampleLibrary().Pick(playerExampleTags(as.gameSummary, player), maxPlayerExamples)
+	return formatExamples(examples) + as.likedMessages(player)
+}
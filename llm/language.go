@@ -0,0 +1,351 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultLanguage is used for unknown languages and locales
+const DefaultLanguage = "en"
+
+// fallbackMessages are the canned messages used when the LLM gives us nothing usable
+type fallbackMessages struct {
+	Generic       []string // When the game summary can't be read
+	AFKMyTeamWin  []string
+	AFKMyTeamLoss []string
+	AFKEnemyTeam  []string
+	Win           string
+	Loss          string
+	SecondWin     string // Added when only one message applies
+	SecondLoss    string
+}
+
+// claimKeywords are the phrases the validators look for, in one language (lowercase)
+type claimKeywords struct {
+	MostDamage       []string // Claims of the most damage in the game
+	MostDamageOnTeam []string // Claims of the most damage on a team
+	Carried          []string // "carried", checked together with Damage
+	Damage           []string
+	Healing          []string // Healing or shielding, checked together with Superlative
+	Superlative      []string
+	Vision           []string // Claims of the best vision
+	TeamColors       []string // "blue team", "red team", ...
+	Win              []string // Victory language that must not be used for a losing player
+}
+
+// chatPhrases classify incoming post-game chat and answer it, and open champion select, in one language. Keywords are lowercase.
+type chatPhrases struct {
+	GG            []string
+	Praise        []string
+	Question      []string // Words a question starts with; a "?" anywhere also counts
+	Toxic         []string
+	ReplyGG       []string
+	ReplyPraise   []string
+	ReplyQuestion []string
+	ReplyToxic    []string // De-escalating replies
+	Openers       []string // Good luck wishes for champion select
+}
+
+// languagePack is everything needed to generate and check messages in one language
+type languagePack struct {
+	Name      string // Language name for the prompt, e.g. "Spanish (español)"
+	Slang     string // How players in this locale talk in post-game chat
+	Fallbacks fallbackMessages
+	Keywords  claimKeywords
+	Chat      chatPhrases
+}
+
+var languagePacks = map[string]*languagePack{
+	"en": {
+		Name:  "English",
+		Slang: `"gg", "wp", "ggwp", "ty", "gj", "nice", "insane", "cracked", "clutch", "lol"`,
+		Fallbacks: fallbackMessages{
+			Generic:       []string{"ggwp everyone, thanks for the game!", "Nice effort team, gl in your next games!"},
+			AFKMyTeamWin:  []string{"Great job team, that was tough playing 4v5!", "Sorry for the AFK, opponents - you played well!"},
+			AFKMyTeamLoss: []string{"Good effort team despite the disadvantage!"},
+			AFKEnemyTeam:  []string{"Sorry for the scuffed game, gl next everyone!"},
+			Win:           "ggwp everyone, thanks for the game!",
+			Loss:          "Awe, dang. Sucks to lose, but ggwp everyone!",
+			SecondWin:     "Well played everyone!",
+			SecondLoss:    "Rough one, but some great plays out there!",
+		},
+		Keywords: claimKeywords{
+			MostDamage:       []string{"most damage in the game", "most damage", "highest damage", "dealt the most"},
+			MostDamageOnTeam: []string{"most damage on", "most damage on their team"},
+			Carried:          []string{"carried"},
+			Damage:           []string{"damage"},
+			Healing:          []string{"healing", "shielding", "heal"},
+			Superlative:      []string{"most", "amazing", "best"},
+			Vision:           []string{"vision control", "best vision", "dominated vision", "vision mvp", "amazing vision"},
+			TeamColors:       []string{"blue team", "red team", "team blue", "team red"},
+			Win:              []string{"helped secure the win", "secured the win", "helped win", "great win", "secured the victory", "contributed to victory", "contributed to the win", "helped secure victory", "victory", "won the game", "winning"},
+		},
+		Chat: chatPhrases{
+			GG:            []string{"gg", "ggs", "ggwp", "good game"},
+			Praise:        []string{"wp", "well played", "gj", "good job", "nice", "great", "insane", "carried", "ty", "thanks", "thank you", "respect", "cracked", "+rep", "fun", "what a game"},
+			Question:      []string{"how", "why", "what", "who", "where", "when", "which", "can you", "do you", "did you", "anyone"},
+			Toxic:         []string{"report", "noob", "trash", "garbage", "useless", "uninstall", "inting", "inter", "feeder", "feeding", "ff", "ez", "diff", "idiot", "stupid", "braindead", "worst", "loser", "kys"},
+			ReplyGG:       []string{"gg wp!", "ggs, thanks for the game"},
+			ReplyPraise:   []string{"ty! you played really well too", "thanks, gg!"},
+			ReplyQuestion: []string{"good question, honestly not sure", "happy to chat, feel free to add me"},
+			ReplyToxic:    []string{"all good, tough game for everyone. gl next one", "no worries, we'll get the next one", "let's keep it friendly, gg"},
+			Openers:       []string{"gl hf everyone!", "gl hf, let's have a fun one", "good luck all, let's win this"},
+		},
+	},
+	"es": {
+		Name:  "Spanish (español)",
+		Slang: `"gg", "wp", "bien jugado", "crack", "tremendo", "qué jugada", "una locura", "jajaja" - neutral Spanish that reads naturally in Spain and Latin America`,
+		Fallbacks: fallbackMessages{
+			Generic:       []string{"ggwp a todos, ¡gracias por la partida!", "Buen esfuerzo equipo, ¡suerte en las próximas!"},
+			AFKMyTeamWin:  []string{"¡Buen trabajo equipo, qué difícil jugar 4v5!", "Perdón por el AFK, rivales - ¡jugaron muy bien!"},
+			AFKMyTeamLoss: []string{"¡Buen esfuerzo equipo a pesar de la desventaja!"},
+			AFKEnemyTeam:  []string{"Perdón por la partida rara, ¡suerte a todos!"},
+			Win:           "ggwp a todos, ¡gracias por la partida!",
+			Loss:          "Uf, duele perder, pero ¡ggwp a todos!",
+			SecondWin:     "¡Bien jugado todos!",
+			SecondLoss:    "Partida dura, ¡pero hubo grandes jugadas!",
+		},
+		Keywords: claimKeywords{
+			MostDamage:       []string{"más daño", "mayor daño", "daño más alto"},
+			MostDamageOnTeam: []string{"más daño del equipo", "más daño de su equipo", "más daño en su equipo"},
+			Carried:          []string{"carreó", "carreaste", "carreo", "carry"},
+			Damage:           []string{"daño"},
+			Healing:          []string{"curación", "curaciones", "curas", "escudos"},
+			Superlative:      []string{"más", "increíble", "mejor"},
+			Vision:           []string{"control de visión", "mejor visión", "visión increíble", "mvp de visión"},
+			TeamColors:       []string{"equipo azul", "equipo rojo"},
+			Win:              []string{"victoria", "ganamos", "ganaron", "ganar la partida", "ganó la partida"},
+		},
+		Chat: chatPhrases{
+			GG:            []string{"gg", "ggwp", "buena partida"},
+			Praise:        []string{"wp", "bien jugado", "buen trabajo", "crack", "tremendo", "gracias", "grande", "bestia", "nice"},
+			Question:      []string{"cómo", "como", "por qué", "qué", "quién", "dónde", "cuándo"},
+			Toxic:         []string{"report", "reporten", "manco", "noob", "basura", "inútil", "malo", "ff", "ez"},
+			ReplyGG:       []string{"gg wp!", "gg, gracias por la partida"},
+			ReplyPraise:   []string{"¡gracias! tú también jugaste muy bien", "gracias, gg!"},
+			ReplyQuestion: []string{"buena pregunta, la verdad no sé", "si quieres agrégame y hablamos"},
+			ReplyToxic:    []string{"tranqui, partida difícil para todos. suerte en la próxima", "todo bien, la próxima la ganamos", "mantengámoslo amistoso, gg"},
+			Openers:       []string{"suerte a todos, a disfrutar!", "gl hf equipo", "vamos con todo, suerte!"},
+		},
+	},
+	"pt-BR": {
+		Name:  "Brazilian Portuguese (português do Brasil)",
+		Slang: `"gg", "wp", "jogou muito", "brabo", "monstro", "mito", "amassou", "que jogada", "kkkk"`,
+		Fallbacks: fallbackMessages{
+			Generic:       []string{"ggwp galera, valeu pela partida!", "Bom esforço time, boa sorte nas próximas!"},
+			AFKMyTeamWin:  []string{"Mandaram bem time, jogar 4v5 foi difícil!", "Foi mal pelo AFK, pessoal do outro time - vocês jogaram bem!"},
+			AFKMyTeamLoss: []string{"Bom esforço time, mesmo com a desvantagem!"},
+			AFKEnemyTeam:  []string{"Foi mal pela partida bugada, boa sorte a todos!"},
+			Win:           "ggwp galera, valeu pela partida!",
+			Loss:          "Poxa, perder é chato, mas ggwp galera!",
+			SecondWin:     "Bem jogado, pessoal!",
+			SecondLoss:    "Partida difícil, mas teve jogada incrível!",
+		},
+		Keywords: claimKeywords{
+			MostDamage:       []string{"mais dano", "maior dano"},
+			MostDamageOnTeam: []string{"mais dano do time", "mais dano da equipe"},
+			Carried:          []string{"carregou", "carry"},
+			Damage:           []string{"dano"},
+			Healing:          []string{"cura", "curas", "escudo", "escudos"},
+			Superlative:      []string{"mais", "incrível", "melhor"},
+			Vision:           []string{"controle de visão", "melhor visão", "visão incrível"},
+			TeamColors:       []string{"time azul", "time vermelho", "equipe azul", "equipe vermelha"},
+			Win:              []string{"vitória", "ganhamos", "vencemos", "venceu", "ganhou a partida"},
+		},
+		Chat: chatPhrases{
+			GG:            []string{"gg", "ggwp", "boa partida"},
+			Praise:        []string{"wp", "jogou muito", "mandou bem", "brabo", "monstro", "mito", "valeu", "obrigado", "nice"},
+			Question:      []string{"como", "por que", "qual", "quem", "onde", "quando"},
+			Toxic:         []string{"report", "reporta", "lixo", "noob", "inútil", "ruim", "horrível", "ff", "ez"},
+			ReplyGG:       []string{"gg wp!", "gg, valeu pela partida"},
+			ReplyPraise:   []string{"valeu! você jogou muito também", "obrigado, gg!"},
+			ReplyQuestion: []string{"boa pergunta, sinceramente não sei", "se quiser me adiciona e a gente conversa"},
+			ReplyToxic:    []string{"tranquilo, partida difícil pra todo mundo. boa sorte na próxima", "de boa, a próxima a gente ganha", "vamos manter a paz, gg"},
+			Openers:       []string{"boa sorte pessoal, bora!", "gl hf time", "bora ganhar essa, boa sorte!"},
+		},
+	},
+	"ko": {
+		Name:  "Korean (한국어)",
+		Slang: `"ㅅㄱ" / "수고하셨습니다" (the usual gg), "잘하시네요", "캐리 감사합니다", "ㄷㄷ", "폼 미쳤다", "ㅋㅋㅋ" - use polite speech (존댓말), since these are strangers`,
+		Fallbacks: fallbackMessages{
+			Generic:       []string{"모두 수고하셨습니다, 즐거운 게임이었어요!", "다들 고생 많으셨어요, 다음 판도 화이팅!"},
+			AFKMyTeamWin:  []string{"4대5로 힘들었는데 다들 정말 잘하셨어요!", "탈주 미안해요 상대팀분들, 정말 잘하셨어요!"},
+			AFKMyTeamLoss: []string{"불리한 상황에서도 다들 끝까지 수고하셨어요!"},
+			AFKEnemyTeam:  []string{"게임이 꼬여서 아쉽네요, 다들 수고하셨어요!"},
+			Win:           "모두 수고하셨습니다, 즐거운 게임이었어요!",
+			Loss:          "아쉽게 졌지만 다들 수고하셨습니다!",
+			SecondWin:     "다들 잘하셨어요!",
+			SecondLoss:    "힘든 판이었지만 멋진 플레이 많았어요!",
+		},
+		Keywords: claimKeywords{
+			MostDamage:       []string{"최고 딜", "최다 딜", "딜량 1등", "가장 많은 피해"},
+			MostDamageOnTeam: []string{"팀 내 최고 딜", "팀에서 가장 많은 피해"},
+			Carried:          []string{"캐리"},
+			Damage:           []string{"딜", "피해"},
+			Healing:          []string{"힐", "치유", "보호막", "쉴드"},
+			Superlative:      []string{"최고", "가장", "엄청난"},
+			Vision:           []string{"시야 장악", "최고의 시야", "시야 점수 1등"},
+			TeamColors:       []string{"블루팀", "레드팀", "블루 팀", "레드 팀"},
+			Win:              []string{"승리", "이겼", "이긴"},
+		},
+		Chat: chatPhrases{
+			GG:            []string{"gg", "ㅅㄱ", "수고"},
+			Praise:        []string{"wp", "잘하시", "잘하셨", "캐리", "감사", "ㄳ", "ㄷㄷ", "폼 미쳤"},
+			Question:      []string{"왜", "어떻게", "뭐", "누구", "언제"},
+			Toxic:         []string{"신고", "트롤", "던지", "못하", "패작", "ff", "ez"},
+			ReplyGG:       []string{"수고하셨습니다!", "ㅅㄱㅅㄱ 즐거웠어요"},
+			ReplyPraise:   []string{"감사합니다! 님도 정말 잘하셨어요", "감사해요, 수고하셨습니다!"},
+			ReplyQuestion: []string{"좋은 질문이네요, 저도 잘 모르겠어요", "궁금하시면 친추 주세요"},
+			ReplyToxic:    []string{"괜찮아요, 다들 힘든 판이었어요. 다음 판 화이팅", "다음 판은 이길 거예요", "좋게좋게 가요, 수고하셨습니다"},
+			Openers:       []string{"다들 화이팅!", "즐겜해요 gl hf", "이번 판 잘해봐요!"},
+		},
+	},
+	"de": {
+		Name:  "German (Deutsch)",
+		Slang: `"gg", "wp", "stark gespielt", "sauber", "krass", "Ehrenmann", "nice", "lol" - use the informal "du"/"ihr"`,
+		Fallbacks: fallbackMessages{
+			Generic:       []string{"ggwp alle, danke fürs Spiel!", "Gute Leistung Team, viel Glück in euren nächsten Spielen!"},
+			AFKMyTeamWin:  []string{"Starke Leistung Team, 4v5 war echt hart!", "Sorry für den AFK, Gegner - ihr habt gut gespielt!"},
+			AFKMyTeamLoss: []string{"Guter Einsatz Team trotz des Nachteils!"},
+			AFKEnemyTeam:  []string{"Sorry für das verkorkste Spiel, viel Glück euch allen!"},
+			Win:           "ggwp alle, danke fürs Spiel!",
+			Loss:          "Schade, verlieren ist blöd, aber ggwp alle!",
+			SecondWin:     "Gut gespielt, alle!",
+			SecondLoss:    "Hartes Spiel, aber ein paar starke Plays dabei!",
+		},
+		Keywords: claimKeywords{
+			MostDamage:       []string{"meisten schaden", "höchsten schaden"},
+			MostDamageOnTeam: []string{"meisten schaden im team", "meisten schaden in seinem team"},
+			Carried:          []string{"gecarried", "gecarryt", "getragen"},
+			Damage:           []string{"schaden"},
+			Healing:          []string{"heilung", "schilde", "heal"},
+			Superlative:      []string{"meisten", "unglaublich", "beste"},
+			Vision:           []string{"sichtkontrolle", "beste sicht", "vision mvp"},
+			TeamColors:       []string{"blaues team", "rotes team", "team blau", "team rot"},
+			Win:              []string{"sieg", "gewonnen", "gewinnen"},
+		},
+		Chat: chatPhrases{
+			GG:            []string{"gg", "ggwp", "gutes spiel"},
+			Praise:        []string{"wp", "gut gespielt", "stark", "sauber", "krass", "danke", "nice", "ehrenmann"},
+			Question:      []string{"wie", "warum", "was", "wer", "wo", "wann"},
+			Toxic:         []string{"report", "noob", "müll", "nutzlos", "schlecht", "idiot", "ff", "ez"},
+			ReplyGG:       []string{"gg wp!", "gg, danke fürs Spiel"},
+			ReplyPraise:   []string{"danke! du hast auch richtig gut gespielt", "danke, gg!"},
+			ReplyQuestion: []string{"gute Frage, ehrlich gesagt keine Ahnung", "add mich gern, dann quatschen wir"},
+			ReplyToxic:    []string{"alles gut, war für alle ein hartes Spiel. viel Glück im nächsten", "kein Stress, das nächste holen wir", "lass uns fair bleiben, gg"},
+			Openers:       []string{"viel Glück allen, gl hf!", "gl hf Team", "auf geht's, lasst uns Spaß haben!"},
+		},
+	},
+	"fr": {
+		Name:  "French (français)",
+		Slang: `"gg", "wp", "bien joué", "bg", "masterclass", "énorme", "trop fort", "mdr" - use the informal "tu"/"vous" of chat`,
+		Fallbacks: fallbackMessages{
+			Generic:       []string{"ggwp tout le monde, merci pour la game !", "Bel effort l'équipe, bonne chance pour les prochaines !"},
+			AFKMyTeamWin:  []string{"Bien joué l'équipe, c'était dur à 4v5 !", "Désolé pour l'AFK, les adversaires - vous avez bien joué !"},
+			AFKMyTeamLoss: []string{"Bel effort l'équipe malgré le désavantage !"},
+			AFKEnemyTeam:  []string{"Désolé pour la game bancale, bonne chance à tous !"},
+			Win:           "ggwp tout le monde, merci pour la game !",
+			Loss:          "Dommage, perdre c'est nul, mais ggwp tout le monde !",
+			SecondWin:     "Bien joué tout le monde !",
+			SecondLoss:    "Game difficile, mais il y a eu de belles actions !",
+		},
+		Keywords: claimKeywords{
+			MostDamage:       []string{"plus de dégâts", "dégâts les plus élevés"},
+			MostDamageOnTeam: []string{"plus de dégâts de l'équipe", "plus de dégâts dans l'équipe", "plus de dégâts de son équipe"},
+			Carried:          []string{"carry", "porté"},
+			Damage:           []string{"dégâts"},
+			Healing:          []string{"soins", "boucliers", "heal"},
+			Superlative:      []string{"plus", "incroyable", "meilleur"},
+			Vision:           []string{"contrôle de la vision", "meilleure vision", "vision incroyable"},
+			TeamColors:       []string{"équipe bleue", "équipe rouge"},
+			Win:              []string{"victoire", "gagné", "gagner"},
+		},
+		Chat: chatPhrases{
+			GG:            []string{"gg", "ggwp", "bonne game"},
+			Praise:        []string{"wp", "bien joué", "bg", "énorme", "trop fort", "merci", "masterclass", "nice"},
+			Question:      []string{"comment", "pourquoi", "quoi", "qui", "où", "quand"},
+			Toxic:         []string{"report", "noob", "nul", "poubelle", "inutile", "débile", "ff", "ez"},
+			ReplyGG:       []string{"gg wp !", "gg, merci pour la game"},
+			ReplyPraise:   []string{"merci ! t'as super bien joué aussi", "merci, gg !"},
+			ReplyQuestion: []string{"bonne question, honnêtement je sais pas", "ajoute-moi si tu veux qu'on en parle"},
+			ReplyToxic:    []string{"tout va bien, game difficile pour tout le monde. bonne chance pour la prochaine", "pas de souci, on gagnera la prochaine", "restons cool, gg"},
+			Openers:       []string{"bonne chance à tous, gl hf !", "gl hf l'équipe", "allez on la gagne, bon jeu !"},
+		},
+	},
+}
+
+// languageFor returns the pack for a language code, falling back to English.
+// Codes are matched case-insensitively, then by their base language ("es-MX" uses "es").
+func languageFor(code string) *languagePack {
+	for key, pack := range languagePacks {
+		if strings.EqualFold(key, code) {
+			return pack
+		}
+	}
+	base, _, _ := strings.Cut(strings.ReplaceAll(code, "_", "-"), "-")
+	for key, pack := range languagePacks {
+		if strings.EqualFold(key, base) {
+			return pack
+		}
+	}
+	return languagePacks[DefaultLanguage]
+}
+
+// ResolveLanguage turns the language setting into a language code. "auto" (or empty) uses the
+// League client's locale, e.g. "pt_BR" -> "pt-BR", "es_MX" -> "es"; unsupported locales use English.
+func ResolveLanguage(setting, clientLocale string) string {
+	if setting != "" && setting != "auto" {
+		return setting
+	}
+	locale := strings.ReplaceAll(clientLocale, "_", "-")
+	if locale == "" {
+		return DefaultLanguage
+	}
+	for key := range languagePacks {
+		if strings.EqualFold(key, locale) {
+			return key
+		}
+	}
+	base, _, _ := strings.Cut(locale, "-")
+	for key := range languagePacks {
+		if strings.EqualFold(key, base) {
+			return key
+		}
+	}
+	if strings.EqualFold(base, "pt") {
+		return "pt-BR" // European Portuguese players read Brazilian Portuguese fine
+	}
+	return DefaultLanguage
+}
+
+// buildLanguageInstructions returns the prompt section asking for messages in a language
+// (empty for English, which the rest of the prompt is written in)
+func buildLanguageInstructions(code string) string {
+	pack := languageFor(code)
+	if pack == languagePacks[DefaultLanguage] {
+		return ""
+	}
+	return fmt.Sprintf(`LANGUAGE:
+- Write every message in %s. Never answer in English.
+- Sound like a player from this region in post-game chat, e.g. %s.
+- Keep champion names exactly as they appear in the game data.
+- The instructions and any example messages in this prompt are in English: follow their meaning and style, but write the messages themselves only in %s.
+
+`, pack.Name, pack.Slang, pack.Name)
+}
+
+// containsAny reports whether text contains any of the phrases
+func containsAny(text string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// FallbackMessages returns the generic messages for a language, for when there is nothing better to show
+func FallbackMessages(language string) []string {
+	generic := languageFor(language).Fallbacks.Generic
+	return append([]string(nil), generic...)
+}
@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type tagsResponse struct {
+	Models []struct {
+		Name  string `json:"name"`
+		Model string `json:"model"`
+	} `json:"models"`
+}
+
+// ListModels returns the models installed on the Ollama server (via /api/tags)
+func (c *Client) ListModels() ([]string, error) {
+	tagsURL, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LLM URL %q: %w", c.URL, err)
+	}
+	tagsURL.Path = "/api/tags"
+	tagsURL.RawQuery = ""
+
+	if c.httpClient == nil {
+		c.httpClient = SharedHTTPClient
+	}
+
+	resp, err := c.httpClient.Get(tagsURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tags tagsResponse
+	if err := json.Unmarshal(body, &tags); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	models := make([]string, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		name := m.Name
+		if name == "" {
+			name = m.Model
+		}
+		models = append(models, name)
+	}
+	return models, nil
+}
+
+// HasModel reports whether model is installed. Ollama reports untagged models as "name:latest".
+func HasModel(models []string, model string) bool {
+	for _, m := range models {
+		if m == model || m == model+":latest" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"fmt"
+	"lol-kind-bot/analyzer"
+	"strings"
+)
+
+// maxExampleMessages is how many liked messages are shown to the writer as style examples
+const maxExampleMessages = 3
+
+// Preferences is what we have learned from feedback on earlier messages
+// (implemented by feedback.Preferences)
+type Preferences interface {
+	// CandidateBonus is added to a candidate's judge score when picking who to praise
+	CandidateBonus(player analyzer.PlayerSummary) float64
+	// Examples returns up to n messages we liked about similar players
+	Examples(player analyzer.PlayerSummary, n int) []string
+}
+
+// SetPreferences makes candidate ranking and message prompts use learned preferences (nil disables them)
+func (as *AgenticSystem) SetPreferences(preferences Preferences) {
+	as.preferences = preferences
+}
+
+// rankingScore is a candidate's judge score adjusted by our learned preferences
+func (as *AgenticSystem) rankingScore(t *AdvocateTestimony) float64 {
+	if as.preferences == nil || t.PlayerIndex < 0 || t.PlayerIndex >= len(as.gameSummary.Players) {
+		return t.Score
+	}
+	return t.Score + as.preferences.CandidateBonus(as.gameSummary.Players[t.PlayerIndex])
+}
+
+// likedMessages builds the prompt block of messages we liked about similar players (empty if there are none)
+func (as *AgenticSystem) likedMessages(player analyzer.PlayerSummary) string {
+	if as.preferences == nil {
+		return ""
+	}
+	examples := as.preferences.Examples(player, maxExampleMessages)
+	if len(examples) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("MESSAGES WE LIKED BEFORE (match their style and length, never copy them):\n")
+	for _, example := range examples {
+		sb.WriteString(fmt.Sprintf("- %q\n", example))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,263 @@
+package llm
+
+import (
+	"fmt"
+	"lol-kind-bot/analyzer"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Intents of an incoming post-game chat line
+const (
+	IntentGG       = "gg"
+	IntentPraise   = "praise"
+	IntentQuestion = "question"
+	IntentToxic    = "toxic"
+	IntentOther    = "other"
+)
+
+// maxReplies is how many replies are suggested for one chat line
+const maxReplies = 3
+
+// ChatReply is an incoming post-game chat line with the replies suggested for it
+type ChatReply struct {
+	MessageID string   `json:"messageId"`
+	From      string   `json:"from"` // The sender's champion, or their name if they aren't in the game summary
+	Text      string   `json:"text"`
+	Intent    string   `json:"intent"`
+	Replies   []string `json:"replies"`
+	Answered  string   `json:"answered,omitempty"` // The reply we sent, if any
+}
+
+// ClassifyChat sorts an incoming chat line into an intent. Toxic lines win over everything
+// else so they always get a calm answer; English keywords are checked for every language,
+// since "gg", "wp" and "report" are used everywhere.
+func ClassifyChat(text, language string) string {
+	normalized := strings.ToLower(strings.TrimSpace(text))
+	if normalized == "" {
+		return IntentOther
+	}
+
+	packs := []*languagePack{languageFor(language)}
+	if english := languagePacks[DefaultLanguage]; packs[0] != english {
+		packs = append(packs, english)
+	}
+	matches := func(phrases func(chatPhrases) []string) bool {
+		for _, pack := range packs {
+			if containsPhrase(normalized, phrases(pack.Chat)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case matches(func(c chatPhrases) []string { return c.Toxic }):
+		return IntentToxic
+	case strings.Contains(normalized, "?"):
+		return IntentQuestion
+	case matches(func(c chatPhrases) []string { return c.Praise }):
+		return IntentPraise
+	case matches(func(c chatPhrases) []string { return c.GG }):
+		return IntentGG
+	case startsWithPhrase(normalized, languageFor(language).Chat.Question):
+		return IntentQuestion // Checked last, so "what a game" stays praise
+	}
+	return IntentOther
+}
+
+// SuggestReplies classifies an incoming chat line and suggests replies to it. sender is the
+// player who wrote it (nil if unknown); English replies mention them and the game. Lines
+// that are neither gg, praise, a question nor toxic get no replies.
+func SuggestReplies(text string, sender *analyzer.PlayerSummary, summary *analyzer.GameSummary, language string, maxLength int) (string, []string) {
+	intent := ClassifyChat(text, language)
+	pack := languageFor(language)
+
+	var generic []string
+	switch intent {
+	case IntentGG:
+		generic = pack.Chat.ReplyGG
+	case IntentPraise:
+		generic = pack.Chat.ReplyPraise
+	case IntentQuestion:
+		generic = pack.Chat.ReplyQuestion
+	case IntentToxic:
+		generic = pack.Chat.ReplyToxic
+	default:
+		return intent, nil
+	}
+
+	var replies []string
+	if pack == languagePacks[DefaultLanguage] && summary != nil {
+		replies = contextReplies(intent, text, sender, summary)
+	}
+	replies = append(replies, generic...)
+
+	var result []string
+	for _, reply := range replies {
+		if maxLength > 0 && utf8.RuneCountInString(reply) > maxLength {
+			continue
+		}
+		if !containsString(result, reply) {
+			result = append(result, reply)
+		}
+		if len(result) == maxReplies {
+			break
+		}
+	}
+	return intent, result
+}
+
+// contextReplies are English replies built from the game: who wrote the line, who it
+// mentions and how the game went
+func contextReplies(intent, text string, sender *analyzer.PlayerSummary, summary *analyzer.GameSummary) []string {
+	me := summary.Me()
+	won := summary.MyTeamWon()
+	ally := sender != nil && summary.MyTeam != "" && sender.Team == summary.MyTeam
+	enemy := sender != nil && summary.MyTeam != "" && sender.Team != summary.MyTeam
+
+	var replies []string
+	switch intent {
+	case IntentPraise:
+		target := mentionedPlayer(text, summary)
+		if target != nil && target != me && target != sender {
+			return []string{fmt.Sprintf("agreed, %s was huge this game", target.Champion)}
+		}
+		switch {
+		case ally && highlight(sender) != "":
+			replies = append(replies, fmt.Sprintf("ty %s! %s was huge too", sender.Champion, highlight(sender)))
+		case ally:
+			replies = append(replies, fmt.Sprintf("ty %s, you played great too", sender.Champion))
+		case enemy:
+			replies = append(replies, fmt.Sprintf("ty! your %s was tough to play against", sender.Champion))
+		}
+	case IntentGG:
+		switch {
+		case enemy && won:
+			replies = append(replies, fmt.Sprintf("gg %s, you played well", sender.Champion))
+		case enemy:
+			replies = append(replies, fmt.Sprintf("gg wp, your %s was a problem all game", sender.Champion))
+		case ally && highlight(sender) != "":
+			replies = append(replies, fmt.Sprintf("gg, %s was huge", highlight(sender)))
+		case ally:
+			replies = append(replies, fmt.Sprintf("gg, nice %s!", sender.Champion))
+		}
+	case IntentToxic:
+		switch {
+		case ally && !won:
+			replies = append(replies, fmt.Sprintf("all good %s, rough one for all of us. gl next", sender.Champion))
+		case enemy && !won:
+			replies = append(replies, "gg, you played well. have a good one")
+		case ally || enemy:
+			replies = append(replies, "no hard feelings, gg and gl next game")
+		}
+	}
+	return replies
+}
+
+// highlight names the standout part of a player's game, or "" if nothing stood out
+func highlight(p *analyzer.PlayerSummary) string {
+	switch {
+	case p == nil:
+		return ""
+	case (p.HighestDamageInGame || p.HighestDamageOnTeam) && p.TotalDamageFormatted != "":
+		return "that " + p.TotalDamageFormatted + " damage"
+	case p.MostHealingShielding:
+		return "your heals and shields"
+	case p.HighestVisionInGame || p.HighestVisionOnTeam:
+		return "your vision"
+	case p.MostCCInGame || p.MostCCOnTeam:
+		return "your crowd control"
+	case p.MostTankingInGame:
+		return "your frontline"
+	case p.LivesSaved > 0:
+		return "your peel"
+	}
+	return ""
+}
+
+// mentionedPlayer returns the player whose champion a chat line names ("wp jinx", "lee sin
+// was cracked"), or nil
+func mentionedPlayer(text string, summary *analyzer.GameSummary) *analyzer.PlayerSummary {
+	var words []string
+	for _, word := range chatWords(text) {
+		words = append(words, lettersOnly(word))
+	}
+	for i := range summary.Players {
+		name := lettersOnly(summary.Players[i].Champion)
+		if name == "" {
+			continue
+		}
+		for j, word := range words {
+			if word == name || (j+1 < len(words) && word+words[j+1] == name) {
+				return &summary.Players[i]
+			}
+		}
+	}
+	return nil
+}
+
+// containsPhrase reports whether text holds any of the phrases. ASCII phrases must match whole
+// words, so "ty" doesn't match "pretty"; others (e.g. Korean, written without spaces) match anywhere.
+func containsPhrase(text string, phrases []string) bool {
+	padded := " " + strings.Join(chatWords(text), " ") + " "
+	for _, phrase := range phrases {
+		if isASCII(phrase) {
+			if strings.Contains(padded, " "+phrase+" ") {
+				return true
+			}
+		} else if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// startsWithPhrase reports whether text starts with any of the phrases as whole words
+func startsWithPhrase(text string, phrases []string) bool {
+	padded := strings.Join(chatWords(text), " ") + " "
+	for _, phrase := range phrases {
+		if strings.HasPrefix(padded, phrase+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// chatWords splits a lowercase chat line into words, dropping punctuation (but keeping "+", as in "+rep")
+func chatWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '\''
+	})
+}
+
+// lettersOnly lowercases a name and drops everything but letters ("Kai'Sa" -> "kaisa")
+func lettersOnly(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// isASCII reports whether s is plain ASCII
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// containsString reports whether list holds s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// streamChunk is one line of an Ollama NDJSON stream or the data of one server-sent event from
+// an OpenAI-style /v1/completions endpoint. Chat completions aren't supported: requests are
+// always sent as a single prompt.
+type streamChunk struct {
+	Response string `json:"response"` // Ollama
+	Done     bool   `json:"done"`
+	Error    string `json:"error"`
+	Choices  []struct {
+		Text string `json:"text"` // OpenAI-style completions
+	} `json:"choices"`
+}
+
+// text returns the text the chunk adds to the response
+func (c streamChunk) text() string {
+	text := c.Response
+	for _, choice := range c.Choices {
+		text += choice.Text
+	}
+	return text
+}
+
+// post sends a generate request for prompt. The caller must close the response body.
+func (c *Client) post(ctx context.Context, prompt string, stream bool) (*http.Response, error) {
+	jsonData, err := json.Marshal(c.newGenerateRequest(prompt, stream))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Use shared HTTP client for connection pooling and parallel requests
+	if c.httpClient == nil {
+		c.httpClient = SharedHTTPClient
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return resp, nil
+}
+
+// GenerateStream sends prompt with streaming enabled and calls onText (if set) with the text
+// generated so far after each chunk, then returns the full response. Both Ollama's
+// newline-delimited JSON and the server-sent events of an OpenAI-style /v1/completions endpoint
+// are understood. Cancelling ctx stops the generation.
+func (c *Client) GenerateStream(ctx context.Context, prompt string, onText func(text string)) (string, error) {
+	resp, err := c.post(ctx, prompt, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var response strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ":") || strings.HasPrefix(line, "event:") {
+			continue // Blank separators and SSE comments or event names
+		}
+		if data, ok := strings.CutPrefix(line, "data:"); ok {
+			line = strings.TrimSpace(data)
+			if line == "[DONE]" {
+				break
+			}
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return response.String(), fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return response.String(), fmt.Errorf("LLM stream error: %s", chunk.Error)
+		}
+
+		if text := chunk.text(); text != "" {
+			response.WriteString(text)
+			if onText != nil {
+				onText(response.String())
+			}
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return response.String(), fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	return response.String(), nil
+}
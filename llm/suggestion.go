@@ -0,0 +1,160 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Suggestion is a generated message together with why it was chosen
+type Suggestion struct {
+	Text      string             `json:"text"`
+	Champion  string             `json:"champion,omitempty"`  // Champion the message praises
+	Tone      string             `json:"tone,omitempty"`      // Tone it was written in
+	Testimony *AdvocateTestimony `json:"testimony,omitempty"` // Advocate's case, validation result and judges' score (nil for fallback messages)
+}
+
+// IsFallback reports whether the suggestion is a canned message rather than an agentic one
+func (s Suggestion) IsFallback() bool {
+	return s.Testimony == nil
+}
+
+// Reason describes in one line why the suggestion was chosen
+func (s Suggestion) Reason() string {
+	if s.Testimony == nil {
+		if s.Champion != "" {
+			return fmt.Sprintf("Template message praising %s (no LLM candidate)", s.Champion)
+		}
+		return "Fallback message (no LLM candidate)"
+	}
+
+	validation := "not validated"
+	if s.Testimony.Validated {
+		validation = "validated"
+	}
+	reason := fmt.Sprintf("Praises %s - score %.1f/10 - %s", s.Champion, s.Testimony.Score, validation)
+	if s.Testimony.ValidationNote != "" {
+		reason += ": " + s.Testimony.ValidationNote
+	}
+	return reason
+}
+
+// SuggestionTexts returns the message text of each suggestion
+func SuggestionTexts(suggestions []Suggestion) []string {
+	texts := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		texts[i] = s.Text
+	}
+	return texts
+}
+
+// praisesChampion reports whether one of the suggestions already praises a champion
+func praisesChampion(suggestions []Suggestion, champion string) bool {
+	if champion == "" {
+		return false
+	}
+	for _, s := range suggestions {
+		if s.Champion == champion {
+			return true
+		}
+	}
+	return false
+}
+
+// fallbackSuggestions wraps canned messages as suggestions
+func fallbackSuggestions(messages []string) []Suggestion {
+	suggestions := make([]Suggestion, len(messages))
+	for i, msg := range messages {
+		suggestions[i] = Suggestion{Text: msg}
+	}
+	return suggestions
+}
+
+// Rewrite generates a replacement for a suggestion praising the same player. shown are the
+// messages already on screen, which an offline replacement avoids.
+func (as *AgenticSystem) Rewrite(s Suggestion, shown []string, enableDebug bool) (Suggestion, error) {
+	guidance := fmt.Sprintf("Write a new message that is clearly different from this earlier one: %q", s.Text)
+	return as.generateFor(s, guidance, shown, enableDebug)
+}
+
+// MoreLike generates another message in the same spirit as a suggestion we liked
+func (as *AgenticSystem) MoreLike(s Suggestion, shown []string, enableDebug bool) (Suggestion, error) {
+	guidance := fmt.Sprintf("We liked this message: %q\nWrite another message in the same spirit, style and length, about the same player. Do not repeat it or reuse its wording.", s.Text)
+	return as.generateFor(s, guidance, shown, enableDebug)
+}
+
+// generateFor runs the message phase again for a suggestion's candidate with extra guidance
+func (as *AgenticSystem) generateFor(s Suggestion, guidance string, shown []string, enableDebug bool) (Suggestion, error) {
+	candidate := s.Testimony
+	if candidate == nil {
+		return as.generateForTemplate(s, guidance, shown, enableDebug)
+	}
+	if candidate.PlayerIndex < 0 || candidate.PlayerIndex >= len(as.gameSummary.Players) {
+		return Suggestion{}, fmt.Errorf("candidate %s is not in this game", candidate.Champion)
+	}
+	return as.messageFor(candidate, guidance, enableDebug)
+}
+
+// generateForTemplate writes a message for the champion a template message praises with a single
+// prompt, skipping the advocates and judges. If the LLM can't write one (or the message is about
+// the whole game), it rotates to another offline message instead, for the same champion if possible.
+func (as *AgenticSystem) generateForTemplate(s Suggestion, guidance string, shown []string, enableDebug bool) (Suggestion, error) {
+	if index := as.playerIndex(s.Champion); index >= 0 {
+		player := as.gameSummary.Players[index]
+		candidate := &AdvocateTestimony{
+			PlayerIndex:    index,
+			Champion:       player.Champion,
+			Testimony:      fmt.Sprintf("An offline template picked %s for this message: %q", player.Champion, s.Text),
+			KeyPoints:      playerTemplateTags(as.gameSummary, player),
+			ValidationNote: "written from a template message, not judged",
+		}
+		suggestion, err := as.messageFor(candidate, guidance, enableDebug)
+		if err == nil {
+			return suggestion, nil
+		}
+		log.Printf("[AGENTIC] %v; using another offline message", err)
+	}
+
+	if alternative, ok := AlternativeSuggestion(as.gameSummary, as.llmSettings, s, shown); ok {
+		return alternative, nil
+	}
+	return Suggestion{}, fmt.Errorf("no other offline message for this game")
+}
+
+// playerIndex finds the player on a champion, preferring anyone but us (-1 if none)
+func (as *AgenticSystem) playerIndex(champion string) int {
+	if champion == "" {
+		return -1
+	}
+	found := -1
+	me := as.gameSummary.Me()
+	for i := range as.gameSummary.Players {
+		if as.gameSummary.Players[i].Champion != champion {
+			continue
+		}
+		if &as.gameSummary.Players[i] != me {
+			return i
+		}
+		found = i
+	}
+	return found
+}
+
+// messageFor writes one message for a candidate and checks it against the game's outcome
+func (as *AgenticSystem) messageFor(candidate *AdvocateTestimony, guidance string, enableDebug bool) (Suggestion, error) {
+	gameSummaryJSON, err := json.MarshalIndent(as.gameSummary, "", "  ")
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("failed to marshal game summary: %w", err)
+	}
+
+	message := as.generateMessageForCandidate(candidate, string(gameSummaryJSON), guidance, enableDebug, nil)
+	if strings.TrimSpace(message) == "" {
+		return Suggestion{}, fmt.Errorf("no message generated for %s", candidate.Champion)
+	}
+	if !as.validateWinLossContext(message, candidate.PlayerIndex, enableDebug) {
+		return Suggestion{}, fmt.Errorf("generated message for %s did not match the game's outcome", candidate.Champion)
+	}
+
+	return Suggestion{Text: message, Champion: candidate.Champion, Tone: as.llmSettings.Tone, Testimony: candidate}, nil
+}
@@ -0,0 +1,448 @@
+package llm
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/config"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Player facts a template can require, on top of scenarios and player tags
+const (
+	FactHighestDamageInGame  = "highest_damage_in_game"
+	FactHighestDamageOnTeam  = "highest_damage_on_team"
+	FactMostHealingShielding = "most_healing_shielding"
+	FactHighestVisionInGame  = "highest_vision_in_game"
+	FactHighestVisionOnTeam  = "highest_vision_on_team"
+	FactMostCCInGame         = "most_cc_in_game"
+	FactMostCCOnTeam         = "most_cc_on_team"
+	FactMostTankingInGame    = "most_tanking_in_game"
+	FactSavedLives           = "saved_lives"    // Saved a teammate at least once
+	FactCriticalSaves        = "critical_saves" // Saved a teammate at low HP at least once
+	FactDeathless            = "deathless"      // No deaths with at least 5 kills + assists
+	FactHighKP               = "high_kp"        // In on 60%+ of the team's kills
+	FactAlly                 = "ally"           // On our team
+	FactEnemy                = "enemy"
+)
+
+const (
+	templateRotationWindow = 30 // A template used within this many picks is avoided while others fit
+	templateRecentPenalty  = 3  // Score taken off a recently used template
+)
+
+//go:embed templates.json
+var builtinTemplatesJSON []byte
+
+// Template is a message with slots filled from the game, e.g. "{champion's} {damage} damage carried".
+// It is only used when every one of its tags applies.
+type Template struct {
+	Text string   `json:"text"`
+	Tags []string `json:"tags"`
+}
+
+// forPlayer reports whether the template praises a player rather than the whole game
+func (t Template) forPlayer() bool {
+	return strings.Contains(t.Text, "{champion")
+}
+
+var builtinTemplates = func() []Template {
+	var templates []Template
+	if err := json.Unmarshal(builtinTemplatesJSON, &templates); err != nil {
+		panic(fmt.Sprintf("invalid built-in templates: %v", err)) // Embedded file; caught by any run
+	}
+	return templates
+}()
+
+// templateRotation remembers when each template was last used, so repeated games get different messages
+var templateRotation = struct {
+	sync.Mutex
+	picks    int
+	lastUsed map[string]int
+}{lastUsed: make(map[string]int)}
+
+// OfflineSuggestions writes messages without the LLM. English messages come from templates filled
+// with the game's stats; other languages (and games no template fits) use the canned messages.
+// It is fast enough to show while the LLM is still working.
+func OfflineSuggestions(summary *analyzer.GameSummary, settings *config.LLMSettings) []Suggestion {
+	var suggestions []Suggestion
+	if languageFor(settings.Language) == languagePacks[DefaultLanguage] {
+		suggestions = templateSuggestions(summary, settings.MaxMessages)
+	}
+
+	// Pad with the canned messages so there are always enough to pick from
+	for _, message := range cannedMessages(summary, languageFor(settings.Language).Fallbacks) {
+		if len(suggestions) >= settings.MinMessages && len(suggestions) > 0 {
+			break
+		}
+		if !hasSuggestion(suggestions, message) {
+			suggestions = append(suggestions, Suggestion{Text: message})
+		}
+	}
+	return suggestions
+}
+
+// hasSuggestion reports whether a message is already among the suggestions
+func hasSuggestion(suggestions []Suggestion, text string) bool {
+	for _, s := range suggestions {
+		if s.Text == text {
+			return true
+		}
+	}
+	return false
+}
+
+// cannedMessages picks fixed messages for the game's AFK scenario and result
+func cannedMessages(summary *analyzer.GameSummary, fallbacks fallbackMessages) []string {
+	didWin := summary.MyTeamWon()
+	messages := []string{}
+
+	if summary.AfkOnMyTeam && didWin {
+		messages = append(messages, fallbacks.AFKMyTeamWin...)
+	} else if summary.AfkOnMyTeam && !didWin {
+		messages = append(messages, fallbacks.AFKMyTeamLoss...)
+	} else if summary.AfkOnEnemyTeam {
+		messages = append(messages, fallbacks.AFKEnemyTeam...)
+	} else if didWin {
+		messages = append(messages, fallbacks.Win)
+	} else {
+		// More casual tone for losses
+		messages = append(messages, fallbacks.Loss)
+	}
+
+	// Add a second message if we don't have enough
+	if len(messages) < 2 {
+		if didWin {
+			messages = append(messages, fallbacks.SecondWin)
+		} else {
+			// Casual, supportive tone for losses focusing on individual highlights
+			messages = append(messages, fallbacks.SecondLoss)
+		}
+	}
+	return messages
+}
+
+// templateCandidate is a template filled in for one player (or the whole game)
+type templateCandidate struct {
+	template Template
+	text     string
+	champion string
+	score    int
+}
+
+// templateSuggestions fills up to n templates: at most one about the whole game, the rest
+// shout-outs for different players, most specific first
+func templateSuggestions(summary *analyzer.GameSummary, n int) []Suggestion {
+	if summary == nil || n <= 0 {
+		return nil
+	}
+
+	var suggestions []Suggestion
+	for _, c := range pickTemplates(templateCandidates(summary), n) {
+		suggestions = append(suggestions, Suggestion{Text: c.text, Champion: c.champion})
+	}
+
+	// The whole-game message reads best last, after the shout-outs
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Champion != "" && suggestions[j].Champion == ""
+	})
+	return suggestions
+}
+
+// AlternativeSuggestion writes another offline message in place of s: preferably a different
+// template about the same champion (or the whole game), else another template that fits the
+// game, else a canned message. Messages in shown are skipped. It reports false if none are left.
+func AlternativeSuggestion(summary *analyzer.GameSummary, settings *config.LLMSettings, s Suggestion, shown []string) (Suggestion, bool) {
+	if summary == nil {
+		return Suggestion{}, false
+	}
+
+	if languageFor(settings.Language) == languagePacks[DefaultLanguage] {
+		var same, other []templateCandidate
+		for _, c := range templateCandidates(summary) {
+			switch {
+			case c.text == s.Text || containsString(shown, c.text):
+			case c.champion == s.Champion:
+				same = append(same, c)
+			default:
+				other = append(other, c)
+			}
+		}
+		for _, candidates := range [][]templateCandidate{same, other} {
+			if picked := pickTemplates(candidates, 1); len(picked) > 0 {
+				return Suggestion{Text: picked[0].text, Champion: picked[0].champion}, true
+			}
+		}
+	}
+
+	for _, message := range cannedMessages(summary, languageFor(settings.Language).Fallbacks) {
+		if message != s.Text && !containsString(shown, message) {
+			return Suggestion{Text: message}, true
+		}
+	}
+	return Suggestion{}, false
+}
+
+// templateCandidates fills every template that fits the game: whole-game templates once, and
+// player templates for each player they fit (other than us and AFK players)
+func templateCandidates(summary *analyzer.GameSummary) []templateCandidate {
+	var candidates []templateCandidate
+	gameTags := tagSet(gameTemplateTags(summary))
+	me := summary.Me()
+	for _, template := range builtinTemplates {
+		if !template.forPlayer() {
+			if score, ok := templateScore(template, gameTags); ok {
+				candidates = append(candidates, templateCandidate{template: template, text: template.Text, score: score})
+			}
+			continue
+		}
+		for i := range summary.Players {
+			player := &summary.Players[i]
+			if player.Afk || player == me {
+				continue
+			}
+			score, ok := templateScore(template, tagSet(playerTemplateTags(summary, *player)))
+			if !ok {
+				continue
+			}
+			text, ok := fillTemplate(template.Text, summary, *player)
+			if !ok {
+				continue
+			}
+			candidates = append(candidates, templateCandidate{template: template, text: text, champion: player.Champion, score: score})
+		}
+	}
+	return candidates
+}
+
+// pickTemplates picks up to n filled templates, most specific first: at most one about the
+// whole game and one per champion, avoiding templates used recently
+func pickTemplates(candidates []templateCandidate, n int) []templateCandidate {
+	templateRotation.Lock()
+	defer templateRotation.Unlock()
+
+	// Avoid templates we used recently; among equals prefer the least recently used, then any
+	effective := func(c templateCandidate) int {
+		if last, ok := templateRotation.lastUsed[c.template.Text]; ok && templateRotation.picks-last < templateRotationWindow {
+			return c.score - templateRecentPenalty
+		}
+		return c.score
+	}
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := effective(candidates[i]), effective(candidates[j])
+		if a != b {
+			return a > b
+		}
+		return templateRotation.lastUsed[candidates[i].template.Text] < templateRotation.lastUsed[candidates[j].template.Text]
+	})
+
+	var picked []templateCandidate
+	usedTemplates := make(map[string]bool)
+	praised := make(map[string]bool)
+	gameMessage := false
+	for _, c := range candidates {
+		if len(picked) >= n {
+			break
+		}
+		if usedTemplates[c.template.Text] || praised[c.champion] || (c.champion == "" && gameMessage) {
+			continue
+		}
+		usedTemplates[c.template.Text] = true
+		if c.champion == "" {
+			gameMessage = true
+		} else {
+			praised[c.champion] = true
+		}
+		templateRotation.picks++
+		templateRotation.lastUsed[c.template.Text] = templateRotation.picks
+		picked = append(picked, c)
+	}
+	return picked
+}
+
+// templateScore returns how specific a template is for the tags in context, and false if one
+// of its tags doesn't apply
+func templateScore(template Template, context map[string]bool) (int, bool) {
+	score := 0
+	for _, tag := range template.Tags {
+		if !context[tag] {
+			return 0, false
+		}
+		score += tagWeight(tag)
+	}
+	return score, true
+}
+
+// gameTemplateTags returns the tags describing the whole game, from our team's point of view
+func gameTemplateTags(summary *analyzer.GameSummary) []string {
+	tags := GameScenarios(summary)
+	if summary.MyTeamWon() {
+		return append(tags, ScenarioWin)
+	}
+	return append(tags, ScenarioLoss)
+}
+
+// playerTemplateTags returns the scenarios, player tags and facts that apply to a player
+func playerTemplateTags(summary *analyzer.GameSummary, player analyzer.PlayerSummary) []string {
+	tags := playerExampleTags(summary, player)
+	facts := []struct {
+		tag   string
+		holds bool
+	}{
+		{FactHighestDamageInGame, player.HighestDamageInGame},
+		{FactHighestDamageOnTeam, player.HighestDamageOnTeam},
+		{FactMostHealingShielding, player.MostHealingShielding},
+		{FactHighestVisionInGame, player.HighestVisionInGame},
+		{FactHighestVisionOnTeam, player.HighestVisionOnTeam},
+		{FactMostCCInGame, player.MostCCInGame},
+		{FactMostCCOnTeam, player.MostCCOnTeam},
+		{FactMostTankingInGame, player.MostTankingInGame},
+		{FactSavedLives, player.LivesSaved > 0},
+		{FactCriticalSaves, player.CriticalSaves > 0},
+		{FactDeathless, player.D == 0 && player.K+player.A >= 5},
+		{FactHighKP, player.Metrics.KP >= 0.60 && player.Metrics.KP <= 1},
+		{FactAlly, player.Team == summary.MyTeam},
+		{FactEnemy, player.Team != summary.MyTeam},
+	}
+	for _, fact := range facts {
+		if fact.holds {
+			tags = append(tags, fact.tag)
+		}
+	}
+	return tags
+}
+
+// tagSet turns a tag list into a set
+func tagSet(tags []string) map[string]bool {
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[tag] = true
+	}
+	return set
+}
+
+// fillTemplate replaces a template's slots with a player's stats. It returns false if a slot
+// is unknown or has no value for this player, so we never print "0 damage".
+//
+// Slots: {champion}, {champion's} (possessive), {kda}, {damage}, {damageShare},
+// {killParticipation}, {vision}, {healingShielding}, {mitigated}, and counts such as
+// {livesSaved:time} ("once", "twice", "3 times") or {kills:kill} ("1 kill", "5 kills").
+func fillTemplate(text string, summary *analyzer.GameSummary, player analyzer.PlayerSummary) (string, bool) {
+	counts := map[string]int{
+		"kills":         player.K,
+		"deaths":        player.D,
+		"assists":       player.A,
+		"livesSaved":    player.LivesSaved,
+		"criticalSaves": player.CriticalSaves,
+		"vision":        player.VisionScore,
+	}
+	values := map[string]string{
+		"champion":          player.Champion,
+		"champion's":        possessive(player.Champion),
+		"kda":               fmt.Sprintf("%d/%d/%d", player.K, player.D, player.A),
+		"damage":            nonZero(player.TotalDamage, player.TotalDamageFormatted),
+		"damageShare":       percent(player.Metrics.DamageShare),
+		"killParticipation": percent(player.Metrics.KP),
+		"vision":            nonZero(player.VisionScore, strconv.Itoa(player.VisionScore)),
+		"healingShielding":  nonZero(player.TotalHealing+player.TotalShielding, analyzer.FormatNumber(player.TotalHealing+player.TotalShielding)),
+		"mitigated":         nonZero(player.TotalDamageMitigated, player.TotalDamageMitigatedFormatted),
+	}
+	return fillSlots(text, values, counts)
+}
+
+// fillSlots replaces {slot} with its value and {name:noun} with a count of noun, returning
+// false if a slot is unknown or has no value
+func fillSlots(text string, values map[string]string, counts map[string]int) (string, bool) {
+	var sb strings.Builder
+	for {
+		start := strings.Index(text, "{")
+		if start < 0 {
+			sb.WriteString(text)
+			break
+		}
+		end := strings.Index(text[start:], "}")
+		if end < 0 {
+			return "", false
+		}
+		sb.WriteString(text[:start])
+		slot := text[start+1 : start+end]
+		text = text[start+end+1:]
+
+		var value string
+		if name, noun, ok := strings.Cut(slot, ":"); ok {
+			count, known := counts[name]
+			if !known || count <= 0 {
+				return "", false
+			}
+			value = countOf(count, noun)
+		} else {
+			value = values[slot]
+		}
+		if value == "" {
+			return "", false
+		}
+		sb.WriteString(value)
+	}
+	return sb.String(), true
+}
+
+// possessive returns "Ahri's", or "Nasus'" for names ending in s
+func possessive(name string) string {
+	if name == "" {
+		return ""
+	}
+	if strings.HasSuffix(name, "s") {
+		return name + "'"
+	}
+	return name + "'s"
+}
+
+// countOf returns a count with its noun in the right form: "once", "twice", "3 times", "1 kill", "2 lives"
+func countOf(count int, noun string) string {
+	if noun == "time" {
+		switch count {
+		case 1:
+			return "once"
+		case 2:
+			return "twice"
+		}
+	}
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %s", count, plural(noun))
+}
+
+// plural returns the plural of the nouns templates count
+func plural(noun string) string {
+	switch {
+	case noun == "life":
+		return "lives"
+	case strings.HasSuffix(noun, "s"):
+		return noun + "es"
+	default:
+		return noun + "s"
+	}
+}
+
+// percent formats a share such as 0.34 as "34%" (empty when there is none or it is bogus)
+func percent(share float64) string {
+	if share <= 0 || share > 1 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%%", share*100)
+}
+
+// nonZero returns value, or "" when the stat behind it is zero
+func nonZero(stat int, value string) string {
+	if stat <= 0 {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"log"
+	"lol-kind-bot/lcu"
+	"sync"
+	"time"
+)
+
+// ChampSelectMonitor watches champion select and reports our team whenever a pick, hover or
+// position changes. It is started when champion select begins and stopped when it ends.
+type ChampSelectMonitor struct {
+	client       *lcu.Client
+	pollInterval time.Duration
+	lastKey      string // Team as last reported, see lcu.ChampSelectSession.Key
+	mu           sync.RWMutex
+	stopChan     chan struct{}
+	running      bool
+	onChange     func(session *lcu.ChampSelectSession)
+}
+
+func NewChampSelectMonitor(client *lcu.Client, pollInterval time.Duration, onChange func(session *lcu.ChampSelectSession)) *ChampSelectMonitor {
+	return &ChampSelectMonitor{
+		client:       client,
+		pollInterval: pollInterval,
+		stopChan:     make(chan struct{}),
+		onChange:     onChange,
+	}
+}
+
+func (m *ChampSelectMonitor) Start() {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = true
+	m.stopChan = make(chan struct{}) // Fresh channel so a stopped monitor can be restarted
+	m.lastKey = ""                   // Report the new champion select's team straight away
+	stopChan := m.stopChan
+	m.mu.Unlock()
+
+	go m.monitorLoop(stopChan)
+}
+
+func (m *ChampSelectMonitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.running {
+		return
+	}
+	m.running = false
+	close(m.stopChan)
+}
+
+func (m *ChampSelectMonitor) IsRunning() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.running
+}
+
+func (m *ChampSelectMonitor) monitorLoop(stopChan <-chan struct{}) {
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	log.Printf("Champ select monitor started (poll interval: %v)", m.pollInterval)
+	m.checkSession(stopChan)
+
+	for {
+		select {
+		case <-stopChan:
+			log.Println("Champ select monitor stopped")
+			return
+		case <-ticker.C:
+			m.checkSession(stopChan)
+		}
+	}
+}
+
+// checkSession reports the team if it changed since the last check
+func (m *ChampSelectMonitor) checkSession(stopChan <-chan struct{}) {
+	session, err := m.client.GetChampSelectSession()
+	if err != nil || len(session.MyTeam) == 0 {
+		return // Champion select not ready yet, or already over
+	}
+
+	key := session.Key()
+	m.mu.Lock()
+	changed := key != m.lastKey
+	m.lastKey = key
+	m.mu.Unlock()
+
+	select {
+	case <-stopChan:
+		return // Stopped while the session was being read
+	default:
+	}
+	if changed && m.onChange != nil {
+		m.onChange(session)
+	}
+}
@@ -65,10 +65,12 @@ func (cm *ClutchMonitor) Start() {
 		return
 	}
 	cm.running = true
+	cm.stopChan = make(chan struct{}) // Fresh channel so a stopped monitor can be restarted
+	stopChan := cm.stopChan
 	cm.mu.Unlock()
 
 	log.Println("[CLUTCH] Starting clutch event monitor")
-	go cm.monitorLoop()
+	go cm.monitorLoop(stopChan)
 }
 
 // Stop stops monitoring
@@ -123,13 +125,13 @@ func (cm *ClutchMonitor) Reset() {
 }
 
 // monitorLoop polls for live game data and detects clutch events
-func (cm *ClutchMonitor) monitorLoop() {
+func (cm *ClutchMonitor) monitorLoop(stopChan <-chan struct{}) {
 	ticker := time.NewTicker(cm.pollInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-cm.stopChan:
+		case <-stopChan:
 			return
 		case <-ticker.C:
 			cm.checkForClutchEvents()
@@ -47,9 +47,11 @@ func (m *GameflowMonitor) Start() {
 		return
 	}
 	m.running = true
+	m.stopChan = make(chan struct{}) // Fresh channel so a stopped monitor can be restarted
+	stopChan := m.stopChan
 	m.mu.Unlock()
 
-	go m.pollLoop()
+	go m.pollLoop(stopChan)
 }
 
 func (m *GameflowMonitor) Stop() {
@@ -68,15 +70,39 @@ func (m *GameflowMonitor) IsRunning() bool {
 	return m.running
 }
 
-func (m *GameflowMonitor) pollLoop() {
-	ticker := time.NewTicker(m.pollInterval)
+// SetPollInterval changes how often the phase is polled, taking effect on the next poll
+func (m *GameflowMonitor) SetPollInterval(pollInterval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pollInterval = pollInterval
+}
+
+// SetCooldown changes the minimum time between EndOfGame callbacks
+func (m *GameflowMonitor) SetCooldown(cooldown time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cooldown = cooldown
+}
+
+func (m *GameflowMonitor) pollLoop(stopChan <-chan struct{}) {
+	m.mu.RLock()
+	pollInterval := m.pollInterval
+	m.mu.RUnlock()
+	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-m.stopChan:
+		case <-stopChan:
 			return
 		case <-ticker.C:
+			m.mu.RLock()
+			interval := m.pollInterval
+			m.mu.RUnlock()
+			if interval != pollInterval && interval > 0 {
+				pollInterval = interval
+				ticker.Reset(pollInterval)
+			}
 			m.checkPhase()
 		}
 	}
@@ -101,6 +127,7 @@ func (m *GameflowMonitor) checkPhase() {
 	currentPhase := m.currentPhase
 	lastPhase := m.lastPhase
 	lastEoGTimeValue := m.lastEoGTime
+	cooldown := m.cooldown
 	m.mu.Unlock()
 
 	if currentPhase != lastPhase {
@@ -114,7 +141,7 @@ func (m *GameflowMonitor) checkPhase() {
 
 	if currentPhase == "EndOfGame" && lastPhase != "EndOfGame" {
 		now := time.Now()
-		if now.Sub(lastEoGTimeValue) >= m.cooldown {
+		if now.Sub(lastEoGTimeValue) >= cooldown {
 			m.mu.Lock()
 			// Double-check after acquiring lock (prevent race conditions)
 			if now.Sub(m.lastEoGTime) >= m.cooldown {
@@ -0,0 +1,181 @@
+package monitor
+
+import (
+	"fmt"
+	"log"
+	"lol-kind-bot/config"
+	"lol-kind-bot/lcu"
+	"strconv"
+	"strings"
+)
+
+// minBuildItemCost skips starter items and basic boots in client item sets
+const minBuildItemCost = 500
+
+// itemBuild is the items we plan to buy this game, in order
+type itemBuild struct {
+	source string // Where it came from, for logging
+	items  []int
+}
+
+// configBuild returns the build configured for the champion (nil if none). Items are matched by
+// ID or case-insensitive name; unknown ones are logged and skipped.
+func configBuild(cfg *config.GoldAnnouncementSettings, champion string, items map[int]lcu.Item) *itemBuild {
+	var planned []string
+	for name, build := range cfg.Builds {
+		if strings.EqualFold(name, champion) {
+			planned = build
+			break
+		}
+	}
+	if len(planned) == 0 {
+		return nil
+	}
+
+	byName := make(map[string]int, len(items))
+	for id, item := range items {
+		if item.InStore || byName[strings.ToLower(item.Name)] == 0 {
+			byName[strings.ToLower(item.Name)] = id // Prefer the buyable item when names repeat (e.g. mode variants)
+		}
+	}
+
+	var build []int
+	for _, entry := range planned {
+		id, err := strconv.Atoi(strings.TrimSpace(entry))
+		if err != nil {
+			id = byName[strings.ToLower(strings.TrimSpace(entry))]
+		}
+		if _, ok := items[id]; !ok {
+			log.Printf("Gold monitor: Unknown item %q in the %s build, skipping it", entry, champion)
+			continue
+		}
+		if !containsItem(build, id) {
+			build = append(build, id)
+		}
+	}
+	if len(build) == 0 {
+		return nil
+	}
+	return &itemBuild{source: "goldAnnouncements.builds", items: build}
+}
+
+// nextPurchase decides what to say about the next item in the build, with a key that is
+// announced once per inventory:
+//   - "You can afford Infinity Edge" once we can buy the next item outright
+//   - otherwise "You can afford B. F. Sword" for its most expensive component we can buy
+//   - otherwise "300 gold to Cloak of Agility" once we're within almostGold of its cheapest
+//
+// It returns false when there is nothing to say or the build is complete.
+func (b *itemBuild) nextPurchase(items map[int]lcu.Item, inventory map[int]int, gold, almostGold int) (GoldMilestone, string, bool) {
+	target, ok := b.next(items, inventory)
+	if !ok {
+		return GoldMilestone{}, "", false
+	}
+	item := items[target]
+
+	owned := copyInventory(inventory)
+	if cost := remainingCost(items, target, owned); gold >= cost {
+		return GoldMilestone{Gold: gold, Item: item.Name, Text: "You can afford " + item.Name}, fmt.Sprintf("afford:%d", target), true
+	}
+
+	// The components still to buy, each with what it costs given what we own
+	type step struct {
+		id, cost int
+	}
+	var steps []step
+	owned = copyInventory(inventory)
+	for _, component := range item.From {
+		if owned[component] > 0 {
+			owned[component]--
+			continue
+		}
+		steps = append(steps, step{id: component, cost: remainingCost(items, component, owned)})
+	}
+
+	var best, cheapest *step
+	for i := range steps {
+		s := &steps[i]
+		if s.cost <= gold && (best == nil || s.cost > best.cost) {
+			best = s
+		}
+		if cheapest == nil || s.cost < cheapest.cost {
+			cheapest = s
+		}
+	}
+	if best != nil {
+		name := items[best.id].Name
+		return GoldMilestone{Gold: gold, Item: name, Text: "You can afford " + name}, fmt.Sprintf("afford:%d", best.id), true
+	}
+
+	// Only the combine cost is left, or no component is affordable yet
+	next := step{id: target, cost: remainingCost(items, target, copyInventory(inventory))}
+	if cheapest != nil {
+		next = *cheapest
+	}
+	short := next.cost - gold
+	if almostGold <= 0 || short > almostGold {
+		return GoldMilestone{}, "", false
+	}
+	short = (short + 49) / 50 * 50 // "300 gold to", not "287 gold to"
+	name := items[next.id].Name
+	return GoldMilestone{Gold: gold, Item: name, Text: fmt.Sprintf("%d gold to %s", short, name)}, fmt.Sprintf("almost:%d", next.id), true
+}
+
+// next returns the first item in the build we don't have yet, counting an item as done once we
+// own it or something built from it
+func (b *itemBuild) next(items map[int]lcu.Item, inventory map[int]int) (int, bool) {
+	for _, id := range b.items {
+		if !hasItem(items, id, inventory, 0) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// hasItem reports whether we own the item or anything it builds into
+func hasItem(items map[int]lcu.Item, id int, inventory map[int]int, depth int) bool {
+	if inventory[id] > 0 {
+		return true
+	}
+	if depth > 4 {
+		return false // Recipes are never this deep; guards against bad data
+	}
+	for _, upgrade := range items[id].To {
+		if hasItem(items, upgrade, inventory, depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// remainingCost returns the gold still needed for an item: its combine cost plus its
+// components, minus those we own (which are taken out of owned as they are used)
+func remainingCost(items map[int]lcu.Item, id int, owned map[int]int) int {
+	item := items[id]
+	cost := item.Price
+	for _, component := range item.From {
+		if owned[component] > 0 {
+			owned[component]--
+			continue
+		}
+		cost += remainingCost(items, component, owned)
+	}
+	return cost
+}
+
+func copyInventory(inventory map[int]int) map[int]int {
+	copied := make(map[int]int, len(inventory))
+	for id, count := range inventory {
+		copied[id] = count
+	}
+	return copied
+}
+
+func containsItem(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
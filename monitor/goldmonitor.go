@@ -1,28 +1,46 @@
 package monitor
 
 import (
+	"fmt"
+	"log"
 	"lol-kind-bot/config"
 	"lol-kind-bot/lcu"
-	"log"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
+// GoldMilestone is one gold announcement
+type GoldMilestone struct {
+	Gold int    `json:"gold"`           // Our gold (build mode) or the threshold reached
+	Item string `json:"item,omitempty"` // The item it is about (build mode)
+	Text string `json:"text"`           // What to say, e.g. "You can afford Infinity Edge"
+}
+
 type GoldMonitor struct {
 	client          *lcu.Client
 	cfg             *config.GoldAnnouncementSettings
-	announcedGold   map[int]bool // Track which thresholds we've already announced
+	announcedGold   map[int]bool    // Track which thresholds we've already announced
+	announced       map[string]bool // Build announcements made since the last purchase
+	inventoryKey    string          // Our items at the last check; a change means we bought or sold something
+	build           *itemBuild      // Our planned build for this game (nil if we have none)
+	buildLoaded     bool
+	items           map[int]lcu.Item // Item catalog from game data, loaded once
+	failures        int              // Failed reads in a row, for logging
 	mu              sync.RWMutex
 	stopChan        chan struct{}
 	running         bool
-	onGoldMilestone func(gold int) // Callback for gold announcements
+	onGoldMilestone func(milestone GoldMilestone) // Callback for gold announcements
 }
 
-func NewGoldMonitor(client *lcu.Client, cfg *config.GoldAnnouncementSettings, onGoldMilestone func(gold int)) *GoldMonitor {
+func NewGoldMonitor(client *lcu.Client, cfg *config.GoldAnnouncementSettings, onGoldMilestone func(milestone GoldMilestone)) *GoldMonitor {
+	settings := *cfg
 	return &GoldMonitor{
 		client:          client,
-		cfg:             cfg,
+		cfg:             &settings,
 		announcedGold:   make(map[int]bool),
+		announced:       make(map[string]bool),
 		stopChan:        make(chan struct{}),
 		onGoldMilestone: onGoldMilestone,
 	}
@@ -35,11 +53,12 @@ func (m *GoldMonitor) Start() {
 		return
 	}
 	m.running = true
-	// Reset announced thresholds when starting
-	m.announcedGold = make(map[int]bool)
+	m.stopChan = make(chan struct{}) // Fresh channel so a stopped monitor can be restarted
+	m.reset()
+	stopChan := m.stopChan
 	m.mu.Unlock()
 
-	go m.monitorLoop()
+	go m.monitorLoop(stopChan)
 }
 
 func (m *GoldMonitor) Stop() {
@@ -58,14 +77,15 @@ func (m *GoldMonitor) IsRunning() bool {
 	return m.running
 }
 
-func (m *GoldMonitor) monitorLoop() {
-	if !m.cfg.Enabled {
+func (m *GoldMonitor) monitorLoop(stopChan <-chan struct{}) {
+	cfg := m.settings()
+	if !cfg.Enabled {
 		log.Println("Gold announcements disabled, stopping monitor")
 		m.Stop()
 		return
 	}
 
-	if len(m.cfg.Thresholds) == 0 {
+	if cfg.Mode == "thresholds" && len(cfg.Thresholds) == 0 {
 		log.Println("Gold monitor: No thresholds configured, stopping")
 		m.Stop()
 		return
@@ -75,81 +95,279 @@ func (m *GoldMonitor) monitorLoop() {
 		log.Println("Gold monitor: WARNING - callback is nil! Announcements will not work.")
 	}
 
-	pollInterval := time.Duration(m.cfg.PollIntervalSec) * time.Second
+	pollInterval := time.Duration(cfg.PollIntervalSec) * time.Second
 	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
-	log.Printf("Gold monitor started (thresholds: %v, poll interval: %v, callback nil: %v)", 
-		m.cfg.Thresholds, pollInterval, m.onGoldMilestone == nil)
+	log.Printf("Gold monitor started (mode: %s, thresholds: %v, poll interval: %v)", cfg.Mode, cfg.Thresholds, pollInterval)
 
 	// Do an immediate check when starting
-	log.Println("Gold monitor: Performing initial gold check...")
 	m.checkGold()
 
 	for {
 		select {
-		case <-m.stopChan:
+		case <-stopChan:
 			log.Println("Gold monitor stopped")
 			return
 		case <-ticker.C:
+			// Pick up interval changes from UpdateSettings
+			if interval := time.Duration(m.settings().PollIntervalSec) * time.Second; interval != pollInterval && interval > 0 {
+				pollInterval = interval
+				ticker.Reset(pollInterval)
+			}
 			m.checkGold()
 		}
 	}
 }
 
 func (m *GoldMonitor) checkGold() {
-	playerData, err := m.client.GetActivePlayerData()
+	gameData, err := m.client.GetAllGameData()
 	if err != nil {
-		// Game might have ended or not be in progress
-		// Log occasionally to help debug (every 10th failure)
+		// Game might have ended or not be in progress; log every 10th failure
 		m.mu.Lock()
-		checkCount := len(m.announcedGold) // Use announced count as a simple counter
+		m.failures++
+		failures := m.failures
 		m.mu.Unlock()
-		if checkCount%10 == 0 {
-			log.Printf("Gold monitor: Failed to get active player data (game may have ended): %v", err)
+		if failures%10 == 1 {
+			log.Printf("Gold monitor: Failed to get live game data (game may have ended): %v", err)
 		}
 		return
 	}
 
-	currentGold := int(playerData.CurrentGold)
-	
-	// Log current gold periodically for debugging (every 30 seconds worth of checks)
-	m.mu.RLock()
-	thresholds := m.cfg.Thresholds
-	announced := m.announcedGold
-	checkCount := len(announced)
-	m.mu.RUnlock()
-	
-	// Log current gold more frequently for debugging (every 5 checks = 10 seconds)
-	if checkCount%5 == 0 && currentGold > 0 {
-		log.Printf("Gold monitor: Current gold: %d (check #%d)", currentGold, checkCount)
+	cfg := m.settings()
+	currentGold := int(gameData.ActivePlayer.CurrentGold)
+	me := gameData.Me()
+	inventory := make(map[int]int)
+	if me != nil {
+		for _, item := range me.Items {
+			inventory[item.ItemID] += max(item.Count, 1)
+		}
+	}
+
+	m.mu.Lock()
+	m.failures = 0
+	if key := inventoryKey(inventory); key != m.inventoryKey {
+		// Bought or sold something: re-arm the announcements for what we can afford now
+		if m.inventoryKey != "" {
+			log.Printf("Gold monitor: Inventory changed, re-arming announcements (gold: %d)", currentGold)
+		}
+		m.inventoryKey = key
+		m.announced = make(map[string]bool)
+		for threshold := range m.announcedGold {
+			if threshold > currentGold {
+				delete(m.announcedGold, threshold)
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	if !cfg.Enabled {
+		return // Disabled while running (config reload)
 	}
 
-	// Check each threshold
+	if cfg.Mode == "build" {
+		if build := m.buildFor(gameData, me, &cfg); build != nil {
+			m.checkBuild(build, inventory, currentGold, cfg.AlmostGold)
+			return
+		}
+	}
+	m.checkThresholds(cfg.Thresholds, currentGold)
+}
+
+// checkThresholds announces each fixed threshold once, until a purchase takes us back under it
+func (m *GoldMonitor) checkThresholds(thresholds []int, currentGold int) {
 	for _, threshold := range thresholds {
 		// Only announce if we've reached or exceeded threshold and haven't announced it yet
-		if currentGold >= threshold && !announced[threshold] {
-			m.mu.Lock()
+		m.mu.Lock()
+		due := currentGold >= threshold && !m.announcedGold[threshold]
+		if due {
 			m.announcedGold[threshold] = true
+		}
+		m.mu.Unlock()
+		if !due {
+			continue
+		}
+
+		log.Printf("Gold milestone reached: %d gold (threshold: %d)", currentGold, threshold)
+		m.announce(GoldMilestone{Gold: threshold, Text: fmt.Sprintf("%d Gold", threshold)})
+	}
+}
+
+// checkBuild announces the next purchase in our build once per inventory
+func (m *GoldMonitor) checkBuild(build *itemBuild, inventory map[int]int, currentGold, almostGold int) {
+	milestone, key, ok := build.nextPurchase(m.catalog(), inventory, currentGold, almostGold)
+	if !ok {
+		return
+	}
+
+	m.mu.Lock()
+	due := !m.announced[key]
+	m.announced[key] = true
+	m.mu.Unlock()
+	if !due {
+		return
+	}
+
+	log.Printf("Gold milestone: %s (gold: %d, build: %s)", milestone.Text, currentGold, build.source)
+	m.announce(milestone)
+}
+
+func (m *GoldMonitor) announce(milestone GoldMilestone) {
+	if m.onGoldMilestone != nil {
+		m.onGoldMilestone(milestone)
+	} else {
+		log.Printf("WARNING: Gold milestone callback is nil!")
+	}
+}
+
+// buildFor returns our planned build for this game, looking it up the first time our champion
+// is known: the config's build for the champion first, then a client item set for it
+func (m *GoldMonitor) buildFor(gameData *lcu.AllGameData, me *lcu.PlayerData, cfg *config.GoldAnnouncementSettings) *itemBuild {
+	m.mu.RLock()
+	build, loaded := m.build, m.buildLoaded
+	m.mu.RUnlock()
+	if loaded {
+		return build
+	}
+
+	champion := gameData.ActivePlayer.ChampionName
+	if me != nil && me.ChampionName != "" {
+		champion = me.ChampionName
+	}
+	if champion == "" {
+		return nil // Not loaded yet; try again next check
+	}
+
+	items := m.catalog()
+	if items == nil {
+		loadedItems, err := m.client.GetItems()
+		if err != nil {
+			log.Printf("Gold monitor: Failed to get items, using thresholds: %v", err)
+		} else {
+			items = loadedItems
+			m.mu.Lock()
+			m.items = items
 			m.mu.Unlock()
+		}
+	}
+	if items != nil {
+		build = configBuild(cfg, champion, items)
+		if build == nil {
+			build = m.itemSetBuild(champion, gameData.GameData.MapNumber, items)
+		}
+	}
+
+	if build != nil {
+		names := make([]string, len(build.items))
+		for i, id := range build.items {
+			names[i] = items[id].Name
+		}
+		log.Printf("Gold monitor: Following %s's build from %s: %s", champion, build.source, strings.Join(names, ", "))
+	} else {
+		log.Printf("Gold monitor: No build for %s, announcing thresholds", champion)
+	}
+
+	m.mu.Lock()
+	m.build, m.buildLoaded = build, true
+	m.mu.Unlock()
+	return build
+}
 
-			log.Printf("Gold milestone reached: %d gold (threshold: %d)", currentGold, threshold)
-			
-			if m.onGoldMilestone != nil {
-				log.Printf("Calling gold milestone callback for %d gold", threshold)
-				m.onGoldMilestone(threshold)
-			} else {
-				log.Printf("WARNING: Gold milestone callback is nil!")
+// itemSetBuild returns the first item set made for the champion in the client (nil if none)
+func (m *GoldMonitor) itemSetBuild(champion string, mapID int, items map[int]lcu.Item) *itemBuild {
+	summoner, err := m.client.GetCurrentSummoner()
+	if err != nil {
+		log.Printf("Gold monitor: Failed to get summoner for item sets: %v", err)
+		return nil
+	}
+	names, err := m.client.GetChampionNames()
+	if err != nil {
+		log.Printf("Gold monitor: Failed to get champion names for item sets: %v", err)
+		return nil
+	}
+	championID := 0
+	for id, name := range names {
+		if strings.EqualFold(name, champion) {
+			championID = id
+			break
+		}
+	}
+	sets, err := m.client.GetItemSets(summoner.SummonerID)
+	if err != nil {
+		log.Printf("Gold monitor: Failed to get item sets: %v", err)
+		return nil
+	}
+
+	for _, set := range sets {
+		if len(set.AssociatedChampions) == 0 || !set.For(championID, mapID) {
+			continue // Generic sets aren't a plan for this champion
+		}
+		var build []int
+		for _, id := range set.ItemIDs() {
+			item, ok := items[id]
+			// Starter items, basic boots and consumables aren't what we save up for
+			if !ok || item.Consumable() || item.PriceTotal < minBuildItemCost || containsItem(build, id) {
+				continue
 			}
+			build = append(build, id)
+		}
+		if len(build) > 0 {
+			return &itemBuild{source: fmt.Sprintf("item set %q", set.Title), items: build}
 		}
 	}
+	return nil
+}
+
+// UpdateSettings applies new gold announcement settings; a running monitor picks them up on its next check
+func (m *GoldMonitor) UpdateSettings(cfg *config.GoldAnnouncementSettings) {
+	settings := *cfg
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg = &settings
+	m.build, m.buildLoaded = nil, false // The builds may have changed
+}
+
+// settings returns a copy of the current settings
+func (m *GoldMonitor) settings() config.GoldAnnouncementSettings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return *m.cfg
 }
 
-// Reset resets the announced thresholds (call when starting a new game)
+// catalog returns the item catalog (nil until loaded)
+func (m *GoldMonitor) catalog() map[int]lcu.Item {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.items
+}
+
+// Reset resets the announced thresholds and build (call when starting a new game)
 func (m *GoldMonitor) Reset() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.announcedGold = make(map[int]bool)
+	m.reset()
 	log.Println("Gold monitor thresholds reset")
 }
 
+// reset forgets the last game. Must be called with m.mu held.
+func (m *GoldMonitor) reset() {
+	m.announcedGold = make(map[int]bool)
+	m.announced = make(map[string]bool)
+	m.inventoryKey = ""
+	m.build, m.buildLoaded = nil, false
+	m.failures = 0
+}
+
+// inventoryKey sums up our items so a purchase can be noticed
+func inventoryKey(inventory map[int]int) string {
+	ids := make([]int, 0, len(inventory))
+	for id := range inventory {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	var b strings.Builder
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%d:%d,", id, inventory[id])
+	}
+	return b.String()
+}
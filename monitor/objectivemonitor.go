@@ -0,0 +1,264 @@
+package monitor
+
+import (
+	"fmt"
+	"log"
+	"lol-kind-bot/config"
+	"lol-kind-bot/lcu"
+	"math"
+	"sync"
+	"time"
+)
+
+// Objective spawn, respawn and buff timings in seconds of game time (Summoner's Rift)
+const (
+	dragonFirstSpawn  = 5 * 60
+	dragonRespawn     = 5 * 60
+	elderRespawn      = 6 * 60 // After a team takes its dragon soul, and after each Elder Dragon
+	dragonSoul        = 4      // Dragons a team needs before Elder Dragon replaces the others
+	heraldFirstSpawn  = 14 * 60
+	baronFirstSpawn   = 20 * 60
+	baronRespawn      = 6 * 60
+	inhibitorRespawn  = 5 * 60
+	baronBuffDuration = 3 * 60
+	elderBuffDuration = 150
+)
+
+// objectiveTimer is an announcement due shortly before a point in game time
+type objectiveTimer struct {
+	kind      string  // Objective kind from config.ObjectiveKinds
+	endsAt    float64 // Game time of the spawn, respawn or buff expiry
+	what      string  // e.g. "Dragon", "Enemy top inhibitor"
+	verb      string  // e.g. "spawns", "is back", "ends"
+	announced bool
+}
+
+// ObjectiveMonitor reads live game events and announces upcoming dragon, Herald and Baron
+// spawns, inhibitor respawns and Baron/Elder buff expiry a configurable time in advance
+type ObjectiveMonitor struct {
+	client      *lcu.Client
+	cfg         *config.ObjectiveTimerSettings
+	timers      map[string]*objectiveTimer // Keyed by objective, e.g. "dragon", "inhibitor:Barracks_T1_L1"
+	dragons     map[string]int             // Team -> dragons taken
+	lastEventID int                        // Highest live event ID already handled
+	mu          sync.RWMutex
+	stopChan    chan struct{}
+	running     bool
+	onAnnounce  func(text string) // Callback for announcements
+}
+
+func NewObjectiveMonitor(client *lcu.Client, cfg *config.ObjectiveTimerSettings, onAnnounce func(text string)) *ObjectiveMonitor {
+	settings := *cfg
+	return &ObjectiveMonitor{
+		client:     client,
+		cfg:        &settings,
+		stopChan:   make(chan struct{}),
+		onAnnounce: onAnnounce,
+	}
+}
+
+func (m *ObjectiveMonitor) Start() {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = true
+	m.stopChan = make(chan struct{}) // Fresh channel so a stopped monitor can be restarted
+	m.reset()
+	stopChan := m.stopChan
+	m.mu.Unlock()
+
+	go m.monitorLoop(stopChan)
+}
+
+func (m *ObjectiveMonitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.running {
+		return
+	}
+	m.running = false
+	close(m.stopChan)
+}
+
+func (m *ObjectiveMonitor) IsRunning() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.running
+}
+
+// UpdateSettings applies new objective timer settings; a running monitor picks them up on its next check
+func (m *ObjectiveMonitor) UpdateSettings(cfg *config.ObjectiveTimerSettings) {
+	settings := *cfg
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg = &settings
+}
+
+// settings returns a copy of the current settings
+func (m *ObjectiveMonitor) settings() config.ObjectiveTimerSettings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return *m.cfg
+}
+
+// reset forgets the last game's timers and sets up the first spawns. Must be called with m.mu held.
+func (m *ObjectiveMonitor) reset() {
+	m.timers = map[string]*objectiveTimer{
+		"dragon": {kind: "dragon", endsAt: dragonFirstSpawn, what: "Dragon", verb: "spawns"},
+		"herald": {kind: "herald", endsAt: heraldFirstSpawn, what: "Rift Herald", verb: "spawns"},
+		"baron":  {kind: "baron", endsAt: baronFirstSpawn, what: "Baron", verb: "spawns"},
+	}
+	m.dragons = make(map[string]int)
+	m.lastEventID = -1
+}
+
+func (m *ObjectiveMonitor) monitorLoop(stopChan <-chan struct{}) {
+	cfg := m.settings()
+	if !cfg.Enabled {
+		log.Println("Objective announcements disabled, stopping monitor")
+		m.Stop()
+		return
+	}
+
+	pollInterval := time.Duration(cfg.PollIntervalSec) * time.Second
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	log.Printf("Objective monitor started (poll interval: %v)", pollInterval)
+	m.checkObjectives()
+
+	for {
+		select {
+		case <-stopChan:
+			log.Println("Objective monitor stopped")
+			return
+		case <-ticker.C:
+			// Pick up interval changes from UpdateSettings
+			if interval := time.Duration(m.settings().PollIntervalSec) * time.Second; interval != pollInterval && interval > 0 {
+				pollInterval = interval
+				ticker.Reset(pollInterval)
+			}
+			m.checkObjectives()
+		}
+	}
+}
+
+// checkObjectives turns new live events into timers and announces the timers that are due
+func (m *ObjectiveMonitor) checkObjectives() {
+	gameData, err := m.client.GetAllGameData()
+	if err != nil {
+		return // Game still loading, or already over
+	}
+	cfg := m.settings()
+	gameTime := gameData.GameData.GameTime
+	myTeam := gameData.MyTeam()
+
+	var announcements []string
+	m.mu.Lock()
+	for _, event := range gameData.Events.Events {
+		if event.EventID <= m.lastEventID {
+			continue
+		}
+		m.lastEventID = event.EventID
+		m.handleEvent(event, gameData, myTeam)
+	}
+
+	for _, timer := range m.timers {
+		if timer.announced {
+			continue
+		}
+		settings := cfg.Timer(timer.kind)
+		remaining := timer.endsAt - gameTime
+		if remaining <= 0 {
+			timer.announced = true // Missed it (e.g. we joined late); too late to be useful
+			continue
+		}
+		if !cfg.Enabled || settings == nil || !settings.Enabled || remaining > float64(settings.LeadSeconds) {
+			continue
+		}
+		timer.announced = true
+		announcements = append(announcements, timer.announcement(remaining))
+	}
+	m.mu.Unlock()
+
+	for _, text := range announcements {
+		log.Printf("Objective timer: %s (game time %s)", text, formatGameTime(gameTime))
+		if m.onAnnounce != nil {
+			m.onAnnounce(text)
+		}
+	}
+}
+
+// handleEvent starts, replaces or removes the timers an event affects. Must be called with m.mu held.
+func (m *ObjectiveMonitor) handleEvent(event lcu.LiveEvent, gameData *lcu.AllGameData, myTeam string) {
+	switch event.EventName {
+	case lcu.LiveEventDragonKill:
+		team := gameData.PlayerTeam(event.KillerName)
+		if event.DragonType == "Elder" {
+			m.timers["dragon"] = &objectiveTimer{kind: "dragon", endsAt: event.EventTime + elderRespawn, what: "Elder Dragon", verb: "spawns"}
+			m.timers["elderBuff"] = &objectiveTimer{kind: "elderBuff", endsAt: event.EventTime + elderBuffDuration, what: teamName(team, myTeam, "Elder buff"), verb: "ends"}
+			return
+		}
+		m.dragons[team]++
+		if team != "" && m.dragons[team] >= dragonSoul {
+			m.timers["dragon"] = &objectiveTimer{kind: "dragon", endsAt: event.EventTime + elderRespawn, what: "Elder Dragon", verb: "spawns"}
+		} else {
+			m.timers["dragon"] = &objectiveTimer{kind: "dragon", endsAt: event.EventTime + dragonRespawn, what: "Dragon", verb: "spawns"}
+		}
+	case lcu.LiveEventHeraldKill:
+		delete(m.timers, "herald") // The Herald doesn't respawn
+	case lcu.LiveEventBaronKill:
+		team := gameData.PlayerTeam(event.KillerName)
+		m.timers["baron"] = &objectiveTimer{kind: "baron", endsAt: event.EventTime + baronRespawn, what: "Baron", verb: "spawns"}
+		m.timers["baronBuff"] = &objectiveTimer{kind: "baronBuff", endsAt: event.EventTime + baronBuffDuration, what: teamName(team, myTeam, "Baron buff"), verb: "ends"}
+	case lcu.LiveEventInhibKilled:
+		team, lane := lcu.InhibitorTeam(event.InhibKilled)
+		if team == "" {
+			return
+		}
+		what := "inhibitor"
+		if lane != "" {
+			what = lane + " inhibitor"
+		}
+		m.timers["inhibitor:"+event.InhibKilled] = &objectiveTimer{kind: "inhibitor", endsAt: event.EventTime + inhibitorRespawn, what: teamName(team, myTeam, what), verb: "is back"}
+	case lcu.LiveEventInhibRespawned:
+		delete(m.timers, "inhibitor:"+event.InhibRespawned)
+	}
+}
+
+// announcement is what to say for a timer with remaining seconds to go, e.g. "Dragon spawns in 30 seconds"
+func (t *objectiveTimer) announcement(remaining float64) string {
+	seconds := int(math.Round(remaining/5) * 5) // "in 30 seconds" rather than "in 28 seconds"
+	if seconds < 5 {
+		return fmt.Sprintf("%s %s now", t.what, t.verb)
+	}
+	return fmt.Sprintf("%s %s in %d seconds", t.what, t.verb, seconds)
+}
+
+// teamName prefixes what with "Our" or "Enemy" depending on whose it is (unchanged if unknown)
+func teamName(team, myTeam, what string) string {
+	switch {
+	case team == "" || myTeam == "":
+		return capitalize(what)
+	case team == myTeam:
+		return "Our " + what
+	default:
+		return "Enemy " + what
+	}
+}
+
+// capitalize upper-cases the first letter of an ASCII phrase
+func capitalize(s string) string {
+	if s == "" || s[0] < 'a' || s[0] > 'z' {
+		return s
+	}
+	return string(s[0]-'a'+'A') + s[1:]
+}
+
+// formatGameTime formats seconds of game time as "m:ss"
+func formatGameTime(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
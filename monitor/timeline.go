@@ -0,0 +1,314 @@
+package monitor
+
+import (
+	"log"
+	"lol-kind-bot/lcu"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timelineMatchSeconds is how far a recording's length may be from a game's length and still be that game's recording
+const timelineMatchSeconds = 120
+
+// Timeline is a compact record of one game, sampled from the live game data
+type Timeline struct {
+	MyTeam   string          `json:"myTeam"`   // "ORDER" or "CHAOS"
+	Duration float64         `json:"duration"` // Seconds of game time at the last sample
+	Frames   []TimelineFrame `json:"frames"`   // One per minute, plus the last sample
+	Events   []TimelineEvent `json:"events"`
+}
+
+// TimelineFrame is every player's state at one point of the game
+type TimelineFrame struct {
+	Time    float64       `json:"time"` // Seconds of game time
+	Players []PlayerFrame `json:"players"`
+}
+
+// PlayerFrame is one player's state in a frame. The live game only reports our own gold, so
+// Gold is the value of the player's items, which is comparable between players.
+type PlayerFrame struct {
+	Champion string `json:"champion"`
+	Team     string `json:"team"` // "ORDER" or "CHAOS"
+	Level    int    `json:"level"`
+	CS       int    `json:"cs"`
+	Kills    int    `json:"kills"`
+	Deaths   int    `json:"deaths"`
+	Assists  int    `json:"assists"`
+	Gold     int    `json:"gold"`
+	Items    []int  `json:"items,omitempty"`
+}
+
+// TimelineEvent is one live game event, with players named by champion
+type TimelineEvent struct {
+	Time      float64  `json:"time"` // Seconds of game time
+	Name      string   `json:"name"` // Live event name, e.g. "ChampionKill", "DragonKill"
+	Team      string   `json:"team,omitempty"`
+	Killer    string   `json:"killer,omitempty"`
+	Victim    string   `json:"victim,omitempty"`
+	Assisters []string `json:"assisters,omitempty"`
+	Detail    string   `json:"detail,omitempty"` // Dragon type, turret or inhibitor
+	Stolen    bool     `json:"stolen,omitempty"`
+}
+
+// TeamGold returns the item gold of each team in a frame
+func (f *TimelineFrame) TeamGold() map[string]int {
+	gold := make(map[string]int, 2)
+	for _, p := range f.Players {
+		gold[p.Team] += p.Gold
+	}
+	return gold
+}
+
+// FrameAt returns the last frame at or before a game time (nil if there is none)
+func (t *Timeline) FrameAt(seconds float64) *TimelineFrame {
+	var found *TimelineFrame
+	for i := range t.Frames {
+		if t.Frames[i].Time > seconds {
+			break
+		}
+		found = &t.Frames[i]
+	}
+	return found
+}
+
+// Matches reports whether the recording is of a game that lasted durationSec seconds
+func (t *Timeline) Matches(durationSec int) bool {
+	return t != nil && len(t.Frames) > 0 && math.Abs(t.Duration-float64(durationSec)) <= timelineMatchSeconds
+}
+
+// TimelineRecorder samples the live game into a Timeline
+type TimelineRecorder struct {
+	client       *lcu.Client
+	pollInterval time.Duration
+	timeline     *Timeline
+	last         *TimelineFrame // Latest sample, added as the final frame
+	lastEventID  int            // Highest live event ID already recorded
+	failures     int            // Failed reads in a row, for logging
+	mu           sync.RWMutex
+	stopChan     chan struct{}
+	running      bool
+}
+
+// NewTimelineRecorder creates a recorder that samples the live game every pollInterval
+func NewTimelineRecorder(client *lcu.Client, pollInterval time.Duration) *TimelineRecorder {
+	return &TimelineRecorder{
+		client:       client,
+		pollInterval: pollInterval,
+		lastEventID:  -1,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start starts recording a new game, dropping the previous recording
+func (r *TimelineRecorder) Start() {
+	r.mu.Lock()
+	if r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = true
+	r.stopChan = make(chan struct{}) // Fresh channel so a stopped recorder can be restarted
+	r.reset()
+	stopChan := r.stopChan
+	r.mu.Unlock()
+
+	go r.monitorLoop(stopChan)
+}
+
+// Stop stops recording; the recording is kept until the next Start
+func (r *TimelineRecorder) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.running {
+		return
+	}
+	r.running = false
+	close(r.stopChan)
+}
+
+// IsRunning returns if the recorder is running
+func (r *TimelineRecorder) IsRunning() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.running
+}
+
+// Timeline returns a copy of the recording, or nil if nothing was recorded
+func (r *TimelineRecorder) Timeline() *Timeline {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.timeline == nil {
+		return nil
+	}
+
+	timeline := *r.timeline
+	timeline.Frames = append([]TimelineFrame{}, r.timeline.Frames...)
+	timeline.Events = append([]TimelineEvent{}, r.timeline.Events...)
+	if r.last != nil && (len(timeline.Frames) == 0 || r.last.Time > timeline.Frames[len(timeline.Frames)-1].Time) {
+		timeline.Frames = append(timeline.Frames, *r.last)
+	}
+	return &timeline
+}
+
+func (r *TimelineRecorder) monitorLoop(stopChan <-chan struct{}) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	log.Printf("Timeline recorder started (poll interval: %v)", r.pollInterval)
+
+	// Do an immediate sample when starting
+	r.sample()
+
+	for {
+		select {
+		case <-stopChan:
+			if timeline := r.Timeline(); timeline != nil {
+				log.Printf("Timeline recorder stopped - recorded %d frames and %d events", len(timeline.Frames), len(timeline.Events))
+			}
+			return
+		case <-ticker.C:
+			r.sample()
+		}
+	}
+}
+
+// sample records new events, and a frame when a new minute has started
+func (r *TimelineRecorder) sample() {
+	gameData, err := r.client.GetAllGameData()
+	if err != nil {
+		// Game might have ended or not be loaded yet; log every 10th failure
+		r.mu.Lock()
+		r.failures++
+		failures := r.failures
+		r.mu.Unlock()
+		if failures%10 == 1 {
+			log.Printf("Timeline recorder: Failed to get live game data: %v", err)
+		}
+		return
+	}
+
+	frame := TimelineFrame{Time: gameData.GameData.GameTime}
+	for _, player := range gameData.AllPlayers {
+		frame.Players = append(frame.Players, playerFrame(player))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = 0
+	if r.timeline == nil {
+		r.timeline = &Timeline{}
+	}
+	if team := gameData.MyTeam(); team != "" {
+		r.timeline.MyTeam = team
+	}
+	r.timeline.Duration = frame.Time
+
+	for _, event := range gameData.Events.Events {
+		if event.EventID <= r.lastEventID {
+			continue
+		}
+		r.lastEventID = event.EventID
+		r.timeline.Events = append(r.timeline.Events, timelineEvent(gameData, event))
+	}
+
+	frames := r.timeline.Frames
+	if len(frames) == 0 || int(frame.Time/60) > int(frames[len(frames)-1].Time/60) {
+		r.timeline.Frames = append(frames, frame)
+		r.last = nil
+	} else {
+		r.last = &frame
+	}
+}
+
+// Reset drops the recording
+func (r *TimelineRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reset()
+}
+
+// reset forgets the last game. Must be called with r.mu held.
+func (r *TimelineRecorder) reset() {
+	r.timeline = nil
+	r.last = nil
+	r.lastEventID = -1
+	r.failures = 0
+}
+
+func playerFrame(player lcu.PlayerData) PlayerFrame {
+	frame := PlayerFrame{
+		Champion: player.ChampionName,
+		Team:     player.Team,
+		Level:    player.Level,
+		CS:       player.Scores.CreepScore,
+		Kills:    player.Scores.Kills,
+		Deaths:   player.Scores.Deaths,
+		Assists:  player.Scores.Assists,
+	}
+	for _, item := range player.Items {
+		frame.Gold += item.Price * max(item.Count, 1)
+		frame.Items = append(frame.Items, item.ItemID)
+	}
+	return frame
+}
+
+// timelineEvent names the players in an event by champion and credits it to a team
+func timelineEvent(gameData *lcu.AllGameData, event lcu.LiveEvent) TimelineEvent {
+	champion := func(name string) string {
+		if p := gameData.Player(name); p != nil && p.ChampionName != "" {
+			return p.ChampionName
+		}
+		return name // A minion, turret or monster
+	}
+
+	te := TimelineEvent{
+		Time:   event.EventTime,
+		Name:   event.EventName,
+		Killer: champion(event.KillerName),
+		Victim: champion(event.VictimName),
+		Team:   gameData.PlayerTeam(event.KillerName),
+		Stolen: strings.EqualFold(event.Stolen, "True"),
+	}
+	for _, assister := range event.Assisters {
+		te.Assisters = append(te.Assisters, champion(assister))
+	}
+
+	switch event.EventName {
+	case lcu.LiveEventChampionKill:
+		if te.Team == "" {
+			// Executed by a turret or minion: the kill goes to the victim's enemies
+			te.Team = otherTeam(gameData.PlayerTeam(event.VictimName))
+		}
+	case lcu.LiveEventAce:
+		te.Team = event.AcingTeam
+	case lcu.LiveEventDragonKill:
+		te.Detail = event.DragonType
+	case lcu.LiveEventTurretKilled:
+		te.Detail = event.TurretKilled
+		if te.Team == "" {
+			te.Team = otherTeam(lcu.StructureTeam(event.TurretKilled))
+		}
+	case lcu.LiveEventInhibKilled:
+		te.Detail = event.InhibKilled
+		if te.Team == "" {
+			te.Team = otherTeam(lcu.StructureTeam(event.InhibKilled))
+		}
+	case lcu.LiveEventInhibRespawned:
+		te.Detail = event.InhibRespawned
+		te.Team = lcu.StructureTeam(event.InhibRespawned)
+	}
+	return te
+}
+
+// otherTeam returns the opposing team ("" for an unknown team)
+func otherTeam(team string) string {
+	switch team {
+	case "ORDER":
+		return "CHAOS"
+	case "CHAOS":
+		return "ORDER"
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"lol-kind-bot/config"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// espeakWordsPerMinute is espeak-ng's normal speaking rate
+const espeakWordsPerMinute = 175
+
+// ESpeak speaks through espeak-ng (or the older espeak)
+type ESpeak struct {
+	path   string
+	voice  string
+	rate   int
+	volume int
+}
+
+// NewESpeak finds espeak-ng on the PATH and creates an engine with the voice, rate and volume
+// from the settings
+func NewESpeak(cfg *config.TTSSettings) (*ESpeak, error) {
+	path, err := exec.LookPath("espeak-ng")
+	if err != nil {
+		if path, err = exec.LookPath("espeak"); err != nil {
+			return nil, fmt.Errorf("espeak-ng not found: %w", err)
+		}
+	}
+	return &ESpeak{path: path, voice: cfg.Voice, rate: cfg.Rate, volume: cfg.Volume}, nil
+}
+
+func (e *ESpeak) Name() string {
+	return "espeak"
+}
+
+func (e *ESpeak) Speak(ctx context.Context, text string) error {
+	args := []string{
+		"-s", strconv.Itoa(int(math.Round(espeakWordsPerMinute * speed(e.rate)))),
+		"-a", strconv.Itoa(e.volume), // Amplitude 0-200, 100 = normal
+		"--stdin",
+	}
+	if e.voice != "" {
+		args = append(args, "-v", e.voice)
+	}
+	cmd := exec.CommandContext(ctx, e.path, args...)
+	cmd.Stdin = strings.NewReader(text)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to run espeak-ng: %w (stderr: %s)", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Voices lists the languages espeak-ng can speak, e.g. "en-us"
+func (e *ESpeak) Voices() ([]string, error) {
+	output, err := exec.Command(e.path, "--voices").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list espeak-ng voices: %w", err)
+	}
+
+	var voices []string
+	for i, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 2 {
+			continue // Header: "Pty Language Age/Gender VoiceName File Other Languages"
+		}
+		voices = append(voices, fields[1])
+	}
+	return voices, nil
+}
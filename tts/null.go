@@ -0,0 +1,49 @@
+package tts
+
+import (
+	"context"
+	"sync"
+)
+
+// Null says nothing (tts.engine "none", or no engine available)
+type Null struct{}
+
+func (Null) Name() string {
+	return "none"
+}
+
+func (Null) Speak(ctx context.Context, text string) error {
+	return nil
+}
+
+func (Null) Voices() ([]string, error) {
+	return nil, nil
+}
+
+// Recorder remembers what it was asked to say instead of speaking, e.g. for replays and tests
+type Recorder struct {
+	mu     sync.Mutex
+	spoken []string
+}
+
+func (r *Recorder) Name() string {
+	return "recorder"
+}
+
+func (r *Recorder) Speak(ctx context.Context, text string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spoken = append(r.spoken, text)
+	return nil
+}
+
+func (r *Recorder) Voices() ([]string, error) {
+	return nil, nil
+}
+
+// Spoken returns everything said so far, oldest first
+func (r *Recorder) Spoken() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.spoken...)
+}
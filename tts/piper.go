@@ -0,0 +1,173 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"lol-kind-bot/config"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// piperSampleRate is the sample rate of most piper voices, used if the model's config doesn't say
+const piperSampleRate = 22050
+
+// Piper speaks through a piper neural voice model, playing its raw audio with aplay
+type Piper struct {
+	path      string
+	aplayPath string
+	model     string
+	speaker   string // Speaker id for multi-speaker models ("" = the model's default)
+	rate      int
+	volume    int
+
+	sampleRate int
+	speakers   map[string]int // Speaker name -> id, from the model's config
+}
+
+// piperModelConfig is the part of a piper model's .onnx.json config we use
+type piperModelConfig struct {
+	Audio struct {
+		SampleRate int `json:"sample_rate"`
+	} `json:"audio"`
+	SpeakerIDMap map[string]int `json:"speaker_id_map"`
+}
+
+// NewPiper finds piper and aplay on the PATH and reads the model's config. The voice setting
+// picks a speaker of a multi-speaker model, by name or id.
+func NewPiper(cfg *config.TTSSettings) (*Piper, error) {
+	path, err := exec.LookPath("piper")
+	if err != nil {
+		return nil, fmt.Errorf("piper not found: %w", err)
+	}
+	aplayPath, err := exec.LookPath("aplay")
+	if err != nil {
+		return nil, fmt.Errorf("aplay not found (needed to play piper's audio): %w", err)
+	}
+	if _, err := os.Stat(cfg.PiperModel); err != nil {
+		return nil, fmt.Errorf("failed to find piper model: %w", err)
+	}
+
+	e := &Piper{
+		path:       path,
+		aplayPath:  aplayPath,
+		model:      cfg.PiperModel,
+		rate:       cfg.Rate,
+		volume:     cfg.Volume,
+		sampleRate: piperSampleRate,
+	}
+	if data, err := os.ReadFile(cfg.PiperModel + ".json"); err == nil {
+		var modelConfig piperModelConfig
+		if err := json.Unmarshal(data, &modelConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse piper model config: %w", err)
+		}
+		if modelConfig.Audio.SampleRate > 0 {
+			e.sampleRate = modelConfig.Audio.SampleRate
+		}
+		e.speakers = modelConfig.SpeakerIDMap
+	}
+	if cfg.Voice != "" {
+		if id, ok := e.speakers[cfg.Voice]; ok {
+			e.speaker = strconv.Itoa(id)
+		} else if _, err := strconv.Atoi(cfg.Voice); err == nil {
+			e.speaker = cfg.Voice
+		} else {
+			return nil, fmt.Errorf("piper model has no speaker %q", cfg.Voice)
+		}
+	}
+	return e, nil
+}
+
+func (e *Piper) Name() string {
+	return "piper"
+}
+
+// Speak pipes piper's raw 16-bit audio through a volume scaler into aplay
+func (e *Piper) Speak(ctx context.Context, text string) error {
+	args := []string{"--model", e.model, "--output_raw", "--length_scale", strconv.FormatFloat(1/speed(e.rate), 'f', 2, 64)}
+	if e.speaker != "" {
+		args = append(args, "--speaker", e.speaker)
+	}
+	piper := exec.CommandContext(ctx, e.path, args...)
+	piper.Stdin = strings.NewReader(text)
+	var piperStderr bytes.Buffer
+	piper.Stderr = &piperStderr
+	audio, err := piper.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start piper: %w", err)
+	}
+
+	aplay := exec.CommandContext(ctx, e.aplayPath, "-q", "-t", "raw", "-f", "S16_LE", "-c", "1", "-r", strconv.Itoa(e.sampleRate), "-")
+	speaker, err := aplay.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start aplay: %w", err)
+	}
+
+	if err := aplay.Start(); err != nil {
+		return fmt.Errorf("failed to start aplay: %w", err)
+	}
+	if err := piper.Start(); err != nil {
+		speaker.Close()
+		aplay.Wait()
+		return fmt.Errorf("failed to start piper: %w", err)
+	}
+
+	_, copyErr := io.Copy(&volumeWriter{w: speaker, volume: e.volume}, audio)
+	speaker.Close()
+	piperErr := piper.Wait()
+	aplayErr := aplay.Wait()
+
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case piperErr != nil:
+		return fmt.Errorf("failed to run piper: %w (stderr: %s)", piperErr, strings.TrimSpace(piperStderr.String()))
+	case copyErr != nil:
+		return fmt.Errorf("failed to play piper audio: %w", copyErr)
+	case aplayErr != nil:
+		return fmt.Errorf("failed to run aplay: %w", aplayErr)
+	}
+	return nil
+}
+
+// Voices lists the speakers of a multi-speaker model (none for a single-speaker model)
+func (e *Piper) Voices() ([]string, error) {
+	voices := make([]string, 0, len(e.speakers))
+	for name := range e.speakers {
+		voices = append(voices, name)
+	}
+	sort.Strings(voices)
+	return voices, nil
+}
+
+// volumeWriter scales 16-bit little-endian samples by volume/100 on their way to w
+type volumeWriter struct {
+	w      io.Writer
+	volume int
+	odd    []byte // Half a sample left over from the last write
+}
+
+func (v *volumeWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if v.volume >= 100 {
+		return v.w.Write(p)
+	}
+
+	data := append(v.odd, p...)
+	whole := len(data) &^ 1
+	v.odd = append([]byte(nil), data[whole:]...)
+	for i := 0; i < whole; i += 2 {
+		sample := int16(binary.LittleEndian.Uint16(data[i:]))
+		binary.LittleEndian.PutUint16(data[i:], uint16(int16(int(sample)*v.volume/100)))
+	}
+	if _, err := v.w.Write(data[:whole]); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,219 @@
+package tts
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// maxPending is how many announcements can wait to be spoken; past it the least important are dropped
+const maxPending = 16
+
+// Priority orders waiting announcements; higher ones are spoken first
+type Priority int
+
+const (
+	PriorityLow    Priority = iota // Nice to know, e.g. gold milestones
+	PriorityNormal                 // Timed calls, e.g. objective spawns
+	PriorityHigh                   // Spoken before anything else waiting
+)
+
+// Utterance is one announcement for a Queue
+type Utterance struct {
+	Text     string
+	Priority Priority
+	Key      string // Announcements with the same key coalesce: a newer one replaces the older ("" = never)
+}
+
+// Queue speaks announcements one at a time on a single goroutine, most important first, so they
+// never talk over each other. Say returns straight away.
+type Queue struct {
+	mu       sync.Mutex
+	engine   Engine
+	pending  []Utterance
+	speaking *Utterance         // Being spoken right now
+	cancel   context.CancelFunc // Cuts off the one being spoken
+	muted    bool
+	wake     chan struct{}
+	closed   chan struct{}
+	stopped  chan struct{} // Closed when run returns
+}
+
+// NewQueue starts a queue speaking through engine
+func NewQueue(engine Engine) *Queue {
+	q := &Queue{
+		engine:  engine,
+		wake:    make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Say queues an announcement. One with the same key as an announcement still waiting takes its
+// place, and one with the same key as the announcement being spoken cuts it off, so a stale
+// "1500 gold" is never followed by "2000 gold". Nothing is queued while muted or after Close.
+func (q *Queue) Say(u Utterance) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.isClosed() {
+		return
+	}
+	if q.muted {
+		log.Printf("TTS: Muted, not saying '%s'", u.Text)
+		return
+	}
+
+	if u.Key != "" {
+		for i := range q.pending {
+			if q.pending[i].Key == u.Key {
+				if q.pending[i].Priority > u.Priority {
+					u.Priority = q.pending[i].Priority
+				}
+				q.pending[i] = u
+				return
+			}
+		}
+		if q.speaking != nil && q.speaking.Key == u.Key {
+			q.cancel()
+		}
+	}
+
+	if len(q.pending) >= maxPending {
+		lowest := 0
+		for i := range q.pending {
+			if q.pending[i].Priority < q.pending[lowest].Priority {
+				lowest = i
+			}
+		}
+		if q.pending[lowest].Priority > u.Priority {
+			log.Printf("TTS: Queue full, dropping '%s'", u.Text)
+			return
+		}
+		log.Printf("TTS: Queue full, dropping '%s'", q.pending[lowest].Text)
+		q.pending = append(q.pending[:lowest], q.pending[lowest+1:]...)
+	}
+	q.pending = append(q.pending, u)
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// SetEngine switches engines; the announcement being spoken finishes on the old one
+func (q *Queue) SetEngine(engine Engine) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.engine = engine
+}
+
+// Engine returns the engine announcements are spoken with
+func (q *Queue) Engine() Engine {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.engine
+}
+
+// SetMuted mutes or unmutes announcements. Muting cuts off the one being spoken and drops
+// the ones waiting.
+func (q *Queue) SetMuted(muted bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.muted = muted
+	if muted {
+		q.pending = nil
+		if q.cancel != nil {
+			q.cancel()
+		}
+	}
+}
+
+// Muted reports whether announcements are muted
+func (q *Queue) Muted() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.muted
+}
+
+// Close stops the queue, cutting off the announcement being spoken, and waits for the
+// engine to stop speaking
+func (q *Queue) Close() {
+	q.mu.Lock()
+	if !q.isClosed() {
+		close(q.closed)
+		q.pending = nil
+		if q.cancel != nil {
+			q.cancel()
+		}
+	}
+	q.mu.Unlock()
+	<-q.stopped
+}
+
+// isClosed reports whether Close was called. Must be called with q.mu held.
+func (q *Queue) isClosed() bool {
+	select {
+	case <-q.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// run speaks queued announcements until the queue is closed
+func (q *Queue) run() {
+	defer close(q.stopped)
+	for {
+		select {
+		case <-q.closed:
+			return
+		case <-q.wake:
+		}
+
+		for {
+			u, engine, ctx, ok := q.next()
+			if !ok {
+				break
+			}
+			log.Printf("TTS: Speaking '%s'", u.Text)
+			if err := engine.Speak(ctx, u.Text); err != nil && ctx.Err() == nil {
+				log.Printf("TTS: Failed to say '%s': %v", u.Text, err)
+			}
+			q.done()
+		}
+	}
+}
+
+// next takes the most important announcement waiting (the oldest among equals) and marks it as being spoken
+func (q *Queue) next() (Utterance, Engine, context.Context, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return Utterance{}, nil, nil, false
+	}
+
+	best := 0
+	for i := range q.pending {
+		if q.pending[i].Priority > q.pending[best].Priority {
+			best = i
+		}
+	}
+	u := q.pending[best]
+	q.pending = append(q.pending[:best], q.pending[best+1:]...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q.speaking, q.cancel = &u, cancel
+	return u, q.engine, ctx, true
+}
+
+// done clears the announcement that was being spoken
+func (q *Queue) done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.cancel != nil {
+		q.cancel()
+	}
+	q.speaking, q.cancel = nil, nil
+}
@@ -0,0 +1,163 @@
+package tts
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// gatedEngine records what it says like Recorder, but holds each announcement until it is
+// released or cut off, so tests can queue announcements behind it
+type gatedEngine struct {
+	Recorder
+	started chan string   // Each announcement as it starts
+	release chan struct{} // Each send lets one announcement finish
+	cut     chan string   // Each announcement that was cut off
+}
+
+func newGatedEngine() *gatedEngine {
+	return &gatedEngine{
+		started: make(chan string, 32),
+		release: make(chan struct{}, 32),
+		cut:     make(chan string, 32),
+	}
+}
+
+func (e *gatedEngine) Speak(ctx context.Context, text string) error {
+	e.Recorder.Speak(ctx, text)
+	e.started <- text
+	select {
+	case <-e.release:
+		return nil
+	case <-ctx.Done():
+		e.cut <- text
+		return ctx.Err()
+	}
+}
+
+func receive(t *testing.T, ch <-chan string) string {
+	t.Helper()
+	select {
+	case text := <-ch:
+		return text
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the queue")
+		return ""
+	}
+}
+
+// speakInOrder lets n announcements finish and returns them in the order they were spoken
+func speakInOrder(t *testing.T, engine *gatedEngine, n int) []string {
+	t.Helper()
+	var spoken []string
+	for i := 0; i < n; i++ {
+		spoken = append(spoken, receive(t, engine.started))
+		engine.release <- struct{}{}
+	}
+	return spoken
+}
+
+// holdQueue starts a queue that is busy saying "hold" until it is released
+func holdQueue(t *testing.T) (*Queue, *gatedEngine) {
+	t.Helper()
+	engine := newGatedEngine()
+	q := NewQueue(engine)
+	t.Cleanup(q.Close)
+	q.Say(Utterance{Text: "hold"})
+	if got := receive(t, engine.started); got != "hold" {
+		t.Fatalf("expected to start with %q, got %q", "hold", got)
+	}
+	return q, engine
+}
+
+func TestPriorityOrder(t *testing.T) {
+	q, engine := holdQueue(t)
+	q.Say(Utterance{Text: "low", Priority: PriorityLow})
+	q.Say(Utterance{Text: "normal 1", Priority: PriorityNormal})
+	q.Say(Utterance{Text: "high", Priority: PriorityHigh})
+	q.Say(Utterance{Text: "normal 2", Priority: PriorityNormal})
+	engine.release <- struct{}{} // Finish "hold"
+
+	got := speakInOrder(t, engine, 4)
+	want := []string{"high", "normal 1", "normal 2", "low"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("spoken in order %v, want %v", got, want)
+	}
+}
+
+func TestKeyCoalescing(t *testing.T) {
+	q, engine := holdQueue(t)
+	q.Say(Utterance{Text: "1500 gold", Key: "gold", Priority: PriorityHigh})
+	q.Say(Utterance{Text: "Dragon in 30 seconds", Priority: PriorityNormal})
+	q.Say(Utterance{Text: "2000 gold", Key: "gold", Priority: PriorityLow})
+	engine.release <- struct{}{}
+
+	// The newer text takes the older one's place, keeping its higher priority
+	got := speakInOrder(t, engine, 2)
+	want := []string{"2000 gold", "Dragon in 30 seconds"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("spoken in order %v, want %v", got, want)
+	}
+}
+
+func TestSameKeyCutsOffSpeaking(t *testing.T) {
+	engine := newGatedEngine()
+	q := NewQueue(engine)
+	t.Cleanup(q.Close)
+
+	q.Say(Utterance{Text: "1500 gold", Key: "gold"})
+	receive(t, engine.started)
+	q.Say(Utterance{Text: "2000 gold", Key: "gold"})
+
+	if got := receive(t, engine.cut); got != "1500 gold" {
+		t.Fatalf("expected %q to be cut off, got %q", "1500 gold", got)
+	}
+	if got := receive(t, engine.started); got != "2000 gold" {
+		t.Fatalf("expected %q next, got %q", "2000 gold", got)
+	}
+}
+
+func TestMuteDropsPending(t *testing.T) {
+	q, engine := holdQueue(t)
+	q.Say(Utterance{Text: "dropped 1"})
+	q.Say(Utterance{Text: "dropped 2"})
+
+	q.SetMuted(true)
+	if got := receive(t, engine.cut); got != "hold" {
+		t.Fatalf("expected muting to cut off %q, got %q", "hold", got)
+	}
+	q.Say(Utterance{Text: "while muted"})
+	q.SetMuted(false)
+	q.Say(Utterance{Text: "after unmute"})
+
+	if got := receive(t, engine.started); got != "after unmute" {
+		t.Fatalf("expected only %q after unmuting, got %q", "after unmute", got)
+	}
+	want := []string{"hold", "after unmute"}
+	if got := engine.Spoken(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("spoken %v, want %v", got, want)
+	}
+}
+
+func TestClose(t *testing.T) {
+	q, engine := holdQueue(t)
+	q.Say(Utterance{Text: "never said"})
+
+	q.Close() // Returns only once the engine has stopped
+	select {
+	case got := <-engine.cut:
+		if got != "hold" {
+			t.Fatalf("expected %q to be cut off, got %q", "hold", got)
+		}
+	default:
+		t.Fatal("Close returned before the announcement being spoken was cut off")
+	}
+
+	q.Say(Utterance{Text: "after close"})
+	q.Close() // Closing twice is fine
+	want := []string{"hold"}
+	if got := engine.Spoken(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("spoken %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,74 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"lol-kind-bot/config"
+	"os/exec"
+	"strings"
+)
+
+// SAPI speaks through Windows SAPI (System.Speech) via PowerShell
+type SAPI struct {
+	voice  string
+	rate   int // -10 to 10, as SAPI takes it
+	volume int // 0 to 100
+}
+
+// NewSAPI creates a SAPI engine with the voice, rate and volume from the settings
+func NewSAPI(cfg *config.TTSSettings) *SAPI {
+	return &SAPI{voice: cfg.Voice, rate: cfg.Rate, volume: cfg.Volume}
+}
+
+func (e *SAPI) Name() string {
+	return "sapi"
+}
+
+// Speak runs one PowerShell process per utterance
+func (e *SAPI) Speak(ctx context.Context, text string) error {
+	script := fmt.Sprintf("Add-Type -AssemblyName System.Speech; $speak = New-Object System.Speech.Synthesis.SpeechSynthesizer; $speak.Rate = %d; $speak.Volume = %d; ", e.rate, e.volume)
+	if e.voice != "" {
+		script += fmt.Sprintf("$speak.SelectVoice('%s'); ", quotePowerShell(e.voice))
+	}
+	script += fmt.Sprintf("$speak.Speak('%s')", quotePowerShell(text))
+
+	_, err := e.powerShell(ctx, script)
+	return err
+}
+
+// Voices lists the installed SAPI voices
+func (e *SAPI) Voices() ([]string, error) {
+	output, err := e.powerShell(context.Background(), "Add-Type -AssemblyName System.Speech; (New-Object System.Speech.Synthesis.SpeechSynthesizer).GetInstalledVoices() | ForEach-Object { $_.VoiceInfo.Name }")
+	if err != nil {
+		return nil, err
+	}
+	var voices []string
+	for _, line := range strings.Split(output, "\n") {
+		if voice := strings.TrimSpace(line); voice != "" {
+			voices = append(voices, voice)
+		}
+	}
+	return voices, nil
+}
+
+// powerShell runs a PowerShell script and returns its output
+func (e *SAPI) powerShell(ctx context.Context, script string) (string, error) {
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-Command", script)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("failed to run SAPI: %w (stderr: %s)", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// quotePowerShell escapes text for a single-quoted PowerShell string
+func quotePowerShell(text string) string {
+	return strings.ReplaceAll(text, "'", "''")
+}
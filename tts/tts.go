@@ -0,0 +1,52 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"lol-kind-bot/config"
+	"math"
+	"runtime"
+)
+
+// Engine speaks text aloud
+type Engine interface {
+	Name() string
+	Speak(ctx context.Context, text string) error // Blocks until the text is spoken; cancelling ctx cuts it off
+	Voices() ([]string, error)                    // Names the Voice setting accepts
+}
+
+// New creates the engine chosen in the settings. "auto" picks SAPI on Windows, and piper (when a
+// model is set) or espeak-ng elsewhere.
+func New(cfg *config.TTSSettings) (Engine, error) {
+	switch cfg.Engine {
+	case "none":
+		return Null{}, nil
+	case "sapi":
+		if runtime.GOOS != "windows" {
+			return nil, fmt.Errorf("the sapi engine is only available on Windows")
+		}
+		return NewSAPI(cfg), nil
+	case "espeak":
+		return NewESpeak(cfg)
+	case "piper":
+		return NewPiper(cfg)
+	}
+
+	if runtime.GOOS == "windows" {
+		return NewSAPI(cfg), nil
+	}
+	if cfg.PiperModel != "" {
+		if engine, err := NewPiper(cfg); err == nil {
+			return engine, nil
+		}
+	}
+	if engine, err := NewESpeak(cfg); err == nil {
+		return engine, nil
+	}
+	return nil, fmt.Errorf("no text-to-speech engine found (install espeak-ng, or piper and set tts.piperModel)")
+}
+
+// speed turns a -10 to 10 rate into a speed factor from 0.5 to 2 (1 = normal)
+func speed(rate int) float64 {
+	return math.Pow(2, float64(rate)/10)
+}
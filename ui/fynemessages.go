@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"fmt"
 	"log"
+	"lol-kind-bot/config"
+	"lol-kind-bot/llm"
 	"strings"
 	"time"
 
@@ -9,9 +12,31 @@ import (
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 )
 
+// MessageSource is the set of suggestions the messages dialog shows and acts on
+// (implemented by app.MessageSet). Indexes refer to Suggestions().
+type MessageSource interface {
+	Suggestions() []llm.Suggestion
+	IsPinned(text string) bool
+	Edit(i int, text string) error
+	Copied(text string)
+	SetPinned(text string, pinned bool)
+	Rewrite(i int, tone string) (llm.Suggestion, error)
+	RegenerateAll(tone string) ([]llm.Suggestion, error)
+	MoreLike(i int) (llm.Suggestion, error)
+	Rate(i int, up bool)
+	Dismissed()
+	Generating() bool
+	Drafts() []llm.Suggestion
+	StopGenerating()
+	ChatReplies() []llm.ChatReply
+	SendReply(i int, text string) error
+	OnChange(fn func()) func()
+}
+
 // normalizeText fixes special character rendering issues in Fyne labels
 func normalizeText(text string) string {
 	// Replace em dashes and en dashes with regular dashes
@@ -26,14 +51,34 @@ func normalizeText(text string) string {
 	return text
 }
 
-// ShowMessagesDialogFyne shows a beautiful Fyne messages dialog
-func ShowMessagesDialogFyne(messages []string) {
-	if len(messages) == 0 {
+// messagesDialog is the state of an open messages window
+type messagesDialog struct {
+	source MessageSource
+
+	rows       *fyne.Container
+	drafts     *fyne.Container // Messages still being written
+	replies    *fyne.Container // Post-game chat lines with suggested replies
+	repliesKey string          // What the replies section shows, to skip needless rebuilds
+	rendered   []string        // Suggestion texts at the last render
+	entries    []*widget.Entry
+	shown      []string // Text each entry started with, to detect our edits
+	actions    []*widget.Button
+	toneSelect *widget.Select
+	status     *widget.Label
+	stopButton *widget.Button
+	busy       bool // An action started by run is in progress
+}
+
+// ShowMessagesDialogFyne shows the message suggestions window and blocks until it is closed.
+// Messages can be edited, copied, pinned, regenerated in another tone or extended with "more like this".
+func ShowMessagesDialogFyne(source MessageSource) {
+	suggestions := source.Suggestions()
+	if len(suggestions) == 0 {
 		log.Printf("ShowMessagesDialog called with empty messages")
 		return
 	}
 
-	log.Printf("ShowMessagesDialog called with %d messages", len(messages))
+	log.Printf("ShowMessagesDialog called with %d messages", len(suggestions))
 
 	// Show toast notification
 	ShowToast("LoL Kind Bot", "Post-game messages ready!")
@@ -55,119 +100,94 @@ func ShowMessagesDialogFyne(messages []string) {
 		}
 	}
 
-	// Use fyne.DoAndWait() to create window and widgets (like first-run dialog)
 	done := make(chan bool, 1)
 
-	var window fyne.Window
-	var messageList *widget.List
-	var copyButton *widget.Button
-	var closeButton *widget.Button
-	var content fyne.CanvasObject
-
 	fyne.DoAndWait(func() {
-
-		// Create window
-		window = app.NewWindow("LoL Kind Bot - Message Suggestions")
+		window := app.NewWindow("LoL Kind Bot - Message Suggestions")
 		window.Resize(fyne.NewSize(700, 550))
 		window.CenterOnScreen()
 		window.SetFixedSize(false)
-		// Create list widget for messages with glass styling
-		messageList = widget.NewList(
-			func() int {
-				return len(messages)
-			},
-			func() fyne.CanvasObject {
-				label := widget.NewLabel("")
-				label.Wrapping = fyne.TextWrapWord
-				return label
-			},
-			func(id widget.ListItemID, obj fyne.CanvasObject) {
-				label := obj.(*widget.Label)
-				// Normalize special characters to prevent rendering issues
-				text := normalizeText(messages[id])
-				label.SetText(text)
-				label.Wrapping = fyne.TextWrapWord
-			},
-		)
 
-		// Track selected index
-		var selectedIndex int = 0
-		messageList.OnSelected = func(id widget.ListItemID) {
-			selectedIndex = id
-		}
+		d := &messagesDialog{source: source}
+		d.rows = container.NewVBox()
+		d.drafts = container.NewVBox()
+		d.replies = container.NewVBox()
+		d.status = widget.NewLabel("")
+		d.stopButton = widget.NewButton("Stop", func() {
+			d.stopButton.Disable()
+			d.status.SetText("Stopping...")
+			source.StopGenerating()
+		})
+		d.toneSelect = widget.NewSelect(config.AllowedTones, nil)
+		d.toneSelect.PlaceHolder = "Same tone"
 
-		// Copy function
-		copyMessage := func() {
-			if selectedIndex >= 0 && selectedIndex < len(messages) {
-				copyMessageToClipboardFyne(messages[selectedIndex])
-			} else if len(messages) > 0 {
-				copyMessageToClipboardFyne(messages[0])
-			}
-		}
+		regenerateAllButton := widget.NewButton("Regenerate All", func() {
+			tone := d.toneSelect.Selected
+			d.run("Regenerating all messages...", func() error {
+				_, err := source.RegenerateAll(tone)
+				return err
+			})
+		})
 
-		// Copy Selected button
-		copyButton = widget.NewButton("Copy Selected", copyMessage)
-		copyButton.Importance = widget.MediumImportance
+		// Better messages replace the instant ones as they finish generating, and are shown
+		// while they are written
+		stopWatching := source.OnChange(func() {
+			fyne.Do(func() {
+				d.saveEdits()
+				if d.suggestionsChanged() {
+					d.render()
+				}
+				d.renderDrafts()
+				d.renderReplies()
+				d.updateStatus()
+			})
+		})
 
-		// Close button
-		closeButton = widget.NewButton("Close", func() {
+		closeDialog := func() {
+			stopWatching()
+			d.saveEdits()
+			source.Dismissed()
 			done <- true
+		}
+		closeButton := widget.NewButton("Close", func() {
+			closeDialog()
 			// Close window directly - we're already in Fyne context
 			window.Close()
 		})
 		closeButton.Importance = widget.HighImportance
 
-		// Beautiful instructions card with glass styling
-		instructionsLabel := widget.NewRichTextFromMarkdown("**Double-click** a message to copy it, or select and click **Copy Selected**")
+		instructionsLabel := widget.NewRichTextFromMarkdown("Edit a message before copying it, **pin** your favourites, or regenerate in another tone")
 		instructionsLabel.Wrapping = fyne.TextWrapWord
-		instructionsCard := widget.NewCard("", "", container.NewPadded(instructionsLabel))
-
-		// Beautiful button bar with glass background
-		buttonBar := container.NewBorder(
-			nil, nil,
-			nil,
-			container.NewHBox(
-				container.NewPadded(widget.NewLabel("")), // Spacer
-				container.NewPadded(copyButton),
-				container.NewPadded(closeButton),
-			),
-		)
+		toneBar := container.NewHBox(widget.NewLabel("Tone:"), d.toneSelect, regenerateAllButton)
+		header := widget.NewCard("", "", container.NewVBox(
+			container.NewPadded(instructionsLabel),
+			container.NewPadded(toneBar),
+		))
 
-		// Create a glass-styled container for the message list
-		messageContainer := container.NewPadded(
-			container.NewBorder(
-				nil, nil, nil, nil,
-				messageList,
-			),
-		)
-
-		// Layout with glass morphism effects
-		content = container.NewBorder(
-			container.NewPadded(instructionsCard),
+		buttonBar := container.NewBorder(nil, nil, container.NewPadded(container.NewHBox(d.status, d.stopButton)), container.NewPadded(closeButton))
+		content := container.NewBorder(
+			container.NewPadded(header),
 			buttonBar,
 			nil,
 			nil,
-			messageContainer,
+			container.NewVScroll(container.NewPadded(container.NewVBox(d.rows, d.drafts, d.replies))),
 		)
+		d.actions = []*widget.Button{regenerateAllButton}
+		d.render()
+		d.renderDrafts()
+		d.renderReplies()
+		d.updateStatus()
 
 		// Apply Windows glass effect (Mica/Acrylic blur)
 		ApplyGlassEffect(window)
-
-		// Set content directly - effects can cause rendering issues
 		window.SetContent(content)
 
-		// Select first item
-		if len(messages) > 0 {
-			messageList.Select(0)
-		}
-
 		window.SetCloseIntercept(func() {
-			done <- true
-			// Don't call window.Close() here - it's already closing
+			closeDialog()
+			window.Close()
 		})
 
 		// Show window and bring to front
-		// app.Run() is running in a goroutine, so Show() will work properly
 		window.Show()
 		window.RequestFocus()
 		window.CenterOnScreen()
@@ -177,18 +197,268 @@ func ShowMessagesDialogFyne(messages []string) {
 	<-done
 }
 
+// render rebuilds the message rows from the source. Must run on the Fyne thread.
+func (d *messagesDialog) render() {
+	suggestions := d.source.Suggestions()
+	d.rendered = llm.SuggestionTexts(suggestions)
+	d.entries = d.entries[:0]
+	d.shown = d.shown[:0]
+	d.actions = d.actions[:1] // Keep "Regenerate All"
+	d.rows.RemoveAll()
+
+	for i, s := range suggestions {
+		d.rows.Add(d.messageRow(i, s))
+	}
+	d.rows.Refresh()
+}
+
+// suggestionsChanged reports whether the source's suggestions differ from the rendered ones
+func (d *messagesDialog) suggestionsChanged() bool {
+	texts := llm.SuggestionTexts(d.source.Suggestions())
+	if len(texts) != len(d.rendered) {
+		return true
+	}
+	for i := range texts {
+		if texts[i] != d.rendered[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// renderDrafts shows the messages still being written. Must run on the Fyne thread.
+func (d *messagesDialog) renderDrafts() {
+	d.drafts.RemoveAll()
+	for _, s := range d.source.Drafts() {
+		text := widget.NewLabel(normalizeText(s.Text) + " ▌")
+		text.Wrapping = fyne.TextWrapWord
+		d.drafts.Add(widget.NewCard("", fmt.Sprintf("Writing a message for %s...", s.Champion), text))
+	}
+	d.drafts.Refresh()
+}
+
+// renderReplies shows the post-game chat lines with a Send and Copy button for each suggested
+// reply. Must run on the Fyne thread.
+func (d *messagesDialog) renderReplies() {
+	replies := d.source.ChatReplies()
+	key := fmt.Sprint(replies)
+	if key == d.repliesKey {
+		return
+	}
+	d.repliesKey = key
+
+	d.replies.RemoveAll()
+	if len(replies) > 0 {
+		title := widget.NewLabel("Post-game chat")
+		title.TextStyle = fyne.TextStyle{Bold: true}
+		d.replies.Add(title)
+	}
+	for i, reply := range replies {
+		d.replies.Add(d.replyCard(i, reply))
+	}
+	d.replies.Refresh()
+}
+
+// replyCard builds the card for one post-game chat line
+func (d *messagesDialog) replyCard(i int, reply llm.ChatReply) fyne.CanvasObject {
+	subtitle := reply.Intent
+	if reply.Intent == llm.IntentToxic {
+		subtitle += " - keep it calm"
+	}
+	line := widget.NewLabel(fmt.Sprintf("%s: %s", reply.From, normalizeText(reply.Text)))
+	line.Wrapping = fyne.TextWrapWord
+	items := []fyne.CanvasObject{line}
+
+	if reply.Answered != "" {
+		sent := widget.NewLabel("You replied: " + normalizeText(reply.Answered))
+		sent.TextStyle = fyne.TextStyle{Italic: true}
+		sent.Wrapping = fyne.TextWrapWord
+		items = append(items, sent)
+		return widget.NewCard("", subtitle, container.NewVBox(items...))
+	}
+
+	for _, text := range reply.Replies {
+		text := text
+		label := widget.NewLabel(normalizeText(text))
+		label.Wrapping = fyne.TextWrapWord
+		var sendButton *widget.Button
+		sendButton = widget.NewButton("Send", func() {
+			sendButton.Disable()
+			go func() {
+				err := d.source.SendReply(i, text)
+				fyne.Do(func() {
+					if err != nil {
+						log.Printf("Failed to send reply: %v", err)
+						d.status.SetText("Failed: " + err.Error())
+						sendButton.Enable()
+					}
+				})
+			}()
+		})
+		sendButton.Importance = widget.HighImportance
+		copyButton := widget.NewButton("Copy", func() {
+			copyMessageToClipboardFyne(text)
+		})
+		items = append(items, container.NewBorder(nil, nil, nil, container.NewHBox(sendButton, copyButton), label))
+	}
+	return widget.NewCard("", subtitle, container.NewVBox(items...))
+}
+
+// messageRow builds the card for one suggestion
+func (d *messagesDialog) messageRow(i int, s llm.Suggestion) fyne.CanvasObject {
+	text := normalizeText(s.Text)
+	entry := widget.NewMultiLineEntry()
+	entry.Wrapping = fyne.TextWrapWord
+	entry.SetText(text)
+	entry.SetMinRowsVisible(2)
+	d.entries = append(d.entries, entry)
+	d.shown = append(d.shown, text)
+
+	pinned := d.source.IsPinned(s.Text)
+	title := fmt.Sprintf("Message %d", i+1)
+	if pinned {
+		title = "★ " + title
+	}
+
+	copyButton := widget.NewButton("Copy", func() {
+		d.saveEdits()
+		if copyMessageToClipboardFyne(entry.Text) == nil {
+			d.source.Copied(entry.Text)
+		}
+	})
+	copyButton.Importance = widget.MediumImportance
+
+	pinLabel := "☆ Pin"
+	if pinned {
+		pinLabel = "★ Unpin"
+	}
+	pinButton := widget.NewButton(pinLabel, func() {
+		d.saveEdits()
+		if current := d.source.Suggestions(); i < len(current) {
+			d.source.SetPinned(current[i].Text, !pinned)
+		}
+		d.render()
+	})
+
+	regenerateButton := widget.NewButton("Regenerate", func() {
+		tone := d.toneSelect.Selected
+		d.run(fmt.Sprintf("Regenerating message %d...", i+1), func() error {
+			_, err := d.source.Rewrite(i, tone)
+			return err
+		})
+	})
+	moreButton := widget.NewButton("More Like This", func() {
+		d.run(fmt.Sprintf("Writing more like message %d...", i+1), func() error {
+			_, err := d.source.MoreLike(i)
+			return err
+		})
+	})
+	d.actions = append(d.actions, regenerateButton, moreButton)
+
+	reason := widget.NewLabel(normalizeText(s.Reason()))
+	reason.TextStyle = fyne.TextStyle{Italic: true}
+	reason.Wrapping = fyne.TextWrapWord
+
+	items := []fyne.CanvasObject{entry, reason}
+	if s.Testimony != nil && s.Testimony.Testimony != "" {
+		why := normalizeText(s.Testimony.Testimony)
+		if len(s.Testimony.KeyPoints) > 0 {
+			why += "\n\n- " + normalizeText(strings.Join(s.Testimony.KeyPoints, "\n- "))
+		}
+		whyLabel := widget.NewLabel(why)
+		whyLabel.Wrapping = fyne.TextWrapWord
+		items = append(items, widget.NewAccordion(widget.NewAccordionItem("Why this message", whyLabel)))
+	}
+	var upButton, downButton *widget.Button
+	rate := func(up bool) {
+		d.saveEdits()
+		d.source.Rate(i, up)
+		upButton.Disable()
+		downButton.Disable()
+	}
+	upButton = widget.NewButton("👍", func() { rate(true) })
+	downButton = widget.NewButton("👎", func() { rate(false) })
+
+	items = append(items, container.NewHBox(copyButton, pinButton, regenerateButton, moreButton, layout.NewSpacer(), upButton, downButton))
+
+	return widget.NewCard(title, "", container.NewVBox(items...))
+}
+
+// saveEdits passes any edited messages to the source. Must run on the Fyne thread.
+func (d *messagesDialog) saveEdits() {
+	for i, entry := range d.entries {
+		if entry.Text == d.shown[i] {
+			continue
+		}
+		if err := d.source.Edit(i, entry.Text); err != nil {
+			log.Printf("Failed to save edited message: %v", err)
+			continue
+		}
+		d.shown[i] = entry.Text
+	}
+}
+
+// run performs a slow action (an LLM call) in the background with the action buttons
+// disabled, then re-renders the messages. Must be called on the Fyne thread.
+func (d *messagesDialog) run(status string, action func() error) {
+	d.saveEdits()
+	d.busy = true
+	d.stopButton.Hide()
+	d.status.SetText(status)
+	for _, button := range d.actions {
+		button.Disable()
+	}
+
+	go func() {
+		err := action()
+		fyne.Do(func() {
+			d.busy = false
+			d.updateStatus()
+			if err != nil {
+				log.Printf("Message action failed: %v", err)
+				d.status.SetText("Failed: " + err.Error())
+			}
+			for _, button := range d.actions {
+				button.Enable()
+			}
+			d.render()
+			d.renderDrafts()
+		})
+	}()
+}
+
+// updateStatus shows whether better messages are still being written, with a button to stop
+// once a good one is visible. Leaves the status of a running action alone. Must run on the Fyne thread.
+func (d *messagesDialog) updateStatus() {
+	if d.busy {
+		return
+	}
+	if !d.source.Generating() {
+		d.status.SetText("")
+		d.stopButton.Hide()
+		d.stopButton.Enable()
+		return
+	}
+	if d.stopButton.Disabled() {
+		return // Stopping
+	}
+	d.status.SetText("Writing better messages...")
+	d.stopButton.Show()
+}
+
 // Wrapper function to maintain compatibility
-func ShowMessagesDialog(messages []string) {
-	ShowMessagesDialogFyne(messages)
+func ShowMessagesDialog(source MessageSource) {
+	ShowMessagesDialogFyne(source)
 }
 
 // copyMessageToClipboardFyne copies a message to clipboard and shows feedback
-func copyMessageToClipboardFyne(message string) {
+func copyMessageToClipboardFyne(message string) error {
 	if err := clipboard.WriteAll(message); err != nil {
 		log.Printf("Failed to copy to clipboard: %v", err)
 		ShowToast("LoL Kind Bot", "Failed to copy message")
-	} else {
-		log.Printf("Copied to clipboard: %s", message)
-		ShowToast("LoL Kind Bot", "Message copied to clipboard!")
+		return err
 	}
+	log.Printf("Copied to clipboard: %s", message)
+	ShowToast("LoL Kind Bot", "Message copied to clipboard!")
+	return nil
 }
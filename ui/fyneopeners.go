@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// OpenerSource is the champion select openers the openers window shows and sends
+// (implemented by app.OpenerSet)
+type OpenerSource interface {
+	Openers() []string
+	Generating() bool
+	Sent() string
+	Send(text string) error
+	OnChange(fn func()) func()
+	Done() <-chan struct{} // Closed when champion select ends
+}
+
+// ShowOpenersDialog shows the champion select openers with a Send and Copy button for each,
+// and blocks until the window is closed or champion select ends
+func ShowOpenersDialog(source OpenerSource) {
+	app := GetFyneApp()
+	if app == nil {
+		log.Printf("Fyne app not available")
+		return
+	}
+	if !isAppReady() {
+		select {
+		case <-appReady:
+		case <-time.After(3 * time.Second):
+		}
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+
+	fyne.DoAndWait(func() {
+		window := app.NewWindow("LoL Kind Bot - Champ Select")
+		window.Resize(fyne.NewSize(520, 380))
+		window.CenterOnScreen()
+
+		rows := container.NewVBox()
+		status := widget.NewLabel("")
+		rendered := ""
+
+		render := func() {
+			openers, sent, generating := source.Openers(), source.Sent(), source.Generating()
+			key := fmt.Sprint(openers, sent, generating)
+			if key == rendered {
+				return
+			}
+			rendered = key
+
+			rows.RemoveAll()
+			if sent != "" {
+				label := widget.NewLabel("You said: " + normalizeText(sent))
+				label.TextStyle = fyne.TextStyle{Italic: true}
+				label.Wrapping = fyne.TextWrapWord
+				rows.Add(widget.NewCard("", "Sent to your team", label))
+			}
+			for _, text := range openers {
+				if sent != "" {
+					break
+				}
+				text := text
+				label := widget.NewLabel(normalizeText(text))
+				label.Wrapping = fyne.TextWrapWord
+				var sendButton *widget.Button
+				sendButton = widget.NewButton("Send", func() {
+					sendButton.Disable()
+					go func() {
+						err := source.Send(text)
+						fyne.Do(func() {
+							if err != nil {
+								log.Printf("Failed to send opener: %v", err)
+								status.SetText("Failed: " + err.Error())
+								sendButton.Enable()
+							}
+						})
+					}()
+				})
+				sendButton.Importance = widget.HighImportance
+				copyButton := widget.NewButton("Copy", func() {
+					copyMessageToClipboardFyne(text)
+				})
+				rows.Add(widget.NewCard("", "", container.NewBorder(nil, nil, nil, container.NewHBox(sendButton, copyButton), label)))
+			}
+			rows.Refresh()
+
+			if generating {
+				status.SetText("Writing openers for your team...")
+			} else {
+				status.SetText("")
+			}
+		}
+
+		stopWatching := source.OnChange(func() {
+			fyne.Do(render)
+		})
+		closeWindow := func() {
+			once.Do(func() {
+				stopWatching()
+				close(done)
+				window.Close()
+			})
+		}
+		go func() {
+			select {
+			case <-source.Done():
+				fyne.Do(closeWindow) // The game is starting
+			case <-done:
+			}
+		}()
+
+		closeButton := widget.NewButton("Close", closeWindow)
+		header := widget.NewLabel("Say hi to your team before the game starts")
+		header.Wrapping = fyne.TextWrapWord
+		content := container.NewBorder(
+			container.NewPadded(header),
+			container.NewBorder(nil, nil, container.NewPadded(status), container.NewPadded(closeButton)),
+			nil,
+			nil,
+			container.NewVScroll(container.NewPadded(rows)),
+		)
+		render()
+
+		ApplyGlassEffect(window)
+		window.SetContent(content)
+		window.SetCloseIntercept(closeWindow)
+		window.Show()
+		window.RequestFocus()
+	})
+
+	<-done
+}
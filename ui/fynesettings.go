@@ -1,10 +1,17 @@
 package ui
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"lol-kind-bot/analyzer"
 	"lol-kind-bot/config"
 	"lol-kind-bot/lcu"
+	"lol-kind-bot/llm"
+	"lol-kind-bot/tts"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -14,9 +21,89 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-// ShowSettingsDialogWithCallbackFyne shows a beautiful Fyne settings window
-func ShowSettingsDialogWithCallbackFyne(cfg *config.Config, generateCallback func()) (*config.Config, bool) {
-	log.Printf("ShowSettingsDialogWithCallbackFyne called")
+// SettingsOptions configures the optional parts of the settings window
+type SettingsOptions struct {
+	OnGenerate     func()                  // Called by "Generate Messages from Last Match"; button hidden if nil
+	PreviewSummary *analyzer.GameSummary   // Game used for the prompt preview; a sample game if nil
+	LoadErrors     *config.ValidationError // Problems found in config.json, whose settings are shown at their defaults
+}
+
+// settingsForm holds the settings window's widgets, one group per tab
+type settingsForm struct {
+	// General
+	summonerName    *widget.Entry
+	autoCopy        *widget.Check
+	replyAssistant  *widget.Check
+	champOpeners    *widget.Check
+	pollInterval    *widget.Entry
+	eogCooldown     *widget.Entry
+	detailedLogging *widget.Check
+	debugLogging    *widget.Check
+
+	// LLM
+	model            *widget.SelectEntry
+	url              *widget.Entry
+	temperature      *widget.Slider
+	temperatureLabel *widget.Label
+	maxTokens        *widget.Entry
+	connectionStatus *widget.Label
+
+	// Messages
+	tone               *widget.Select
+	languageStyle      *widget.Select
+	language           *widget.Select
+	minMessages        *widget.Entry
+	maxMessages        *widget.Entry
+	maxMessageLength   *widget.Entry
+	focusAreas         *widget.CheckGroup
+	afkHandling        *widget.Select
+	customInstructions *widget.Entry
+	feedbackEnabled    *widget.Check
+	adaptTone          *widget.Check
+	adaptLength        *widget.Check
+	feedbackMinSamples *widget.Entry
+
+	// AFK detection
+	afkMinGameMinutes *widget.Entry
+	afkMaxCsPerMin    *widget.Entry
+	afkMaxDamage      *widget.Entry
+	afkMaxGold        *widget.Entry
+
+	// Gold announcements
+	goldEnabled      *widget.Check
+	goldMode         *widget.Select
+	goldThresholds   *widget.Entry
+	goldPollInterval *widget.Entry
+	goldAlmost       *widget.Entry
+	goldBuilds       *widget.Entry
+
+	// Objective timers
+	objectivesEnabled      *widget.Check
+	objectivesPollInterval *widget.Entry
+	objectiveChecks        map[string]*widget.Check // Keyed by config.ObjectiveKinds
+	objectiveLeads         map[string]*widget.Entry
+
+	// Voice
+	ttsEngine      *widget.Select
+	ttsVoice       *widget.SelectEntry
+	ttsRateLabel   *widget.Label
+	ttsRate        *widget.Slider
+	ttsVolumeLabel *widget.Label
+	ttsVolume      *widget.Slider
+	ttsPiperModel  *widget.Entry
+	ttsMuteHotkey  *widget.Entry
+	ttsStatus      *widget.Label
+
+	// Prompt preview
+	preview     *widget.Label
+	previewJSON string
+	base        *config.Config // Settings not shown in the window (profiles, API, webhooks) are kept from here
+}
+
+// ShowSettingsWindow shows the tabbed Fyne settings window and blocks until it is closed.
+// It returns the edited config and true if the user saved.
+func ShowSettingsWindow(cfg *config.Config, opts SettingsOptions) (*config.Config, bool) {
+	log.Printf("ShowSettingsWindow called")
 
 	// Get Fyne app and ensure it's ready
 	app := getFyneApp()
@@ -36,7 +123,7 @@ func ShowSettingsDialogWithCallbackFyne(cfg *config.Config, generateCallback fun
 	}
 
 	// Create a copy of config for editing (non-Fyne operation, can happen outside UI thread)
-	editCfg := *cfg
+	editCfg := cfg.Clone()
 
 	// Try to auto-detect summoner name if empty (non-Fyne operation)
 	if editCfg.MySummonerName == "" {
@@ -50,253 +137,65 @@ func ShowSettingsDialogWithCallbackFyne(cfg *config.Config, generateCallback fun
 		}
 	}
 
-	// Message Style dropdown options (non-Fyne data)
-	styleOptions := []string{"Casual & Friendly", "Professional", "Enthusiastic", "Humble & Supportive"}
-	styleMapping := []struct {
-		tone          string
-		languageStyle string
-	}{
-		{"friendly", "casual"},
-		{"professional", "formal"},
-		{"enthusiastic", "enthusiastic"},
-		{"humble", "casual"},
-	}
-
-	// Find current style index (non-Fyne operation)
-	currentStyleIdx := 0
-	for i, style := range styleMapping {
-		if style.tone == editCfg.LLMSettings.Tone && style.languageStyle == editCfg.LLMSettings.LanguageStyle {
-			currentStyleIdx = i
-			break
-		}
+	// Game used for the prompt preview
+	previewSummary := opts.PreviewSummary
+	if previewSummary == nil {
+		previewSummary = samplePreviewSummary(editCfg.MySummonerName)
 	}
+	previewJSON, _ := json.MarshalIndent(previewSummary, "", "  ")
 
-	// Number of Messages dropdown options (non-Fyne data)
-	msgCountOptions := []string{"2", "3", "4", "5"}
-	currentMsgCount := editCfg.LLMSettings.MaxMessages
-	if editCfg.LLMSettings.MinMessages != editCfg.LLMSettings.MaxMessages {
-		currentMsgCount = (editCfg.LLMSettings.MinMessages + editCfg.LLMSettings.MaxMessages) / 2
-	}
-	currentMsgIdx := 0
-	for i, count := range []int{2, 3, 4, 5} {
-		if count == currentMsgCount {
-			currentMsgIdx = i
-			break
-		}
-	}
-	if currentMsgIdx == 0 && currentMsgCount != 2 {
-		currentMsgIdx = 1 // Default to 3
-	}
-
-	// Use fyne.DoAndWait() to create window and widgets (like first-run dialog)
-	// Then use ShowAndRun() inside fyne.DoAndWait() to start the event loop
 	var resultCfg *config.Config
 	var accepted bool
 	done := make(chan bool, 1)
 
-	var window fyne.Window
-	var summonerNameEntry *widget.Entry
-	var autoCopyCheck *widget.Check
-	var generalForm fyne.CanvasObject
-	var modelEntry *widget.Entry
-	var urlEntry *widget.Entry
-	var apiForm fyne.CanvasObject
-	var styleSelect *widget.Select
-	var msgCountSelect *widget.Select
-	var messageForm fyne.CanvasObject
-	var goldEnabledCheck *widget.Check
-	var goldThresholdsEntry *widget.Entry
-	var goldForm fyne.CanvasObject
-	var testGoldButton *widget.Button
-	var generateButton *widget.Button
-	var saveButton *widget.Button
-	var cancelButton *widget.Button
-	var mainContent fyne.CanvasObject
-
-	log.Printf("About to call fyne.DoAndWait()")
 	fyne.DoAndWait(func() {
-		log.Printf("Inside fyne.DoAndWait() - creating window")
-		// App is already available from above
-		log.Printf("Got Fyne app instance")
-
-		// Create window
-		window = app.NewWindow("LoL Kind Bot - Settings")
-		log.Printf("Created window")
+		window := app.NewWindow("LoL Kind Bot - Settings")
 		window.Resize(fyne.NewSize(750, 650))
 		window.CenterOnScreen()
 		window.SetFixedSize(false)
 
-		// Create widgets
-		summonerNameEntry = widget.NewEntry()
-		summonerNameEntry.SetText(editCfg.MySummonerName)
-		summonerNameEntry.SetPlaceHolder("Enter your summoner name")
-
-		autoCopyCheck = widget.NewCheck("Auto-copy first message to clipboard", nil)
-		autoCopyCheck.SetChecked(editCfg.AutoCopyToClipboard)
-
-		// Beautiful glass card with enhanced styling
-		generalCard := widget.NewCard("General Settings", "", container.NewVBox(
-			container.NewPadded(container.NewGridWithColumns(2,
-				widget.NewLabel("Summoner Name:"),
-				summonerNameEntry,
-			)),
-			container.NewPadded(autoCopyCheck),
-		))
-		generalForm = container.NewPadded(generalCard)
-
-		// LLM API Settings Section
-		modelEntry = widget.NewEntry()
-		modelEntry.SetText(editCfg.OllamaModel)
-		modelEntry.SetPlaceHolder("e.g., llama3.1")
-
-		urlEntry = widget.NewEntry()
-		urlEntry.SetText(editCfg.OllamaURL)
-		urlEntry.SetPlaceHolder("e.g., http://localhost:11434/api/generate")
-
-		// Beautiful glass card
-		apiCard := widget.NewCard("LLM API Settings", "", container.NewVBox(
-			container.NewPadded(container.NewGridWithColumns(2,
-				widget.NewLabel("Model:"),
-				modelEntry,
-			)),
-			container.NewPadded(container.NewGridWithColumns(2,
-				widget.NewLabel("API URL:"),
-				urlEntry,
-			)),
-		))
-		apiForm = container.NewPadded(apiCard)
-
-		// Message Generation Settings Section
-		styleSelect = widget.NewSelect(styleOptions, nil)
-		styleSelect.SetSelectedIndex(currentStyleIdx)
-
-		msgCountSelect = widget.NewSelect(msgCountOptions, nil)
-		msgCountSelect.SetSelectedIndex(currentMsgIdx)
-
-		// Beautiful glass card
-		messageCard := widget.NewCard("Message Generation", "", container.NewVBox(
-			container.NewPadded(container.NewGridWithColumns(2,
-				widget.NewLabel("Message Style:"),
-				styleSelect,
-			)),
-			container.NewPadded(container.NewGridWithColumns(2,
-				widget.NewLabel("Number of Messages:"),
-				msgCountSelect,
-			)),
-		))
-		messageForm = container.NewPadded(messageCard)
-
-		// Gold Announcements Settings Section
-		goldEnabledCheck = widget.NewCheck("Enable gold announcements", nil)
-		goldEnabledCheck.SetChecked(editCfg.GoldAnnouncements.Enabled)
-
-		// Format thresholds as comma-separated string
-		thresholdsStr := ""
-		if len(editCfg.GoldAnnouncements.Thresholds) > 0 {
-			thresholdsStr = fmt.Sprintf("%d", editCfg.GoldAnnouncements.Thresholds[0])
-			for i := 1; i < len(editCfg.GoldAnnouncements.Thresholds); i++ {
-				thresholdsStr += fmt.Sprintf(", %d", editCfg.GoldAnnouncements.Thresholds[i])
+		form := newSettingsForm(editCfg, string(previewJSON))
+
+		tabs := container.NewAppTabs(
+			container.NewTabItem("General", form.generalTab()),
+			container.NewTabItem("LLM", form.llmTab()),
+			container.NewTabItem("Messages", form.messagesTab()),
+			container.NewTabItem("AFK Detection", form.afkTab()),
+			container.NewTabItem("Gold", form.goldTab()),
+			container.NewTabItem("Objectives", form.objectivesTab()),
+			container.NewTabItem("Voice", form.voiceTab()),
+			container.NewTabItem("Prompt Preview", form.previewTab()),
+		)
+		tabs.OnSelected = func(tab *container.TabItem) {
+			if tab.Text == "Prompt Preview" {
+				form.refreshPreview()
 			}
 		}
 
-		goldThresholdsEntry = widget.NewEntry()
-		goldThresholdsEntry.SetText(thresholdsStr)
-		goldThresholdsEntry.SetPlaceHolder("e.g., 1500, 2000, 3000")
-
-		// Test gold sound button
-		testGoldButton = widget.NewButton("🔊 Test Gold Sound", func() {
-			// Test with 2000 gold as a sample value
-			AnnounceGold(2000)
-			ShowToast("LoL Kind Bot", "Testing gold announcement: 2000 Gold")
-		})
-		testGoldButton.Importance = widget.MediumImportance
-
-		// Beautiful glass card for gold announcements
-		goldCard := widget.NewCard("Gold Announcements", "", container.NewVBox(
-			container.NewPadded(goldEnabledCheck),
-			container.NewPadded(container.NewGridWithColumns(2,
-				widget.NewLabel("Thresholds:"),
-				goldThresholdsEntry,
-			)),
-			container.NewPadded(widget.NewLabel("Enter comma-separated gold amounts (e.g., 1500, 2000, 3000)")),
-			container.NewPadded(testGoldButton),
-		))
-		goldForm = container.NewPadded(goldCard)
-
-		// Generate Messages button (if callback provided)
-		if generateCallback != nil {
-			generateButton = widget.NewButton("✨ Generate Messages from Last Match", func() {
-				ShowToast("LoL Kind Bot", "Generating messages from last match...")
-				go generateCallback()
-			})
-			generateButton.Importance = widget.MediumImportance
+		// Validation errors are shown above the buttons instead of closing the window
+		errorLabel := widget.NewLabel("")
+		errorLabel.Importance = widget.DangerImportance
+		errorLabel.Wrapping = fyne.TextWrapWord
+		errorLabel.Hide()
+		if opts.LoadErrors != nil {
+			errorLabel.SetText("config.json had invalid settings, shown here at their defaults until you save:\n" + opts.LoadErrors.Error())
+			errorLabel.Show()
 		}
 
-		// Action buttons
-		saveButton = widget.NewButton("Save", func() {
-			// Parse gold thresholds from entry field
-			thresholdsStr := strings.TrimSpace(goldThresholdsEntry.Text)
-			var thresholds []int
-			if thresholdsStr != "" {
-				parts := strings.Split(thresholdsStr, ",")
-				for _, part := range parts {
-					part = strings.TrimSpace(part)
-					if part != "" {
-						if val, err := strconv.Atoi(part); err == nil && val > 0 {
-							thresholds = append(thresholds, val)
-						}
-					}
-				}
-			}
-
-			// Build result config - access widget values
-			resultCfg = &config.Config{
-				MySummonerName:        summonerNameEntry.Text,
-				OllamaModel:           modelEntry.Text,
-				OllamaURL:             urlEntry.Text,
-				AutoCopyToClipboard:   autoCopyCheck.Checked,
-				PollIntervalSeconds:   editCfg.PollIntervalSeconds,
-				EndOfGameCooldownSec:  editCfg.EndOfGameCooldownSec,
-				EnableDetailedLogging: editCfg.EnableDetailedLogging,
-				EnableDebugLogging:    editCfg.EnableDebugLogging,
-				AFKThresholds:         editCfg.AFKThresholds,
-				GoldAnnouncements: config.GoldAnnouncementSettings{
-					Enabled:         goldEnabledCheck.Checked,
-					Thresholds:      thresholds,
-					PollIntervalSec: editCfg.GoldAnnouncements.PollIntervalSec,
-				},
-				LLMSettings: config.LLMSettings{
-					MinMessages:        []int{2, 3, 4, 5}[msgCountSelect.SelectedIndex()],
-					MaxMessages:        []int{2, 3, 4, 5}[msgCountSelect.SelectedIndex()],
-					MaxMessageLength:   150,
-					FocusAreas:         []string{"all"},
-					AFKHandling:        "default",
-					Temperature:        0.7,
-					MaxTokens:          0,
-					CustomInstructions: "",
-				},
-			}
-
-			// Set tone and language style from style selection
-			if styleSelect.SelectedIndex() >= 0 && styleSelect.SelectedIndex() < len(styleMapping) {
-				resultCfg.LLMSettings.Tone = styleMapping[styleSelect.SelectedIndex()].tone
-				resultCfg.LLMSettings.LanguageStyle = styleMapping[styleSelect.SelectedIndex()].languageStyle
-			}
-
-			// Use defaults for hidden settings
-			if resultCfg.LLMSettings.MaxMessageLength == 0 {
-				resultCfg.LLMSettings.MaxMessageLength = 150
-			}
-			if len(resultCfg.LLMSettings.FocusAreas) == 0 {
-				resultCfg.LLMSettings.FocusAreas = []string{"all"}
-			}
-			if resultCfg.LLMSettings.AFKHandling == "" {
-				resultCfg.LLMSettings.AFKHandling = "default"
+		saveButton := widget.NewButton("Save", func() {
+			newCfg := editCfg.Clone()
+			err := form.apply(newCfg)
+			if err == nil {
+				err = newCfg.Validate()
 			}
-			if resultCfg.LLMSettings.Temperature == 0 {
-				resultCfg.LLMSettings.Temperature = 0.7
+			if err != nil {
+				log.Printf("Settings not saved: %v", err)
+				errorLabel.SetText(err.Error())
+				errorLabel.Show()
+				return
 			}
 
+			resultCfg = newCfg
 			accepted = true
 			done <- true
 			// Close window directly - we're already in Fyne context
@@ -304,7 +203,7 @@ func ShowSettingsDialogWithCallbackFyne(cfg *config.Config, generateCallback fun
 		})
 		saveButton.Importance = widget.HighImportance
 
-		cancelButton = widget.NewButton("Cancel", func() {
+		cancelButton := widget.NewButton("Cancel", func() {
 			accepted = false
 			resultCfg = nil
 			done <- true
@@ -312,40 +211,25 @@ func ShowSettingsDialogWithCallbackFyne(cfg *config.Config, generateCallback fun
 			window.Close()
 		})
 
-		// Layout with beautiful spacing and glass effects
-		contentItems := []fyne.CanvasObject{
-			generalForm,
-			apiForm,
-			messageForm,
-			goldForm,
-		}
-		if generateButton != nil {
-			contentItems = append(contentItems, container.NewPadded(generateButton))
-		}
-		content := container.NewVBox(contentItems...)
-
-		buttonBar := container.NewBorder(
-			nil, nil,
-			nil,
-			container.NewHBox(
-				container.NewPadded(cancelButton),
-				container.NewPadded(saveButton),
-			),
+		buttons := container.NewHBox(
+			container.NewPadded(cancelButton),
+			container.NewPadded(saveButton),
 		)
+		var leftButtons fyne.CanvasObject
+		if opts.OnGenerate != nil {
+			generateButton := widget.NewButton("✨ Generate Messages from Last Match", func() {
+				ShowToast("LoL Kind Bot", "Generating messages from last match...")
+				go opts.OnGenerate()
+			})
+			generateButton.Importance = widget.MediumImportance
+			leftButtons = container.NewPadded(generateButton)
+		}
 
-		mainContent = container.NewBorder(
-			nil,       // top
-			buttonBar, // bottom
-			nil,       // left
-			nil,       // right
-			container.NewScroll(content),
-		)
+		buttonBar := container.NewBorder(errorLabel, nil, leftButtons, buttons)
+		mainContent := container.NewBorder(nil, buttonBar, nil, nil, tabs)
 
 		// Apply Windows glass effect (Mica/Acrylic blur)
 		ApplyGlassEffect(window)
-
-		// Set content directly - effects can cause rendering issues
-		// For now, use mainContent directly to ensure it renders
 		window.SetContent(mainContent)
 
 		// Handle window close event (X button or Alt+F4)
@@ -354,34 +238,650 @@ func ShowSettingsDialogWithCallbackFyne(cfg *config.Config, generateCallback fun
 			accepted = false
 			resultCfg = nil
 			done <- true
-			// Don't call window.Close() here - it's already closing
+			window.Close()
 		})
 
-		// Show window and bring to front
-		// app.Run() is running in a goroutine, so Show() will work properly
-		log.Printf("About to call window.Show()")
 		window.Show()
 		window.RequestFocus()
 		window.CenterOnScreen()
-		log.Printf("Window shown")
+		log.Printf("Settings window shown")
 	})
 
-	log.Printf("fyne.DoAndWait() completed, waiting for window to close")
 	// Wait for window to close (signaled by button handlers or close intercept)
 	<-done
-	log.Printf("Window closed, returning result")
+	log.Printf("Settings window closed (saved: %v)", accepted)
 
 	return resultCfg, accepted
 }
 
+// newSettingsForm creates the form widgets filled in from cfg. Must run on the Fyne thread.
+func newSettingsForm(cfg *config.Config, previewJSON string) *settingsForm {
+	f := &settingsForm{previewJSON: previewJSON, base: cfg}
+
+	// General
+	f.summonerName = widget.NewEntry()
+	f.summonerName.SetPlaceHolder("Enter your summoner name")
+	f.autoCopy = widget.NewCheck("Auto-copy first message to clipboard", nil)
+	f.replyAssistant = widget.NewCheck("Suggest replies to post-game chat", nil)
+	f.champOpeners = widget.NewCheck("Suggest openers during champion select", nil)
+	f.pollInterval = newIntEntry("e.g., 3")
+	f.eogCooldown = newIntEntry("e.g., 30")
+	f.detailedLogging = widget.NewCheck("Detailed logging (game summaries and EoG stats)", nil)
+	f.debugLogging = widget.NewCheck("Debug logging (show console, verbose LLM pipeline logs)", nil)
+
+	// LLM
+	f.model = widget.NewSelectEntry(nil)
+	f.model.SetPlaceHolder("e.g., llama3.1")
+	f.url = widget.NewEntry()
+	f.url.SetPlaceHolder("e.g., http://localhost:11434/api/generate")
+	f.temperatureLabel = widget.NewLabel("")
+	f.temperature = widget.NewSlider(0, 1)
+	f.temperature.Step = 0.05
+	f.temperature.OnChanged = func(value float64) {
+		f.temperatureLabel.SetText(fmt.Sprintf("%.2f", value))
+	}
+	f.maxTokens = newIntEntry("0 = server default")
+	f.connectionStatus = widget.NewLabel("")
+	f.connectionStatus.Wrapping = fyne.TextWrapWord
+
+	// Messages
+	f.tone = widget.NewSelect(config.AllowedTones, nil)
+	f.languageStyle = widget.NewSelect(config.AllowedLanguageStyles, nil)
+	f.language = widget.NewSelect(config.AllowedLanguages, nil)
+	f.minMessages = newIntEntry("1-10")
+	f.maxMessages = newIntEntry("1-10")
+	f.maxMessageLength = newIntEntry("e.g., 150")
+	f.focusAreas = widget.NewCheckGroup(config.AllowedFocusAreas, nil)
+	f.focusAreas.Horizontal = true
+	f.afkHandling = widget.NewSelect(config.AllowedAFKHandling, nil)
+	f.customInstructions = widget.NewMultiLineEntry()
+	f.customInstructions.SetPlaceHolder("Extra instructions added to the prompt (optional)")
+	f.customInstructions.Wrapping = fyne.TextWrapWord
+	f.customInstructions.SetMinRowsVisible(4)
+	f.feedbackEnabled = widget.NewCheck("Learn from the messages I copy, send, edit and rate", nil)
+	f.adaptTone = widget.NewCheck("Adapt the tone to my feedback", nil)
+	f.adaptLength = widget.NewCheck("Adapt the message length to my feedback", nil)
+	f.feedbackMinSamples = newIntEntry("e.g., 20")
+
+	// AFK detection
+	f.afkMinGameMinutes = newFloatEntry("e.g., 10")
+	f.afkMaxCsPerMin = newFloatEntry("e.g., 0.5")
+	f.afkMaxDamage = newIntEntry("e.g., 1500")
+	f.afkMaxGold = newIntEntry("e.g., 4000")
+
+	// Gold announcements
+	f.goldEnabled = widget.NewCheck("Enable gold announcements", nil)
+	f.goldThresholds = widget.NewEntry()
+	f.goldThresholds.SetPlaceHolder("e.g., 1500, 2000, 3000")
+	f.goldPollInterval = newIntEntry("e.g., 2")
+	f.goldMode = widget.NewSelect(config.AllowedGoldModes, nil)
+	f.goldAlmost = newIntEntry("0 = off, e.g., 300")
+	f.goldBuilds = widget.NewMultiLineEntry()
+	f.goldBuilds.SetPlaceHolder("One champion per line, e.g.\nJinx: Kraken Slayer, Infinity Edge, Phantom Dancer")
+	f.goldBuilds.Wrapping = fyne.TextWrapWord
+	f.goldBuilds.SetMinRowsVisible(4)
+
+	// Objective timers
+	f.objectivesEnabled = widget.NewCheck("Announce objective timers", nil)
+	f.objectivesPollInterval = newIntEntry("e.g., 2")
+	f.objectiveChecks = make(map[string]*widget.Check)
+	f.objectiveLeads = make(map[string]*widget.Entry)
+	for _, kind := range config.ObjectiveKinds {
+		f.objectiveChecks[kind] = widget.NewCheck(objectiveLabels[kind], nil)
+		f.objectiveLeads[kind] = newIntEntry("seconds before, e.g., 30")
+	}
+
+	// Voice
+	f.ttsEngine = widget.NewSelect(config.AllowedTTSEngines, nil)
+	f.ttsVoice = widget.NewSelectEntry(nil)
+	f.ttsVoice.SetPlaceHolder("Engine default")
+	f.ttsRateLabel = widget.NewLabel("")
+	f.ttsRate = widget.NewSlider(-10, 10)
+	f.ttsRate.OnChanged = func(value float64) {
+		f.ttsRateLabel.SetText(fmt.Sprintf("%+.0f", value))
+	}
+	f.ttsVolumeLabel = widget.NewLabel("")
+	f.ttsVolume = widget.NewSlider(0, 100)
+	f.ttsVolume.Step = 5
+	f.ttsVolume.OnChanged = func(value float64) {
+		f.ttsVolumeLabel.SetText(fmt.Sprintf("%.0f%%", value))
+	}
+	f.ttsPiperModel = widget.NewEntry()
+	f.ttsPiperModel.SetPlaceHolder("e.g., /path/to/en_US-lessac-medium.onnx")
+	f.ttsMuteHotkey = widget.NewEntry()
+	f.ttsMuteHotkey.SetPlaceHolder("e.g., ctrl+shift+m (empty = none)")
+	f.ttsMuteHotkey.Validator = func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		_, err := config.ParseHotkey(text)
+		return err
+	}
+	f.ttsStatus = widget.NewLabel("")
+	f.ttsStatus.Wrapping = fyne.TextWrapWord
+
+	// Prompt preview
+	f.preview = widget.NewLabel("")
+	f.preview.Wrapping = fyne.TextWrapWord
+	f.preview.TextStyle = fyne.TextStyle{Monospace: true}
+
+	f.loadGeneral(cfg)
+	f.loadLLM(cfg)
+	f.loadMessages(cfg)
+	f.loadAFK(cfg)
+	f.loadGold(cfg)
+	f.loadObjectives(cfg)
+	f.loadVoice(cfg)
+	return f
+}
+
+func (f *settingsForm) loadGeneral(cfg *config.Config) {
+	// Keep the summoner name on reset - it is not a preference
+	if f.summonerName.Text == "" {
+		f.summonerName.SetText(cfg.MySummonerName)
+	}
+	f.autoCopy.SetChecked(cfg.AutoCopyToClipboard)
+	f.replyAssistant.SetChecked(cfg.ReplyAssistant)
+	f.champOpeners.SetChecked(cfg.ChampSelectOpeners)
+	f.pollInterval.SetText(strconv.Itoa(cfg.PollIntervalSeconds))
+	f.eogCooldown.SetText(strconv.Itoa(cfg.EndOfGameCooldownSec))
+	f.detailedLogging.SetChecked(cfg.EnableDetailedLogging)
+	f.debugLogging.SetChecked(cfg.EnableDebugLogging)
+}
+
+func (f *settingsForm) loadLLM(cfg *config.Config) {
+	f.model.SetText(cfg.OllamaModel)
+	f.url.SetText(cfg.OllamaURL)
+	f.temperature.SetValue(cfg.LLMSettings.Temperature)
+	f.temperatureLabel.SetText(fmt.Sprintf("%.2f", cfg.LLMSettings.Temperature))
+	f.maxTokens.SetText(strconv.Itoa(cfg.LLMSettings.MaxTokens))
+	f.connectionStatus.SetText("")
+}
+
+func (f *settingsForm) loadMessages(cfg *config.Config) {
+	f.tone.SetSelected(cfg.LLMSettings.Tone)
+	f.languageStyle.SetSelected(cfg.LLMSettings.LanguageStyle)
+	f.language.SetSelected(cfg.LLMSettings.Language)
+	f.minMessages.SetText(strconv.Itoa(cfg.LLMSettings.MinMessages))
+	f.maxMessages.SetText(strconv.Itoa(cfg.LLMSettings.MaxMessages))
+	f.maxMessageLength.SetText(strconv.Itoa(cfg.LLMSettings.MaxMessageLength))
+	f.focusAreas.SetSelected(cfg.LLMSettings.FocusAreas)
+	f.afkHandling.SetSelected(cfg.LLMSettings.AFKHandling)
+	f.customInstructions.SetText(cfg.LLMSettings.CustomInstructions)
+	f.feedbackEnabled.SetChecked(cfg.Feedback.Enabled)
+	f.adaptTone.SetChecked(cfg.Feedback.AdaptTone)
+	f.adaptLength.SetChecked(cfg.Feedback.AdaptLength)
+	f.feedbackMinSamples.SetText(strconv.Itoa(cfg.Feedback.MinSamples))
+}
+
+func (f *settingsForm) loadAFK(cfg *config.Config) {
+	f.afkMinGameMinutes.SetText(formatFloat(cfg.AFKThresholds.MinGameMinutes))
+	f.afkMaxCsPerMin.SetText(formatFloat(cfg.AFKThresholds.MaxCsPerMin))
+	f.afkMaxDamage.SetText(strconv.Itoa(cfg.AFKThresholds.MaxDamageToChamp))
+	f.afkMaxGold.SetText(strconv.Itoa(cfg.AFKThresholds.MaxGoldEarned))
+}
+
+func (f *settingsForm) loadGold(cfg *config.Config) {
+	f.goldEnabled.SetChecked(cfg.GoldAnnouncements.Enabled)
+	thresholds := make([]string, len(cfg.GoldAnnouncements.Thresholds))
+	for i, threshold := range cfg.GoldAnnouncements.Thresholds {
+		thresholds[i] = strconv.Itoa(threshold)
+	}
+	f.goldThresholds.SetText(strings.Join(thresholds, ", "))
+	f.goldPollInterval.SetText(strconv.Itoa(cfg.GoldAnnouncements.PollIntervalSec))
+	f.goldMode.SetSelected(cfg.GoldAnnouncements.Mode)
+	f.goldAlmost.SetText(strconv.Itoa(cfg.GoldAnnouncements.AlmostGold))
+	champions := make([]string, 0, len(cfg.GoldAnnouncements.Builds))
+	for champion := range cfg.GoldAnnouncements.Builds {
+		champions = append(champions, champion)
+	}
+	sort.Strings(champions)
+	lines := make([]string, len(champions))
+	for i, champion := range champions {
+		lines[i] = champion + ": " + strings.Join(cfg.GoldAnnouncements.Builds[champion], ", ")
+	}
+	f.goldBuilds.SetText(strings.Join(lines, "\n"))
+}
+
+func (f *settingsForm) loadObjectives(cfg *config.Config) {
+	f.objectivesEnabled.SetChecked(cfg.ObjectiveTimers.Enabled)
+	f.objectivesPollInterval.SetText(strconv.Itoa(cfg.ObjectiveTimers.PollIntervalSec))
+	for _, kind := range config.ObjectiveKinds {
+		timer := cfg.ObjectiveTimers.Timer(kind)
+		f.objectiveChecks[kind].SetChecked(timer.Enabled)
+		f.objectiveLeads[kind].SetText(strconv.Itoa(timer.LeadSeconds))
+	}
+}
+
+func (f *settingsForm) loadVoice(cfg *config.Config) {
+	f.ttsEngine.SetSelected(cfg.TTS.Engine)
+	f.ttsVoice.SetText(cfg.TTS.Voice)
+	f.ttsRate.SetValue(float64(cfg.TTS.Rate))
+	f.ttsVolume.SetValue(float64(cfg.TTS.Volume))
+	f.ttsPiperModel.SetText(cfg.TTS.PiperModel)
+	f.ttsMuteHotkey.SetText(cfg.TTS.MuteHotkey)
+}
+
+// apply writes the form's values into cfg. Fields that fail to parse keep their old value
+// and are reported in the returned *config.ValidationError.
+func (f *settingsForm) apply(cfg *config.Config) error {
+	p := &formParser{}
+
+	cfg.MySummonerName = strings.TrimSpace(f.summonerName.Text)
+	cfg.AutoCopyToClipboard = f.autoCopy.Checked
+	cfg.ReplyAssistant = f.replyAssistant.Checked
+	cfg.ChampSelectOpeners = f.champOpeners.Checked
+	p.parseInt("pollIntervalSeconds", f.pollInterval, &cfg.PollIntervalSeconds)
+	p.parseInt("endOfGameCooldownSeconds", f.eogCooldown, &cfg.EndOfGameCooldownSec)
+	cfg.EnableDetailedLogging = f.detailedLogging.Checked
+	cfg.EnableDebugLogging = f.debugLogging.Checked
+
+	cfg.OllamaModel = strings.TrimSpace(f.model.Text)
+	cfg.OllamaURL = strings.TrimSpace(f.url.Text)
+	cfg.LLMSettings.Temperature = math.Round(f.temperature.Value*100) / 100
+	p.parseInt("llmSettings.maxTokens", f.maxTokens, &cfg.LLMSettings.MaxTokens)
+
+	cfg.LLMSettings.Tone = f.tone.Selected
+	cfg.LLMSettings.LanguageStyle = f.languageStyle.Selected
+	cfg.LLMSettings.Language = f.language.Selected
+	p.parseInt("llmSettings.minMessages", f.minMessages, &cfg.LLMSettings.MinMessages)
+	p.parseInt("llmSettings.maxMessages", f.maxMessages, &cfg.LLMSettings.MaxMessages)
+	p.parseInt("llmSettings.maxMessageLength", f.maxMessageLength, &cfg.LLMSettings.MaxMessageLength)
+	cfg.LLMSettings.FocusAreas = append([]string(nil), f.focusAreas.Selected...)
+	cfg.LLMSettings.AFKHandling = f.afkHandling.Selected
+	cfg.LLMSettings.CustomInstructions = strings.TrimSpace(f.customInstructions.Text)
+	cfg.Feedback.Enabled = f.feedbackEnabled.Checked
+	cfg.Feedback.AdaptTone = f.adaptTone.Checked
+	cfg.Feedback.AdaptLength = f.adaptLength.Checked
+	p.parseInt("feedback.minSamples", f.feedbackMinSamples, &cfg.Feedback.MinSamples)
+
+	p.parseFloat("afkThresholds.minGameMinutes", f.afkMinGameMinutes, &cfg.AFKThresholds.MinGameMinutes)
+	p.parseFloat("afkThresholds.maxCsPerMin", f.afkMaxCsPerMin, &cfg.AFKThresholds.MaxCsPerMin)
+	p.parseInt("afkThresholds.maxDamageToChamp", f.afkMaxDamage, &cfg.AFKThresholds.MaxDamageToChamp)
+	p.parseInt("afkThresholds.maxGoldEarned", f.afkMaxGold, &cfg.AFKThresholds.MaxGoldEarned)
+
+	cfg.GoldAnnouncements.Enabled = f.goldEnabled.Checked
+	p.parseIntList("goldAnnouncements.thresholds", f.goldThresholds, &cfg.GoldAnnouncements.Thresholds)
+	p.parseInt("goldAnnouncements.pollIntervalSec", f.goldPollInterval, &cfg.GoldAnnouncements.PollIntervalSec)
+	cfg.GoldAnnouncements.Mode = f.goldMode.Selected
+	p.parseInt("goldAnnouncements.almostGold", f.goldAlmost, &cfg.GoldAnnouncements.AlmostGold)
+	p.parseBuilds("goldAnnouncements.builds", f.goldBuilds, &cfg.GoldAnnouncements.Builds)
+
+	cfg.ObjectiveTimers.Enabled = f.objectivesEnabled.Checked
+	p.parseInt("objectiveTimers.pollIntervalSec", f.objectivesPollInterval, &cfg.ObjectiveTimers.PollIntervalSec)
+	for _, kind := range config.ObjectiveKinds {
+		timer := cfg.ObjectiveTimers.Timer(kind)
+		timer.Enabled = f.objectiveChecks[kind].Checked
+		p.parseInt("objectiveTimers."+kind+".leadSeconds", f.objectiveLeads[kind], &timer.LeadSeconds)
+	}
+
+	cfg.TTS.Engine = f.ttsEngine.Selected
+	cfg.TTS.Voice = strings.TrimSpace(f.ttsVoice.Text)
+	cfg.TTS.Rate = int(f.ttsRate.Value)
+	cfg.TTS.Volume = int(f.ttsVolume.Value)
+	cfg.TTS.PiperModel = strings.TrimSpace(f.ttsPiperModel.Text)
+	cfg.TTS.MuteHotkey = strings.TrimSpace(f.ttsMuteHotkey.Text)
+
+	if len(p.errors) > 0 {
+		return &config.ValidationError{Errors: p.errors}
+	}
+	return nil
+}
+
+// refreshPreview rebuilds the prompt preview from the current form values
+func (f *settingsForm) refreshPreview() {
+	cfg := f.base.Clone()
+	_ = f.apply(cfg) // Unparseable fields keep their saved values in the preview
+	f.preview.SetText(llm.BuildPrompt(f.previewJSON, &cfg.LLMSettings))
+}
+
+// testConnection lists the models on the LLM server and offers them in the model dropdown
+func (f *settingsForm) testConnection() {
+	url := strings.TrimSpace(f.url.Text)
+	model := strings.TrimSpace(f.model.Text)
+	f.connectionStatus.Importance = widget.MediumImportance
+	f.connectionStatus.SetText("Connecting to " + url + "...")
+
+	go func() {
+		models, err := llm.NewClient(url, model, nil).ListModels()
+		fyne.Do(func() {
+			switch {
+			case err != nil:
+				log.Printf("LLM connection test failed: %v", err)
+				f.connectionStatus.Importance = widget.DangerImportance
+				f.connectionStatus.SetText(fmt.Sprintf("Connection failed: %v", err))
+			case len(models) == 0:
+				f.connectionStatus.Importance = widget.WarningImportance
+				f.connectionStatus.SetText("Connected, but no models are installed (run: ollama pull llama3.1)")
+			case model != "" && !llm.HasModel(models, model):
+				f.model.SetOptions(models)
+				f.connectionStatus.Importance = widget.WarningImportance
+				f.connectionStatus.SetText(fmt.Sprintf("Connected, but %q is not installed. Available: %s", model, strings.Join(models, ", ")))
+			default:
+				f.model.SetOptions(models)
+				f.connectionStatus.Importance = widget.SuccessImportance
+				f.connectionStatus.SetText(fmt.Sprintf("Connected - %d model(s) available: %s", len(models), strings.Join(models, ", ")))
+			}
+		})
+	}()
+}
+
+func (f *settingsForm) generalTab() fyne.CanvasObject {
+	card := widget.NewCard("General Settings", "", container.NewVBox(
+		formRow("Summoner Name:", f.summonerName),
+		container.NewPadded(f.autoCopy),
+		container.NewPadded(f.replyAssistant),
+		container.NewPadded(f.champOpeners),
+		formRow("Game State Poll Interval (s):", f.pollInterval),
+		formRow("End-of-Game Cooldown (s):", f.eogCooldown),
+		container.NewPadded(f.detailedLogging),
+		container.NewPadded(f.debugLogging),
+	))
+	return settingsTab(card, func() { f.loadGeneral(config.DefaultConfig()) })
+}
+
+func (f *settingsForm) llmTab() fyne.CanvasObject {
+	testButton := widget.NewButton("Test LLM Connection", f.testConnection)
+	testButton.Importance = widget.MediumImportance
+
+	card := widget.NewCard("LLM API Settings", "", container.NewVBox(
+		formRow("Model:", f.model),
+		formRow("API URL:", f.url),
+		container.NewPadded(container.NewHBox(testButton)),
+		container.NewPadded(f.connectionStatus),
+		formRow("Temperature:", container.NewBorder(nil, nil, nil, f.temperatureLabel, f.temperature)),
+		formRow("Max Tokens:", f.maxTokens),
+	))
+	return settingsTab(card, func() { f.loadLLM(config.DefaultConfig()) })
+}
+
+func (f *settingsForm) messagesTab() fyne.CanvasObject {
+	card := widget.NewCard("Message Generation", "", container.NewVBox(
+		formRow("Tone:", f.tone),
+		formRow("Language Style:", f.languageStyle),
+		formRow("Language:", f.language),
+		formRow("Minimum Messages:", f.minMessages),
+		formRow("Maximum Messages:", f.maxMessages),
+		formRow("Max Message Length:", f.maxMessageLength),
+		formRow("AFK Handling:", f.afkHandling),
+		container.NewPadded(widget.NewLabel("Focus Areas:")),
+		container.NewPadded(f.focusAreas),
+		container.NewPadded(widget.NewLabel("Custom Instructions:")),
+		container.NewPadded(f.customInstructions),
+		widget.NewSeparator(),
+		container.NewPadded(f.feedbackEnabled),
+		container.NewPadded(f.adaptTone),
+		container.NewPadded(f.adaptLength),
+		formRow("Ratings Before Adapting:", f.feedbackMinSamples),
+	))
+	return settingsTab(card, func() { f.loadMessages(config.DefaultConfig()) })
+}
+
+func (f *settingsForm) afkTab() fyne.CanvasObject {
+	card := widget.NewCard("AFK Detection", "A player is flagged AFK when all limits are met", container.NewVBox(
+		formRow("Minimum Game Length (min):", f.afkMinGameMinutes),
+		formRow("Max CS per Minute:", f.afkMaxCsPerMin),
+		formRow("Max Damage to Champions:", f.afkMaxDamage),
+		formRow("Max Gold Earned:", f.afkMaxGold),
+	))
+	return settingsTab(card, func() { f.loadAFK(config.DefaultConfig()) })
+}
+
+func (f *settingsForm) goldTab() fyne.CanvasObject {
+	// Test gold sound button
+	testGoldButton := widget.NewButton("🔊 Test Gold Sound", func() {
+		// Test with 2000 gold as a sample value
+		f.testSpeech("2000 Gold")
+		ShowToast("LoL Kind Bot", "Testing gold announcement: 2000 Gold")
+	})
+	testGoldButton.Importance = widget.MediumImportance
+
+	card := widget.NewCard("Gold Announcements", "", container.NewVBox(
+		container.NewPadded(f.goldEnabled),
+		formRow("Announce:", f.goldMode),
+		container.NewPadded(widget.NewLabel("\"build\" announces the next item in your build (from below, or your item sets in the client) and falls back to the thresholds")),
+		formRow("Builds:", f.goldBuilds),
+		formRow("Almost There (gold):", f.goldAlmost),
+		formRow("Thresholds:", f.goldThresholds),
+		container.NewPadded(widget.NewLabel("Enter comma-separated gold amounts (e.g., 1500, 2000, 3000)")),
+		formRow("Poll Interval (s):", f.goldPollInterval),
+		container.NewPadded(container.NewHBox(testGoldButton)),
+	))
+	return settingsTab(card, func() { f.loadGold(config.DefaultConfig()) })
+}
+
+// objectiveLabels names each objective timer in the settings window
+var objectiveLabels = map[string]string{
+	"dragon":    "Dragon spawn",
+	"herald":    "Rift Herald spawn",
+	"baron":     "Baron spawn",
+	"inhibitor": "Inhibitor respawn",
+	"baronBuff": "Baron buff ends",
+	"elderBuff": "Elder buff ends",
+}
+
+func (f *settingsForm) objectivesTab() fyne.CanvasObject {
+	testButton := widget.NewButton("🔊 Test Announcement", func() {
+		f.testSpeech("Dragon spawns in 30 seconds")
+	})
+	testButton.Importance = widget.MediumImportance
+
+	rows := container.NewVBox(
+		container.NewPadded(f.objectivesEnabled),
+		formRow("Poll Interval (s):", f.objectivesPollInterval),
+		container.NewPadded(widget.NewLabel("Announce each objective this many seconds before it happens:")),
+	)
+	for _, kind := range config.ObjectiveKinds {
+		rows.Add(container.NewPadded(container.NewGridWithColumns(2, f.objectiveChecks[kind], f.objectiveLeads[kind])))
+	}
+	rows.Add(container.NewPadded(container.NewHBox(testButton)))
+
+	card := widget.NewCard("Objective Timers", "", rows)
+	return settingsTab(card, func() { f.loadObjectives(config.DefaultConfig()) })
+}
+
+func (f *settingsForm) voiceTab() fyne.CanvasObject {
+	listButton := widget.NewButton("List Voices", f.listVoices)
+	testButton := widget.NewButton("🔊 Test Voice", func() {
+		f.testSpeech("Baron spawns in 30 seconds")
+	})
+	testButton.Importance = widget.MediumImportance
+
+	card := widget.NewCard("Voice", "How in-game announcements are spoken", container.NewVBox(
+		formRow("Engine:", f.ttsEngine),
+		formRow("Voice:", f.ttsVoice),
+		formRow("Rate:", container.NewBorder(nil, nil, nil, f.ttsRateLabel, f.ttsRate)),
+		formRow("Volume:", container.NewBorder(nil, nil, nil, f.ttsVolumeLabel, f.ttsVolume)),
+		formRow("Piper Model:", f.ttsPiperModel),
+		formRow("Mute Hotkey:", f.ttsMuteHotkey),
+		container.NewPadded(container.NewHBox(listButton, testButton)),
+		container.NewPadded(f.ttsStatus),
+	))
+	return settingsTab(card, func() { f.loadVoice(config.DefaultConfig()) })
+}
+
+// speechSettings returns the voice settings as currently entered
+func (f *settingsForm) speechSettings() config.TTSSettings {
+	cfg := f.base.Clone()
+	_ = f.apply(cfg) // Unparseable fields elsewhere don't matter here
+	return cfg.TTS
+}
+
+// testSpeech speaks text with the voice settings as currently entered, even if unsaved or muted
+func (f *settingsForm) testSpeech(text string) {
+	settings := f.speechSettings()
+	go func() {
+		engine, err := tts.New(&settings)
+		if err == nil {
+			err = engine.Speak(context.Background(), text)
+		}
+		if err != nil {
+			log.Printf("Voice test failed: %v", err)
+			fyne.Do(func() {
+				f.ttsStatus.Importance = widget.DangerImportance
+				f.ttsStatus.SetText(fmt.Sprintf("Voice test failed: %v", err))
+			})
+		}
+	}()
+}
+
+// listVoices offers the selected engine's voices in the voice dropdown
+func (f *settingsForm) listVoices() {
+	settings := f.speechSettings()
+	f.ttsStatus.Importance = widget.MediumImportance
+	f.ttsStatus.SetText("Looking for voices...")
+
+	go func() {
+		var voices []string
+		engine, err := tts.New(&settings)
+		if err == nil {
+			voices, err = engine.Voices()
+		}
+		fyne.Do(func() {
+			switch {
+			case err != nil:
+				f.ttsStatus.Importance = widget.DangerImportance
+				f.ttsStatus.SetText(fmt.Sprintf("Failed to list voices: %v", err))
+			case len(voices) == 0:
+				f.ttsStatus.Importance = widget.WarningImportance
+				f.ttsStatus.SetText(fmt.Sprintf("The %s engine has no voices to choose from; its default voice is used", engine.Name()))
+			default:
+				f.ttsVoice.SetOptions(voices)
+				f.ttsStatus.Importance = widget.SuccessImportance
+				f.ttsStatus.SetText(fmt.Sprintf("%d voice(s) available for %s", len(voices), engine.Name()))
+			}
+		})
+	}()
+}
+
+func (f *settingsForm) previewTab() fyne.CanvasObject {
+	refreshButton := widget.NewButton("Refresh", f.refreshPreview)
+	header := container.NewBorder(nil, nil,
+		widget.NewLabel("Prompt built from the current settings for a sample game:"), refreshButton)
+	return container.NewBorder(container.NewPadded(header), nil, nil, nil,
+		container.NewVScroll(container.NewPadded(f.preview)))
+}
+
+// settingsTab wraps a settings card with a reset-to-defaults button for its section
+func settingsTab(card *widget.Card, reset func()) fyne.CanvasObject {
+	resetButton := widget.NewButton("Reset to Defaults", reset)
+	resetButton.Importance = widget.LowImportance
+	return container.NewVScroll(container.NewVBox(
+		container.NewPadded(card),
+		container.NewPadded(container.NewHBox(resetButton)),
+	))
+}
+
+// formRow lays out a label and its input side by side
+func formRow(label string, input fyne.CanvasObject) fyne.CanvasObject {
+	return container.NewPadded(container.NewGridWithColumns(2, widget.NewLabel(label), input))
+}
+
+// newIntEntry creates an entry that flags anything other than a whole number
+func newIntEntry(placeholder string) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(placeholder)
+	entry.Validator = func(text string) error {
+		if _, err := strconv.Atoi(strings.TrimSpace(text)); err != nil {
+			return fmt.Errorf("must be a whole number")
+		}
+		return nil
+	}
+	return entry
+}
+
+// newFloatEntry creates an entry that flags anything other than a number
+func newFloatEntry(placeholder string) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(placeholder)
+	entry.Validator = func(text string) error {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		return nil
+	}
+	return entry
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// formParser collects parse errors for text fields, using the config's JSON paths
+type formParser struct {
+	errors []config.FieldError
+}
+
+func (p *formParser) parseInt(path string, entry *widget.Entry, dst *int) {
+	value, err := strconv.Atoi(strings.TrimSpace(entry.Text))
+	if err != nil {
+		p.errors = append(p.errors, config.FieldError{Path: path, Message: fmt.Sprintf("must be a whole number (got %q)", entry.Text)})
+		return
+	}
+	*dst = value
+}
+
+func (p *formParser) parseFloat(path string, entry *widget.Entry, dst *float64) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(entry.Text), 64)
+	if err != nil {
+		p.errors = append(p.errors, config.FieldError{Path: path, Message: fmt.Sprintf("must be a number (got %q)", entry.Text)})
+		return
+	}
+	*dst = value
+}
+
+func (p *formParser) parseIntList(path string, entry *widget.Entry, dst *[]int) {
+	var values []int
+	for _, part := range strings.Split(entry.Text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			p.errors = append(p.errors, config.FieldError{Path: path, Message: fmt.Sprintf("must be comma-separated whole numbers (got %q)", part)})
+			return
+		}
+		values = append(values, value)
+	}
+	*dst = values
+}
+
+// parseBuilds parses "Champion: item, item" lines into champion builds
+func (p *formParser) parseBuilds(path string, entry *widget.Entry, dst *map[string][]string) {
+	builds := make(map[string][]string)
+	for _, line := range strings.Split(entry.Text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		champion, list, ok := strings.Cut(line, ":")
+		champion = strings.TrimSpace(champion)
+		if !ok || champion == "" {
+			p.errors = append(p.errors, config.FieldError{Path: path, Message: fmt.Sprintf("each line must be \"Champion: item, item\" (got %q)", line)})
+			return
+		}
+		var items []string
+		for _, item := range strings.Split(list, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		builds[champion] = items
+	}
+	if len(builds) == 0 {
+		builds = nil
+	}
+	*dst = builds
+}
+
 // ShowSettingsDialogFyne shows settings dialog without callback
 func ShowSettingsDialogFyne(cfg *config.Config) (*config.Config, bool) {
-	return ShowSettingsDialogWithCallbackFyne(cfg, nil)
+	return ShowSettingsWindow(cfg, SettingsOptions{})
 }
 
 // Wrapper functions to maintain compatibility - these now use Fyne
 func ShowSettingsDialogWithCallback(cfg *config.Config, generateCallback func()) (*config.Config, bool) {
-	return ShowSettingsDialogWithCallbackFyne(cfg, generateCallback)
+	return ShowSettingsWindow(cfg, SettingsOptions{OnGenerate: generateCallback})
 }
 
 func ShowSettingsDialog(cfg *config.Config) (*config.Config, bool) {
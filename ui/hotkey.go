@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"lol-kind-bot/config"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	hotkeyUser32           = syscall.NewLazyDLL("user32.dll")
+	hotkeyKernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procRegisterHotKey     = hotkeyUser32.NewProc("RegisterHotKey")
+	procUnregisterHotKey   = hotkeyUser32.NewProc("UnregisterHotKey")
+	procPostThreadMessageW = hotkeyUser32.NewProc("PostThreadMessageW")
+	procGetCurrentThreadId = hotkeyKernel32.NewProc("GetCurrentThreadId")
+)
+
+const (
+	MOD_ALT      = 0x0001
+	MOD_CONTROL  = 0x0002
+	MOD_SHIFT    = 0x0004
+	MOD_WIN      = 0x0008
+	MOD_NOREPEAT = 0x4000
+
+	WM_HOTKEY = 0x0312
+	WM_QUIT   = 0x0012
+
+	VK_F1 = 0x70
+)
+
+// lastHotkeyID numbers registered hotkeys
+var lastHotkeyID int32
+
+// RegisterHotkey calls fn whenever the global hotkey is pressed, in any application, and
+// returns a function that unregisters it
+func RegisterHotkey(hotkey config.Hotkey, fn func()) (func(), error) {
+	modifiers := uintptr(MOD_NOREPEAT)
+	if hotkey.Ctrl {
+		modifiers |= MOD_CONTROL
+	}
+	if hotkey.Alt {
+		modifiers |= MOD_ALT
+	}
+	if hotkey.Shift {
+		modifiers |= MOD_SHIFT
+	}
+	if hotkey.Win {
+		modifiers |= MOD_WIN
+	}
+	var key uintptr
+	if len(hotkey.Key) == 1 {
+		key = uintptr(hotkey.Key[0]) // Virtual-key codes of letters and digits are their ASCII codes
+	} else {
+		var n int
+		fmt.Sscanf(hotkey.Key, "F%d", &n)
+		key = uintptr(VK_F1 + n - 1)
+	}
+
+	hotkeyID := uintptr(atomic.AddInt32(&lastHotkeyID, 1))
+
+	// The hotkey belongs to the thread that registers it, and WM_HOTKEY arrives in that thread's message queue
+	registered := make(chan error, 1)
+	threadID := make(chan uintptr, 1)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		id, _, _ := procGetCurrentThreadId.Call()
+		if ret, _, err := procRegisterHotKey.Call(0, hotkeyID, modifiers, key); ret == 0 {
+			registered <- fmt.Errorf("failed to register hotkey (already used by another application?): %w", err)
+			return
+		}
+		defer procUnregisterHotKey.Call(0, hotkeyID)
+		threadID <- id
+		registered <- nil
+
+		var msg MSG
+		for {
+			ret, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&msg)), 0, 0, 0)
+			if ret == 0 || int32(ret) == -1 {
+				return // WM_QUIT from the unregister function, or an error
+			}
+			if msg.Message == WM_HOTKEY && msg.WParam == hotkeyID {
+				go fn()
+			}
+		}
+	}()
+
+	if err := <-registered; err != nil {
+		return nil, err
+	}
+	id := <-threadID
+	return func() {
+		procPostThreadMessageW.Call(id, WM_QUIT, 0, 0)
+	}, nil
+}
@@ -0,0 +1,33 @@
+package ui
+
+import "lol-kind-bot/analyzer"
+
+// samplePreviewSummary returns a small made-up game for previewing prompts before any game was played
+func samplePreviewSummary(mySummonerName string) *analyzer.GameSummary {
+	if mySummonerName == "" {
+		mySummonerName = "You"
+	}
+
+	return &analyzer.GameSummary{
+		GameDurationMinutes: 28.5,
+		WinningTeam:         "100",
+		MySummonerName:      mySummonerName,
+		MyTeam:              "100",
+		GameMode:            "CLASSIC",
+		QueueType:           "RANKED_SOLO_5x5",
+		TotalKills:          41,
+		KillDifference:      7,
+		WasClose:            true,
+		Players: []analyzer.PlayerSummary{
+			{SummonerName: mySummonerName, Team: "100", Champion: "Lux", K: 6, D: 3, A: 14, CsPerMin: 6.8, DamageShare: 0.27, VisionScore: 31, Tags: []string{"hard_carry"}, HighestDamageOnTeam: true, TotalDamage: 24100},
+			{SummonerName: "Teammate", Team: "100", Champion: "Leona", K: 2, D: 5, A: 17, CsPerMin: 1.2, DamageShare: 0.09, VisionScore: 58, Tags: []string{"vision_mvp", "utility_mvp"}, HighestVisionInGame: true, MostCCInGame: true, TotalDamage: 8200},
+			{SummonerName: "Opponent", Team: "200", Champion: "Zed", K: 9, D: 6, A: 3, CsPerMin: 7.9, DamageShare: 0.31, VisionScore: 12, HighestDamageInGame: true, TotalDamage: 26300},
+		},
+		Achievements: analyzer.GameAchievements{
+			HighestDamageInGame:   "Zed",
+			HighestDamageOnMyTeam: "Lux",
+			HighestVisionInGame:   "Leona",
+			MostCCInGame:          "Leona",
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"lol-kind-bot/analyzer"
+	"strings"
+	"time"
+)
+
+// Discord embed colors
+const (
+	discordColorWin     = 0x2ECC71
+	discordColorLoss    = 0xE74C3C
+	discordColorNeutral = 0x5865F2
+)
+
+type discordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Content  string         `json:"content,omitempty"`
+	Embeds   []discordEmbed `json:"embeds,omitempty"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+// formatDiscord builds a Discord webhook body with an embed of the game highlights and chosen message
+func formatDiscord(payload Payload) ([]byte, error) {
+	msg := discordMessage{Username: "LoL Kind Bot"}
+
+	switch payload.Event {
+	case EventGoldMilestone:
+		msg.Content = fmt.Sprintf("💰 %d gold", payload.Gold)
+		if payload.Message != "" {
+			msg.Content = "💰 " + payload.Message
+		}
+	default:
+		msg.Embeds = []discordEmbed{gameEmbed(payload)}
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Discord message: %w", err)
+	}
+	return data, nil
+}
+
+// gameEmbed summarizes a finished game for Discord
+func gameEmbed(payload Payload) discordEmbed {
+	embed := discordEmbed{
+		Title:     "Game finished",
+		Color:     discordColorNeutral,
+		Timestamp: payload.Time.Format(time.RFC3339),
+		Footer:    &discordFooter{Text: "LoL Kind Bot"},
+	}
+	if payload.Message != "" {
+		embed.Description = "> " + payload.Message
+	}
+
+	summary := payload.Summary
+	if summary == nil {
+		return embed
+	}
+
+	result := "Defeat"
+	embed.Color = discordColorLoss
+	if summary.MyTeamWon() {
+		result = "Victory"
+		embed.Color = discordColorWin
+	}
+	mode := summary.QueueType
+	if mode == "" {
+		mode = summary.GameMode
+	}
+	embed.Title = fmt.Sprintf("%s · %.0f min", result, summary.GameDurationMinutes)
+	if mode != "" {
+		embed.Title += " · " + mode
+	}
+
+	if me := summary.Me(); me != nil {
+		value := fmt.Sprintf("%s %d/%d/%d", me.Champion, me.K, me.D, me.A)
+		if len(me.Tags) > 0 {
+			value += " (" + strings.Join(me.Tags, ", ") + ")"
+		}
+		embed.Fields = append(embed.Fields, discordField{Name: "Me", Value: value})
+	}
+
+	highlights := achievementLines(summary.Achievements)
+	if summary.IsComeback {
+		highlights = append(highlights, "Comeback win")
+	}
+	if len(highlights) > 0 {
+		embed.Fields = append(embed.Fields, discordField{Name: "Highlights", Value: strings.Join(highlights, "\n")})
+	}
+
+	if len(payload.Messages) > 1 {
+		embed.Fields = append(embed.Fields, discordField{
+			Name:  "Other suggestions",
+			Value: strings.Join(payload.Messages[1:], "\n"),
+		})
+	}
+
+	return embed
+}
+
+// achievementLines turns the explicit game achievements into readable lines
+func achievementLines(a analyzer.GameAchievements) []string {
+	var lines []string
+	add := func(label, champion string) {
+		if champion != "" {
+			lines = append(lines, fmt.Sprintf("%s: **%s**", label, champion))
+		}
+	}
+	add("Most damage", a.HighestDamageInGame)
+	add("Most healing & shielding", a.MostHealingShielding)
+	add("Best vision", a.HighestVisionInGame)
+	add("Most CC", a.MostCCInGame)
+	add("Frontline", a.MostTankingInGame)
+	return lines
+}
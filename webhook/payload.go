@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"lol-kind-bot/analyzer"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Webhook event names (used in WebhookSettings.Events)
+const (
+	EventGameEnd       = "gameEnd"
+	EventMessages      = "messages"
+	EventGoldMilestone = "goldMilestone"
+)
+
+// Payload is what a webhook delivers. It is the body of "json" webhooks and the data for templates.
+type Payload struct {
+	Event    string                `json:"event"`
+	Time     time.Time             `json:"time"`
+	Summary  *analyzer.GameSummary `json:"summary,omitempty"`
+	Messages []string              `json:"messages,omitempty"`
+	Message  string                `json:"message,omitempty"` // The chosen (first) message
+	Gold     int                   `json:"gold,omitempty"`
+	Item     string                `json:"item,omitempty"` // The item a gold milestone is about
+}
+
+// templateFuncs are available in WebhookSettings.Template
+var templateFuncs = template.FuncMap{
+	// json encodes a value as JSON, so strings are safely quoted and escaped
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": strings.Join,
+}
+
+// renderTemplate executes a user template against the payload and checks the result is valid JSON
+func renderTemplate(text string, payload Payload) ([]byte, error) {
+	tmpl, err := template.New("webhook").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, payload); err != nil {
+		return nil, fmt.Errorf("failed to execute webhook template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("webhook template did not produce valid JSON: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalPayload encodes the payload as the body of a "json" webhook
+func marshalPayload(payload Payload) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,221 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/app"
+	"lol-kind-bot/config"
+	"lol-kind-bot/monitor"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultBackoff    = 1 * time.Second
+	maxBackoff        = 30 * time.Second
+)
+
+// Dispatcher delivers bot events to the configured webhooks. Every delivery runs in its own
+// goroutine, so slow or failing endpoints never hold up the bot.
+type Dispatcher struct {
+	mu      sync.RWMutex
+	hooks   []config.WebhookSettings
+	client  *http.Client
+	backoff time.Duration // First retry delay, doubled on each retry
+	wg      sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher for the given webhooks
+func NewDispatcher(hooks []config.WebhookSettings) *Dispatcher {
+	return &Dispatcher{
+		hooks:   hooks,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		backoff: defaultBackoff,
+	}
+}
+
+// Run turns bot events into webhook deliveries until the channel is closed
+func (d *Dispatcher) Run(events <-chan app.Event) {
+	var lastSummary *analyzer.GameSummary
+
+	for event := range events {
+		payload := Payload{Time: event.Time}
+
+		switch event.Type {
+		case app.EventGameSummary:
+			summary, ok := event.Data.(*analyzer.GameSummary)
+			if !ok {
+				continue
+			}
+			lastSummary = summary
+			payload.Event = EventGameEnd
+			payload.Summary = summary
+		case app.EventMessages:
+			messages, ok := event.Data.([]string)
+			if !ok || len(messages) == 0 {
+				continue
+			}
+			payload.Event = EventMessages
+			payload.Summary = lastSummary // Messages always follow their game's summary
+			payload.Messages = messages
+			payload.Message = messages[0]
+		case app.EventConfigChanged:
+			if cfg, ok := event.Data.(*config.Config); ok {
+				d.SetHooks(cfg.Webhooks)
+			}
+			continue
+		case app.EventGoldMilestone:
+			milestone, ok := event.Data.(monitor.GoldMilestone)
+			if !ok {
+				continue
+			}
+			payload.Event = EventGoldMilestone
+			payload.Gold = milestone.Gold
+			payload.Item = milestone.Item
+			payload.Message = milestone.Text
+		default:
+			continue
+		}
+
+		d.Send(payload)
+	}
+}
+
+// SetHooks replaces the configured webhooks (deliveries already in flight are unaffected)
+func (d *Dispatcher) SetHooks(hooks []config.WebhookSettings) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.hooks = hooks
+}
+
+// Send delivers payload asynchronously to every webhook subscribed to its event
+func (d *Dispatcher) Send(payload Payload) {
+	d.mu.RLock()
+	hooks := d.hooks
+	d.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if !subscribed(hook, payload.Event) {
+			continue
+		}
+
+		hook := hook
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			if err := d.deliver(hook, payload); err != nil {
+				log.Printf("[WEBHOOK] %s: %v", hookName(hook), err)
+			}
+		}()
+	}
+}
+
+// Wait blocks until all in-flight deliveries have finished
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// deliver builds the body for hook and POSTs it, retrying with exponential backoff
+func (d *Dispatcher) deliver(hook config.WebhookSettings, payload Payload) error {
+	body, err := buildBody(hook, payload)
+	if err != nil {
+		return err
+	}
+
+	maxRetries := hook.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	backoff := d.backoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := d.post(hook.URL, body)
+		if err == nil {
+			log.Printf("[WEBHOOK] %s: delivered %s event", hookName(hook), payload.Event)
+			return nil
+		}
+		if retryAfter < 0 || attempt >= maxRetries {
+			return fmt.Errorf("failed to deliver %s event after %d attempt(s): %w", payload.Event, attempt+1, err)
+		}
+
+		wait := backoff
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		log.Printf("[WEBHOOK] %s: %v, retrying in %v", hookName(hook), err, wait)
+		time.Sleep(wait)
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// post sends one request. On failure retryAfter is the server-requested delay (0 if none),
+// or negative when the error is permanent and must not be retried.
+func (d *Dispatcher) post(url string, body []byte) (retryAfter time.Duration, err error) {
+	resp, err := d.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+
+	err = fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		if seconds, convErr := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); convErr == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds * float64(time.Second))
+		}
+		if retryAfter > maxBackoff {
+			retryAfter = maxBackoff
+		}
+		return retryAfter, err
+	}
+	return -1, err
+}
+
+// buildBody renders the request body using the hook's template or format
+func buildBody(hook config.WebhookSettings, payload Payload) ([]byte, error) {
+	if hook.Template != "" {
+		return renderTemplate(hook.Template, payload)
+	}
+
+	switch hook.Format {
+	case "", "json":
+		return marshalPayload(payload)
+	case "discord":
+		return formatDiscord(payload)
+	default:
+		return nil, fmt.Errorf("unknown webhook format %q (expected \"json\" or \"discord\")", hook.Format)
+	}
+}
+
+func subscribed(hook config.WebhookSettings, event string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, e := range hook.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func hookName(hook config.WebhookSettings) string {
+	if hook.Name != "" {
+		return hook.Name
+	}
+	return hook.URL
+}
@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"lol-kind-bot/analyzer"
+	"lol-kind-bot/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// standIn is a local webhook endpoint that answers with the given statuses in turn (the last
+// one repeats) and records every request body
+type standIn struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	header   http.Header
+	bodies   []string
+}
+
+func newStandIn(t *testing.T, statuses ...int) *standIn {
+	s := &standIn{statuses: statuses, header: http.Header{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(body))
+		status := s.statuses[min(len(s.bodies), len(s.statuses))-1]
+		for key, values := range s.header {
+			w.Header()[key] = values
+		}
+		s.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *standIn) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+// newTestDispatcher returns a dispatcher that retries without the production backoff
+func newTestDispatcher(hooks ...config.WebhookSettings) *Dispatcher {
+	d := NewDispatcher(hooks)
+	d.backoff = time.Millisecond
+	return d
+}
+
+func TestRetriesServerErrors(t *testing.T) {
+	server := newStandIn(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK)
+	d := newTestDispatcher(config.WebhookSettings{URL: server.URL})
+
+	d.Send(Payload{Event: EventMessages, Message: "gg"})
+	d.Wait()
+
+	if got := len(server.requests()); got != 3 {
+		t.Fatalf("expected 3 attempts (2 failures, then success), got %d", got)
+	}
+}
+
+func TestGivesUpAfterMaxRetries(t *testing.T) {
+	server := newStandIn(t, http.StatusServiceUnavailable)
+	d := newTestDispatcher(config.WebhookSettings{URL: server.URL, MaxRetries: 2})
+
+	d.Send(Payload{Event: EventMessages, Message: "gg"})
+	d.Wait()
+
+	if got := len(server.requests()); got != 3 {
+		t.Fatalf("expected 3 attempts (1 + 2 retries), got %d", got)
+	}
+}
+
+func TestHonoursRetryAfter(t *testing.T) {
+	server := newStandIn(t, http.StatusTooManyRequests, http.StatusOK)
+	server.header.Set("Retry-After", "0.2")
+	d := newTestDispatcher(config.WebhookSettings{URL: server.URL})
+
+	start := time.Now()
+	d.Send(Payload{Event: EventMessages, Message: "gg"})
+	d.Wait()
+
+	if got := len(server.requests()); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("retried after %v, before the requested Retry-After of 200ms", elapsed)
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	server := newStandIn(t, http.StatusBadRequest, http.StatusOK)
+	d := newTestDispatcher(config.WebhookSettings{URL: server.URL})
+
+	d.Send(Payload{Event: EventMessages, Message: "gg"})
+	d.Wait()
+
+	if got := len(server.requests()); got != 1 {
+		t.Fatalf("expected a 4xx not to be retried, got %d attempts", got)
+	}
+}
+
+func TestEventsFilter(t *testing.T) {
+	gameEnd := newStandIn(t, http.StatusOK)
+	all := newStandIn(t, http.StatusOK)
+	d := newTestDispatcher(
+		config.WebhookSettings{URL: gameEnd.URL, Events: []string{EventGameEnd}},
+		config.WebhookSettings{URL: all.URL},
+	)
+
+	d.Send(Payload{Event: EventGoldMilestone, Gold: 1300})
+	d.Send(Payload{Event: EventGameEnd})
+	d.Wait()
+
+	requests := gameEnd.requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected only the gameEnd event, got %d requests", len(requests))
+	}
+	var payload Payload
+	if err := json.Unmarshal([]byte(requests[0]), &payload); err != nil {
+		t.Fatalf("body is not a JSON payload: %v", err)
+	}
+	if payload.Event != EventGameEnd {
+		t.Fatalf("expected event %q, got %q", EventGameEnd, payload.Event)
+	}
+	if got := len(all.requests()); got != 2 {
+		t.Fatalf("expected a hook without events to get both, got %d", got)
+	}
+}
+
+func TestDiscordBody(t *testing.T) {
+	server := newStandIn(t, http.StatusOK)
+	d := newTestDispatcher(config.WebhookSettings{URL: server.URL, Format: "discord"})
+
+	summary := &analyzer.GameSummary{MyTeam: "BLUE", WinningTeam: "BLUE", GameDurationMinutes: 31, QueueType: "ARAM"}
+	d.Send(Payload{Event: EventMessages, Summary: summary, Messages: []string{"gg wp", "nice game"}, Message: "gg wp"})
+	d.Wait()
+
+	requests := server.requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	var msg discordMessage
+	if err := json.Unmarshal([]byte(requests[0]), &msg); err != nil {
+		t.Fatalf("body is not a Discord message: %v", err)
+	}
+	if len(msg.Embeds) != 1 {
+		t.Fatalf("expected 1 embed, got %d", len(msg.Embeds))
+	}
+	embed := msg.Embeds[0]
+	if embed.Title != "Victory · 31 min · ARAM" {
+		t.Errorf("unexpected title %q", embed.Title)
+	}
+	if embed.Color != discordColorWin {
+		t.Errorf("expected the win color, got %#x", embed.Color)
+	}
+	if embed.Description != "> gg wp" {
+		t.Errorf("unexpected description %q", embed.Description)
+	}
+	if n := len(embed.Fields); n == 0 || embed.Fields[n-1].Value != "nice game" {
+		t.Errorf("expected the other suggestions as the last field, got %+v", embed.Fields)
+	}
+}
+
+func TestDiscordGoldMilestone(t *testing.T) {
+	body, err := buildBody(config.WebhookSettings{Format: "discord"}, Payload{Event: EventGoldMilestone, Gold: 3100, Message: "You can afford Infinity Edge"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg discordMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Content != "💰 You can afford Infinity Edge" || len(msg.Embeds) != 0 {
+		t.Fatalf("unexpected gold milestone message %+v", msg)
+	}
+}
+
+func TestTemplateBody(t *testing.T) {
+	server := newStandIn(t, http.StatusOK)
+	d := newTestDispatcher(config.WebhookSettings{
+		URL:      server.URL,
+		Format:   "discord", // The template wins
+		Template: `{"text": {{json .Message}}, "all": {{json (join .Messages " | ")}}}`,
+	})
+
+	d.Send(Payload{Event: EventMessages, Messages: []string{`say "gg"`, "wp"}, Message: `say "gg"`})
+	d.Wait()
+
+	requests := server.requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	var body map[string]string
+	if err := json.Unmarshal([]byte(requests[0]), &body); err != nil {
+		t.Fatalf("template body is not valid JSON: %v (%s)", err, requests[0])
+	}
+	if body["text"] != `say "gg"` || body["all"] != `say "gg" | wp` {
+		t.Fatalf("unexpected template body %v", body)
+	}
+}
+
+func TestTemplateMustProduceJSON(t *testing.T) {
+	_, err := buildBody(config.WebhookSettings{Template: `text: {{.Message}}`}, Payload{Message: "gg"})
+	if err == nil || !strings.Contains(err.Error(), "valid JSON") {
+		t.Fatalf("expected an invalid JSON error, got %v", err)
+	}
+}